@@ -0,0 +1,80 @@
+package arangogo
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type StatisticsDistribution struct {
+	Sum    float64 `json:"sum"`
+	Count  int64   `json:"count"`
+	Counts []int64 `json:"counts"`
+}
+
+type SystemStatistics struct {
+	MinorPageFaults int64   `json:"minorPageFaults"`
+	MajorPageFaults int64   `json:"majorPageFaults"`
+	UserTime        float64 `json:"userTime"`
+	SystemTime      float64 `json:"systemTime"`
+	NumberOfThreads int64   `json:"numberOfThreads"`
+	ResidentSize    int64   `json:"residentSize"`
+	VirtualSize     int64   `json:"virtualSize"`
+}
+
+type ClientStatistics struct {
+	HTTPConnections int64                  `json:"httpConnections"`
+	ConnectionTime  StatisticsDistribution `json:"connectionTime"`
+	TotalTime       StatisticsDistribution `json:"totalTime"`
+	RequestTime     StatisticsDistribution `json:"requestTime"`
+	QueueTime       StatisticsDistribution `json:"queueTime"`
+	IOTime          StatisticsDistribution `json:"ioTime"`
+	BytesSent       StatisticsDistribution `json:"bytesSent"`
+	BytesReceived   StatisticsDistribution `json:"bytesReceived"`
+}
+
+type HTTPStatistics struct {
+	RequestsTotal   int64 `json:"requestsTotal"`
+	RequestsAsync   int64 `json:"requestsAsync"`
+	RequestsGet     int64 `json:"requestsGet"`
+	RequestsHead    int64 `json:"requestsHead"`
+	RequestsPost    int64 `json:"requestsPost"`
+	RequestsPut     int64 `json:"requestsPut"`
+	RequestsPatch   int64 `json:"requestsPatch"`
+	RequestsDelete  int64 `json:"requestsDelete"`
+	RequestsOptions int64 `json:"requestsOptions"`
+	RequestsOther   int64 `json:"requestsOther"`
+}
+
+type ServerStatisticsServer struct {
+	Uptime         float64 `json:"uptime"`
+	PhysicalMemory int64   `json:"physicalMemory"`
+}
+
+type ServerStatistics struct {
+	Time    float64                `json:"time"`
+	Enabled bool                   `json:"enabled"`
+	System  SystemStatistics       `json:"system"`
+	Client  ClientStatistics       `json:"client"`
+	HTTP    HTTPStatistics         `json:"http"`
+	Server  ServerStatisticsServer `json:"server"`
+}
+
+func (c *Connection) GetServerStatistics() (stats ServerStatistics, rc int, err error) {
+	rc, _, err = c.send(http.MethodGet, "/_admin/statistics", nil, nil, &stats)
+	if err != nil {
+		return ServerStatistics{}, rc, fmt.Errorf("failed to get server statistics: %v", err)
+	}
+	return stats, rc, nil
+}
+
+// GetServerMetrics returns the server metrics in the Prometheus text format.
+func (c *Connection) GetServerMetrics() (metrics []byte, rc int, err error) {
+	if err := c.requireVersion("/_admin/metrics", 30600); err != nil {
+		return nil, 0, err
+	}
+	rc, metrics, err = c.sendRaw(http.MethodGet, "/_admin/metrics", nil)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to get server metrics: %v", err)
+	}
+	return metrics, rc, nil
+}
@@ -0,0 +1,64 @@
+// Package arangomock provides mocks of the client interfaces of arangogo.
+//
+// Each mock records the calls of its methods and calls the function field
+// named after the method, like ReadDocumentFunc for ReadDocument, to return
+// programmed results. Methods whose function is not set return zero values.
+// Client mocks all the client interfaces with one recorder, and Batch is
+// returned from NewBatchFunc to test code sending batches.
+//
+//	m := &arangomock.DocumentClient{
+//		ReadDocumentFunc: func(dbName, documentHandle string, config *ara.ReadDocumentConfig, documentPtr interface{}) (int, error) {
+//			return http.StatusNotFound, errors.New("not found")
+//		},
+//	}
+//	... // run the code under test with m
+//	calls := m.CallsTo("ReadDocument")
+package arangomock
+
+import "sync"
+
+//go:generate go run gen.go
+
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the calls of a mock. It is embedded in the mocks.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	r.mu.Unlock()
+}
+
+// Calls returns the calls in the order they were made.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls of the method.
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.calls = nil
+	r.mu.Unlock()
+}
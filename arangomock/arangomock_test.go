@@ -0,0 +1,99 @@
+package arangomock_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangomock"
+)
+
+// createUsers is an example of code under test. It creates the users in a
+// batch and removes the old user asynchronously.
+func createUsers(c ara.Client, names []string, oldKey string) ([]error, error) {
+	errs := make([]error, len(names))
+	b := c.NewBatch("test")
+	for i, name := range names {
+		i, name := i, name
+		b.Add(func(c ara.Client) {
+			_, _, errs[i] = c.CreateDocument("test", "users", map[string]interface{}{"_key": name}, nil, nil, nil)
+		})
+	}
+	if _, err := b.Send(); err != nil {
+		return nil, err
+	}
+	if _, _, err := c.Async(ara.AsyncModeFireAndForget, nil).RemoveDocument("test", "users", oldKey, nil, nil); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+func TestClient(t *testing.T) {
+	conflict := errors.New("conflict")
+	ac := &arangomock.Client{}
+	batch := &arangomock.Batch{}
+	c := &arangomock.Client{
+		NewBatchFunc: func(dbName string) ara.Batch {
+			return batch
+		},
+		AsyncFunc: func(mode string, job *ara.AsyncJob) ara.Client {
+			return ac
+		},
+	}
+	// The operations are called with the client when the batch is sent.
+	var ops []func(c ara.Client)
+	batch.AddFunc = func(op func(c ara.Client)) {
+		ops = append(ops, op)
+	}
+	part := &arangomock.Client{
+		CreateDocumentFunc: func(dbName, collName string, data interface{}, config *ara.CreateDocumentConfig, oldDocPtr, newDocPtr interface{}) (ara.Document, int, error) {
+			if data.(map[string]interface{})["_key"] == "bob" {
+				return ara.Document{}, http.StatusConflict, conflict
+			}
+			return ara.Document{}, http.StatusAccepted, nil
+		},
+	}
+	batch.SendFunc = func() (int, error) {
+		for _, op := range ops {
+			op(part)
+		}
+		return http.StatusOK, nil
+	}
+
+	errs, err := createUsers(c, []string{"alice", "bob"}, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs[0] != nil || errs[1] != conflict {
+		t.Errorf("errs = %v", errs)
+	}
+
+	if calls := c.Calls(); len(calls) != 2 || calls[0].Method != "NewBatch" || calls[1].Method != "Async" {
+		t.Errorf("calls = %v", calls)
+	}
+	if calls := batch.CallsTo("Add"); len(calls) != 2 || len(batch.CallsTo("Send")) != 1 {
+		t.Errorf("batch calls = %v", batch.Calls())
+	}
+	if calls := part.CallsTo("CreateDocument"); len(calls) != 2 {
+		t.Errorf("part calls = %v", part.Calls())
+	}
+	calls := ac.CallsTo("RemoveDocument")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args[:3]) != "[test users carol]" {
+		t.Errorf("async calls = %v", ac.Calls())
+	}
+}
+
+func TestZeroValues(t *testing.T) {
+	var c arangomock.Client
+	if b := c.NewBatch("test"); b != nil {
+		t.Errorf("NewBatch = %v, want nil", b)
+	}
+	if rc, err := c.ReadDocument("test", "users/alice", nil, nil); rc != 0 || err != nil {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+	if calls := c.Calls(); len(calls) != 2 {
+		t.Errorf("calls = %v", calls)
+	}
+}
@@ -0,0 +1,214 @@
+//go:build ignore
+
+// gen.go generates mocks.go from the client interfaces in ../client.go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+const (
+	source = "../client.go"
+	output = "mocks.go"
+)
+
+func main() {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, source, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var names []string
+	interfaces := make(map[string]*ast.InterfaceType)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			names = append(names, ts.Name.Name)
+			interfaces[ts.Name.Name] = it
+		}
+	}
+
+	var body bytes.Buffer
+	for _, name := range names {
+		writeMock(&body, name, interfaces)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\npackage arangomock\n\nimport (\n")
+	for _, spec := range f.Imports {
+		fmt.Fprintf(&buf, "\t%s\n", spec.Path.Value)
+	}
+	buf.WriteString("\n\tara \"github.com/hnakamur/arangogo\"\n)\n")
+	buf.Write(body.Bytes())
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format generated code: %v\n%s", err, buf.Bytes())
+	}
+	err = ioutil.WriteFile(output, b, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type method struct {
+	name    string
+	params  []string
+	types   []string
+	results []string
+}
+
+// collectMethods returns the methods of an interface including the methods of
+// the interfaces embedded in it, which must be in the same file.
+func collectMethods(it *ast.InterfaceType, interfaces map[string]*ast.InterfaceType) []method {
+	var methods []method
+	for _, field := range it.Methods.List {
+		if len(field.Names) == 0 {
+			embedded, ok := interfaces[field.Type.(*ast.Ident).Name]
+			if !ok {
+				log.Fatalf("unknown embedded interface: %s", field.Type.(*ast.Ident).Name)
+			}
+			methods = append(methods, collectMethods(embedded, interfaces)...)
+			continue
+		}
+		ft := field.Type.(*ast.FuncType)
+		m := method{name: field.Names[0].Name}
+		for _, p := range ft.Params.List {
+			typ := typeString(p.Type)
+			for _, n := range p.Names {
+				m.params = append(m.params, n.Name)
+				m.types = append(m.types, typ)
+			}
+		}
+		if ft.Results == nil {
+			methods = append(methods, m)
+			continue
+		}
+		for _, r := range ft.Results.List {
+			typ := typeString(r.Type)
+			n := len(r.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				m.results = append(m.results, typ)
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// writeMock writes the mock of an interface. The methods of embedded
+// interfaces are flattened so that one Recorder records all the calls.
+func writeMock(buf *bytes.Buffer, name string, interfaces map[string]*ast.InterfaceType) {
+	methods := collectMethods(interfaces[name], interfaces)
+
+	fmt.Fprintf(buf, "\n// %s is a mock of arangogo.%s.\n", name, name)
+	fmt.Fprintf(buf, "type %s struct {\n\tRecorder\n\n", name)
+	for _, m := range methods {
+		fmt.Fprintf(buf, "\t%sFunc func(%s) (%s)\n", m.name, m.paramList(), strings.Join(m.results, ", "))
+	}
+	buf.WriteString("}\n")
+	fmt.Fprintf(buf, "\nvar _ ara.%s = (*%s)(nil)\n", name, name)
+
+	for _, m := range methods {
+		named := make([]string, len(m.results))
+		for i, r := range m.results {
+			named[i] = fmt.Sprintf("r%d %s", i, r)
+		}
+		fmt.Fprintf(buf, "\nfunc (m *%s) %s(%s) (%s) {\n", name, m.name, m.paramList(), strings.Join(named, ", "))
+		args := strings.Join(m.params, ", ")
+		if args == "" {
+			fmt.Fprintf(buf, "\tm.record(%q)\n", m.name)
+		} else {
+			fmt.Fprintf(buf, "\tm.record(%q, %s)\n", m.name, args)
+		}
+		if len(m.results) == 0 {
+			fmt.Fprintf(buf, "\tif m.%sFunc != nil {\n\t\tm.%sFunc(%s)\n\t}\n}\n", m.name, m.name, args)
+			continue
+		}
+		fmt.Fprintf(buf, "\tif m.%sFunc != nil {\n\t\treturn m.%sFunc(%s)\n\t}\n\treturn\n}\n", m.name, m.name, args)
+	}
+}
+
+func (m method) paramList() string {
+	params := make([]string, len(m.params))
+	for i := range m.params {
+		params[i] = m.params[i] + " " + m.types[i]
+	}
+	return strings.Join(params, ", ")
+}
+
+// typeString prints the type qualifying the exported identifiers with the
+// ara package. The type is rebuilt without positions so that it is printed
+// in a line.
+func typeString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	err := printer.Fprint(&buf, token.NewFileSet(), qualify(expr))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
+
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("ara"), Sel: ast.NewIdent(e.Name)}
+		}
+		return ast.NewIdent(e.Name)
+	case *ast.SelectorExpr:
+		// Types of other packages are imported like in the source.
+		return &ast.SelectorExpr{X: ast.NewIdent(e.X.(*ast.Ident).Name), Sel: ast.NewIdent(e.Sel.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params), Results: qualifyFields(e.Results)}
+	case *ast.InterfaceType:
+		if len(e.Methods.List) == 0 {
+			// An identifier prints the empty interface in a line.
+			return ast.NewIdent("interface{}")
+		}
+	}
+	log.Fatalf("unsupported type: %T", expr)
+	return nil
+}
+
+func qualifyFields(fl *ast.FieldList) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range fl.List {
+		var names []*ast.Ident
+		for _, n := range f.Names {
+			names = append(names, ast.NewIdent(n.Name))
+		}
+		out.List = append(out.List, &ast.Field{Names: names, Type: qualify(f.Type)})
+	}
+	return out
+}
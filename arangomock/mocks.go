@@ -0,0 +1,1348 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package arangomock
+
+import (
+	"context"
+
+	ara "github.com/hnakamur/arangogo"
+)
+
+// DatabaseClient is a mock of arangogo.DatabaseClient.
+type DatabaseClient struct {
+	Recorder
+
+	CreateDatabaseFunc    func(config ara.CreateDatabaseConfig) error
+	DropDatabaseFunc      func(name string) error
+	ListDatabasesFunc     func() ([]string, error)
+	ListUserDatabasesFunc func() ([]string, error)
+}
+
+var _ ara.DatabaseClient = (*DatabaseClient)(nil)
+
+func (m *DatabaseClient) CreateDatabase(config ara.CreateDatabaseConfig) (r0 error) {
+	m.record("CreateDatabase", config)
+	if m.CreateDatabaseFunc != nil {
+		return m.CreateDatabaseFunc(config)
+	}
+	return
+}
+
+func (m *DatabaseClient) DropDatabase(name string) (r0 error) {
+	m.record("DropDatabase", name)
+	if m.DropDatabaseFunc != nil {
+		return m.DropDatabaseFunc(name)
+	}
+	return
+}
+
+func (m *DatabaseClient) ListDatabases() (r0 []string, r1 error) {
+	m.record("ListDatabases")
+	if m.ListDatabasesFunc != nil {
+		return m.ListDatabasesFunc()
+	}
+	return
+}
+
+func (m *DatabaseClient) ListUserDatabases() (r0 []string, r1 error) {
+	m.record("ListUserDatabases")
+	if m.ListUserDatabasesFunc != nil {
+		return m.ListUserDatabasesFunc()
+	}
+	return
+}
+
+// CollectionClient is a mock of arangogo.CollectionClient.
+type CollectionClient struct {
+	Recorder
+
+	CreateCollectionFunc   func(dbName string, config ara.CreateCollectionConfig) (ara.CreateCollectionResult, int, error)
+	DropCollectionFunc     func(dbName string, collectionName string) (ara.DropCollectionResult, int, error)
+	ListCollectionsFunc    func(dbName string) ([]ara.Collection, int, error)
+	TruncateCollectionFunc func(dbName string, collectionName string) (ara.Collection, int, error)
+}
+
+var _ ara.CollectionClient = (*CollectionClient)(nil)
+
+func (m *CollectionClient) CreateCollection(dbName string, config ara.CreateCollectionConfig) (r0 ara.CreateCollectionResult, r1 int, r2 error) {
+	m.record("CreateCollection", dbName, config)
+	if m.CreateCollectionFunc != nil {
+		return m.CreateCollectionFunc(dbName, config)
+	}
+	return
+}
+
+func (m *CollectionClient) DropCollection(dbName string, collectionName string) (r0 ara.DropCollectionResult, r1 int, r2 error) {
+	m.record("DropCollection", dbName, collectionName)
+	if m.DropCollectionFunc != nil {
+		return m.DropCollectionFunc(dbName, collectionName)
+	}
+	return
+}
+
+func (m *CollectionClient) ListCollections(dbName string) (r0 []ara.Collection, r1 int, r2 error) {
+	m.record("ListCollections", dbName)
+	if m.ListCollectionsFunc != nil {
+		return m.ListCollectionsFunc(dbName)
+	}
+	return
+}
+
+func (m *CollectionClient) TruncateCollection(dbName string, collectionName string) (r0 ara.Collection, r1 int, r2 error) {
+	m.record("TruncateCollection", dbName, collectionName)
+	if m.TruncateCollectionFunc != nil {
+		return m.TruncateCollectionFunc(dbName, collectionName)
+	}
+	return
+}
+
+// DocumentClient is a mock of arangogo.DocumentClient.
+type DocumentClient struct {
+	Recorder
+
+	ReadDocumentFunc       func(dbName string, documentHandle string, config *ara.ReadDocumentConfig, documentPtr interface{}) (int, error)
+	ReadDocumentHeaderFunc func(dbName string, documentHandle string, config *ara.ReadDocumentHeaderConfig) (string, int, error)
+	ListAllDocumentsFunc   func(dbName string, config ara.ListAllDocumentsConfig) (*ara.Cursor, int, error)
+	CreateDocumentFunc     func(dbName string, collName string, data interface{}, config *ara.CreateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (ara.Document, int, error)
+	CreateDocumentsFunc    func(dbName string, collName string, data interface{}, config *ara.CreateDocumentsConfig, oldDocsPtr interface{}, newDocsPtr interface{}) (ara.CreateDocumentsResult, int, error)
+	ReplaceDocumentFunc    func(dbName string, docHandle string, data interface{}, config *ara.ReplaceDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (ara.ReplaceDocumentResult, int, error)
+	UpdateDocumentFunc     func(dbName string, docHandle string, data interface{}, config *ara.UpdateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (ara.UpdateDocumentResult, int, error)
+	RemoveDocumentFunc     func(dbName string, collName string, key string, config *ara.RemoveDocumentConfig, docPtr interface{}) (ara.Document, int, error)
+	UpdateWithRetryFunc    func(dbName string, docHandle string, docPtr interface{}, mutate func(docPtr interface{}) error, config *ara.UpdateWithRetryConfig) (ara.ReplaceDocumentResult, int, error)
+}
+
+var _ ara.DocumentClient = (*DocumentClient)(nil)
+
+func (m *DocumentClient) ReadDocument(dbName string, documentHandle string, config *ara.ReadDocumentConfig, documentPtr interface{}) (r0 int, r1 error) {
+	m.record("ReadDocument", dbName, documentHandle, config, documentPtr)
+	if m.ReadDocumentFunc != nil {
+		return m.ReadDocumentFunc(dbName, documentHandle, config, documentPtr)
+	}
+	return
+}
+
+func (m *DocumentClient) ReadDocumentHeader(dbName string, documentHandle string, config *ara.ReadDocumentHeaderConfig) (r0 string, r1 int, r2 error) {
+	m.record("ReadDocumentHeader", dbName, documentHandle, config)
+	if m.ReadDocumentHeaderFunc != nil {
+		return m.ReadDocumentHeaderFunc(dbName, documentHandle, config)
+	}
+	return
+}
+
+func (m *DocumentClient) ListAllDocuments(dbName string, config ara.ListAllDocumentsConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("ListAllDocuments", dbName, config)
+	if m.ListAllDocumentsFunc != nil {
+		return m.ListAllDocumentsFunc(dbName, config)
+	}
+	return
+}
+
+func (m *DocumentClient) CreateDocument(dbName string, collName string, data interface{}, config *ara.CreateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (r0 ara.Document, r1 int, r2 error) {
+	m.record("CreateDocument", dbName, collName, data, config, oldDocPtr, newDocPtr)
+	if m.CreateDocumentFunc != nil {
+		return m.CreateDocumentFunc(dbName, collName, data, config, oldDocPtr, newDocPtr)
+	}
+	return
+}
+
+func (m *DocumentClient) CreateDocuments(dbName string, collName string, data interface{}, config *ara.CreateDocumentsConfig, oldDocsPtr interface{}, newDocsPtr interface{}) (r0 ara.CreateDocumentsResult, r1 int, r2 error) {
+	m.record("CreateDocuments", dbName, collName, data, config, oldDocsPtr, newDocsPtr)
+	if m.CreateDocumentsFunc != nil {
+		return m.CreateDocumentsFunc(dbName, collName, data, config, oldDocsPtr, newDocsPtr)
+	}
+	return
+}
+
+func (m *DocumentClient) ReplaceDocument(dbName string, docHandle string, data interface{}, config *ara.ReplaceDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (r0 ara.ReplaceDocumentResult, r1 int, r2 error) {
+	m.record("ReplaceDocument", dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	if m.ReplaceDocumentFunc != nil {
+		return m.ReplaceDocumentFunc(dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	}
+	return
+}
+
+func (m *DocumentClient) UpdateDocument(dbName string, docHandle string, data interface{}, config *ara.UpdateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (r0 ara.UpdateDocumentResult, r1 int, r2 error) {
+	m.record("UpdateDocument", dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	if m.UpdateDocumentFunc != nil {
+		return m.UpdateDocumentFunc(dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	}
+	return
+}
+
+func (m *DocumentClient) RemoveDocument(dbName string, collName string, key string, config *ara.RemoveDocumentConfig, docPtr interface{}) (r0 ara.Document, r1 int, r2 error) {
+	m.record("RemoveDocument", dbName, collName, key, config, docPtr)
+	if m.RemoveDocumentFunc != nil {
+		return m.RemoveDocumentFunc(dbName, collName, key, config, docPtr)
+	}
+	return
+}
+
+func (m *DocumentClient) UpdateWithRetry(dbName string, docHandle string, docPtr interface{}, mutate func(docPtr interface{}) error, config *ara.UpdateWithRetryConfig) (r0 ara.ReplaceDocumentResult, r1 int, r2 error) {
+	m.record("UpdateWithRetry", dbName, docHandle, docPtr, mutate, config)
+	if m.UpdateWithRetryFunc != nil {
+		return m.UpdateWithRetryFunc(dbName, docHandle, docPtr, mutate, config)
+	}
+	return
+}
+
+// GraphClient is a mock of arangogo.GraphClient.
+type GraphClient struct {
+	Recorder
+
+	ListGraphsFunc             func(dbName string, graphsPtr interface{}) (int, error)
+	CreateGraphFunc            func(dbName string, data interface{}) (ara.Graph, int, error)
+	GetGraphFunc               func(dbName string, graphName string) (ara.Graph, int, error)
+	DropGraphFunc              func(dbName string, graphName string, config *ara.DropGraphConfig) (bool, int, error)
+	EnsureGraphFunc            func(dbName string, definition ara.CreateGraphConfig, config *ara.EnsureGraphConfig) (ara.EnsureGraphResult, int, error)
+	ListVertexCollectionsFunc  func(dbName string, graphName string) ([]string, int, error)
+	AddVertexCollectionFunc    func(dbName string, graphName string, collectionName string, config *ara.AddVertexCollectionConfig) (ara.AddVertexCollectionResult, int, error)
+	RemoveVertexCollectionFunc func(dbName string, graphName string, collectionName string, config *ara.RemoveVertexCollectionConfig) (ara.RemoveVertexCollectionResult, int, error)
+	ListEdgeDefinitionsFunc    func(dbName string, graphName string) ([]string, int, error)
+	AddEdgeDefinitionFunc      func(dbName string, graphName string, edgeDefinition interface{}) (ara.AddEdgeDefinitionResult, int, error)
+	RemoveEdgeDefinitionFunc   func(dbName string, graphName string, definitionName string) (ara.RemoveEdgeDefinitionResult, int, error)
+	ReplaceEdgeDefinitionFunc  func(dbName string, graphName string, definitionName string, edgeDefinition ara.EdgeDefinition, config *ara.ReplaceEdgeDefinitionConfig) (ara.Graph, int, error)
+	CreateVertexFunc           func(dbName string, graphName string, collName string, data interface{}, config *ara.CreateVertexConfig, newVertexPtr interface{}) (ara.CreateVertexResult, int, error)
+	GetVertexFunc              func(dbName string, graphName string, collName string, vertexKey string, config *ara.GetVertexConfig, vertexPtr interface{}) (ara.GetVertexResult, int, error)
+	ModifyVertexFunc           func(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ModifyVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (ara.ModifyVertexResult, int, error)
+	ReplaceVertexFunc          func(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ReplaceVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (ara.ReplaceVertexResult, int, error)
+	RemoveVertexFunc           func(dbName string, graphName string, collName string, vertexKey string, config *ara.RemoveVertexConfig, oldVertexPtr interface{}) (bool, int, error)
+	UpdateVertexWithRetryFunc  func(dbName string, graphName string, collName string, vertexKey string, vertexPtr interface{}, mutate func(vertexPtr interface{}) error, config *ara.UpdateWithRetryConfig) (ara.ReplaceVertexResult, int, error)
+	CreateEdgeFunc             func(dbName string, graphName string, collName string, data interface{}, config *ara.CreateEdgeConfig, newEdgePtr interface{}) (ara.CreateEdgeResult, int, error)
+	GetEdgeFunc                func(dbName string, graphName string, collName string, edgeKey string, config *ara.GetEdgeConfig, edgePtr interface{}) (ara.GetEdgeResult, int, error)
+	ModifyEdgeFunc             func(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ModifyEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (ara.ModifyEdgeResult, int, error)
+	ReplaceEdgeFunc            func(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ReplaceEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (ara.ReplaceEdgeResult, int, error)
+	RemoveEdgeFunc             func(dbName string, graphName string, collName string, edgeKey string, config *ara.RemoveEdgeConfig, oldEdgePtr interface{}) (bool, int, error)
+	EdgesFunc                  func(dbName string, collName string, vertexHandle string, direction string, edgesPtr interface{}) (ara.ListAllDocumentsResultStats, int, error)
+	TraverseFunc               func(dbName string, graphName string, startVertex string, config ara.TraversalConfig, verticesPtr interface{}, pathsPtr interface{}) (int, error)
+	ShortestPathFunc           func(dbName string, graphName string, from string, to string, config *ara.ShortestPathConfig, path *ara.Path) (bool, int, error)
+	KShortestPathsFunc         func(dbName string, graphName string, from string, to string, k int, config *ara.ShortestPathConfig, newPath func() ara.Path) ([]ara.Path, int, error)
+}
+
+var _ ara.GraphClient = (*GraphClient)(nil)
+
+func (m *GraphClient) ListGraphs(dbName string, graphsPtr interface{}) (r0 int, r1 error) {
+	m.record("ListGraphs", dbName, graphsPtr)
+	if m.ListGraphsFunc != nil {
+		return m.ListGraphsFunc(dbName, graphsPtr)
+	}
+	return
+}
+
+func (m *GraphClient) CreateGraph(dbName string, data interface{}) (r0 ara.Graph, r1 int, r2 error) {
+	m.record("CreateGraph", dbName, data)
+	if m.CreateGraphFunc != nil {
+		return m.CreateGraphFunc(dbName, data)
+	}
+	return
+}
+
+func (m *GraphClient) GetGraph(dbName string, graphName string) (r0 ara.Graph, r1 int, r2 error) {
+	m.record("GetGraph", dbName, graphName)
+	if m.GetGraphFunc != nil {
+		return m.GetGraphFunc(dbName, graphName)
+	}
+	return
+}
+
+func (m *GraphClient) DropGraph(dbName string, graphName string, config *ara.DropGraphConfig) (r0 bool, r1 int, r2 error) {
+	m.record("DropGraph", dbName, graphName, config)
+	if m.DropGraphFunc != nil {
+		return m.DropGraphFunc(dbName, graphName, config)
+	}
+	return
+}
+
+func (m *GraphClient) EnsureGraph(dbName string, definition ara.CreateGraphConfig, config *ara.EnsureGraphConfig) (r0 ara.EnsureGraphResult, r1 int, r2 error) {
+	m.record("EnsureGraph", dbName, definition, config)
+	if m.EnsureGraphFunc != nil {
+		return m.EnsureGraphFunc(dbName, definition, config)
+	}
+	return
+}
+
+func (m *GraphClient) ListVertexCollections(dbName string, graphName string) (r0 []string, r1 int, r2 error) {
+	m.record("ListVertexCollections", dbName, graphName)
+	if m.ListVertexCollectionsFunc != nil {
+		return m.ListVertexCollectionsFunc(dbName, graphName)
+	}
+	return
+}
+
+func (m *GraphClient) AddVertexCollection(dbName string, graphName string, collectionName string, config *ara.AddVertexCollectionConfig) (r0 ara.AddVertexCollectionResult, r1 int, r2 error) {
+	m.record("AddVertexCollection", dbName, graphName, collectionName, config)
+	if m.AddVertexCollectionFunc != nil {
+		return m.AddVertexCollectionFunc(dbName, graphName, collectionName, config)
+	}
+	return
+}
+
+func (m *GraphClient) RemoveVertexCollection(dbName string, graphName string, collectionName string, config *ara.RemoveVertexCollectionConfig) (r0 ara.RemoveVertexCollectionResult, r1 int, r2 error) {
+	m.record("RemoveVertexCollection", dbName, graphName, collectionName, config)
+	if m.RemoveVertexCollectionFunc != nil {
+		return m.RemoveVertexCollectionFunc(dbName, graphName, collectionName, config)
+	}
+	return
+}
+
+func (m *GraphClient) ListEdgeDefinitions(dbName string, graphName string) (r0 []string, r1 int, r2 error) {
+	m.record("ListEdgeDefinitions", dbName, graphName)
+	if m.ListEdgeDefinitionsFunc != nil {
+		return m.ListEdgeDefinitionsFunc(dbName, graphName)
+	}
+	return
+}
+
+func (m *GraphClient) AddEdgeDefinition(dbName string, graphName string, edgeDefinition interface{}) (r0 ara.AddEdgeDefinitionResult, r1 int, r2 error) {
+	m.record("AddEdgeDefinition", dbName, graphName, edgeDefinition)
+	if m.AddEdgeDefinitionFunc != nil {
+		return m.AddEdgeDefinitionFunc(dbName, graphName, edgeDefinition)
+	}
+	return
+}
+
+func (m *GraphClient) RemoveEdgeDefinition(dbName string, graphName string, definitionName string) (r0 ara.RemoveEdgeDefinitionResult, r1 int, r2 error) {
+	m.record("RemoveEdgeDefinition", dbName, graphName, definitionName)
+	if m.RemoveEdgeDefinitionFunc != nil {
+		return m.RemoveEdgeDefinitionFunc(dbName, graphName, definitionName)
+	}
+	return
+}
+
+func (m *GraphClient) ReplaceEdgeDefinition(dbName string, graphName string, definitionName string, edgeDefinition ara.EdgeDefinition, config *ara.ReplaceEdgeDefinitionConfig) (r0 ara.Graph, r1 int, r2 error) {
+	m.record("ReplaceEdgeDefinition", dbName, graphName, definitionName, edgeDefinition, config)
+	if m.ReplaceEdgeDefinitionFunc != nil {
+		return m.ReplaceEdgeDefinitionFunc(dbName, graphName, definitionName, edgeDefinition, config)
+	}
+	return
+}
+
+func (m *GraphClient) CreateVertex(dbName string, graphName string, collName string, data interface{}, config *ara.CreateVertexConfig, newVertexPtr interface{}) (r0 ara.CreateVertexResult, r1 int, r2 error) {
+	m.record("CreateVertex", dbName, graphName, collName, data, config, newVertexPtr)
+	if m.CreateVertexFunc != nil {
+		return m.CreateVertexFunc(dbName, graphName, collName, data, config, newVertexPtr)
+	}
+	return
+}
+
+func (m *GraphClient) GetVertex(dbName string, graphName string, collName string, vertexKey string, config *ara.GetVertexConfig, vertexPtr interface{}) (r0 ara.GetVertexResult, r1 int, r2 error) {
+	m.record("GetVertex", dbName, graphName, collName, vertexKey, config, vertexPtr)
+	if m.GetVertexFunc != nil {
+		return m.GetVertexFunc(dbName, graphName, collName, vertexKey, config, vertexPtr)
+	}
+	return
+}
+
+func (m *GraphClient) ModifyVertex(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ModifyVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (r0 ara.ModifyVertexResult, r1 int, r2 error) {
+	m.record("ModifyVertex", dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	if m.ModifyVertexFunc != nil {
+		return m.ModifyVertexFunc(dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	}
+	return
+}
+
+func (m *GraphClient) ReplaceVertex(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ReplaceVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (r0 ara.ReplaceVertexResult, r1 int, r2 error) {
+	m.record("ReplaceVertex", dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	if m.ReplaceVertexFunc != nil {
+		return m.ReplaceVertexFunc(dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	}
+	return
+}
+
+func (m *GraphClient) RemoveVertex(dbName string, graphName string, collName string, vertexKey string, config *ara.RemoveVertexConfig, oldVertexPtr interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("RemoveVertex", dbName, graphName, collName, vertexKey, config, oldVertexPtr)
+	if m.RemoveVertexFunc != nil {
+		return m.RemoveVertexFunc(dbName, graphName, collName, vertexKey, config, oldVertexPtr)
+	}
+	return
+}
+
+func (m *GraphClient) UpdateVertexWithRetry(dbName string, graphName string, collName string, vertexKey string, vertexPtr interface{}, mutate func(vertexPtr interface{}) error, config *ara.UpdateWithRetryConfig) (r0 ara.ReplaceVertexResult, r1 int, r2 error) {
+	m.record("UpdateVertexWithRetry", dbName, graphName, collName, vertexKey, vertexPtr, mutate, config)
+	if m.UpdateVertexWithRetryFunc != nil {
+		return m.UpdateVertexWithRetryFunc(dbName, graphName, collName, vertexKey, vertexPtr, mutate, config)
+	}
+	return
+}
+
+func (m *GraphClient) CreateEdge(dbName string, graphName string, collName string, data interface{}, config *ara.CreateEdgeConfig, newEdgePtr interface{}) (r0 ara.CreateEdgeResult, r1 int, r2 error) {
+	m.record("CreateEdge", dbName, graphName, collName, data, config, newEdgePtr)
+	if m.CreateEdgeFunc != nil {
+		return m.CreateEdgeFunc(dbName, graphName, collName, data, config, newEdgePtr)
+	}
+	return
+}
+
+func (m *GraphClient) GetEdge(dbName string, graphName string, collName string, edgeKey string, config *ara.GetEdgeConfig, edgePtr interface{}) (r0 ara.GetEdgeResult, r1 int, r2 error) {
+	m.record("GetEdge", dbName, graphName, collName, edgeKey, config, edgePtr)
+	if m.GetEdgeFunc != nil {
+		return m.GetEdgeFunc(dbName, graphName, collName, edgeKey, config, edgePtr)
+	}
+	return
+}
+
+func (m *GraphClient) ModifyEdge(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ModifyEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (r0 ara.ModifyEdgeResult, r1 int, r2 error) {
+	m.record("ModifyEdge", dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	if m.ModifyEdgeFunc != nil {
+		return m.ModifyEdgeFunc(dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	}
+	return
+}
+
+func (m *GraphClient) ReplaceEdge(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ReplaceEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (r0 ara.ReplaceEdgeResult, r1 int, r2 error) {
+	m.record("ReplaceEdge", dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	if m.ReplaceEdgeFunc != nil {
+		return m.ReplaceEdgeFunc(dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	}
+	return
+}
+
+func (m *GraphClient) RemoveEdge(dbName string, graphName string, collName string, edgeKey string, config *ara.RemoveEdgeConfig, oldEdgePtr interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("RemoveEdge", dbName, graphName, collName, edgeKey, config, oldEdgePtr)
+	if m.RemoveEdgeFunc != nil {
+		return m.RemoveEdgeFunc(dbName, graphName, collName, edgeKey, config, oldEdgePtr)
+	}
+	return
+}
+
+func (m *GraphClient) Edges(dbName string, collName string, vertexHandle string, direction string, edgesPtr interface{}) (r0 ara.ListAllDocumentsResultStats, r1 int, r2 error) {
+	m.record("Edges", dbName, collName, vertexHandle, direction, edgesPtr)
+	if m.EdgesFunc != nil {
+		return m.EdgesFunc(dbName, collName, vertexHandle, direction, edgesPtr)
+	}
+	return
+}
+
+func (m *GraphClient) Traverse(dbName string, graphName string, startVertex string, config ara.TraversalConfig, verticesPtr interface{}, pathsPtr interface{}) (r0 int, r1 error) {
+	m.record("Traverse", dbName, graphName, startVertex, config, verticesPtr, pathsPtr)
+	if m.TraverseFunc != nil {
+		return m.TraverseFunc(dbName, graphName, startVertex, config, verticesPtr, pathsPtr)
+	}
+	return
+}
+
+func (m *GraphClient) ShortestPath(dbName string, graphName string, from string, to string, config *ara.ShortestPathConfig, path *ara.Path) (r0 bool, r1 int, r2 error) {
+	m.record("ShortestPath", dbName, graphName, from, to, config, path)
+	if m.ShortestPathFunc != nil {
+		return m.ShortestPathFunc(dbName, graphName, from, to, config, path)
+	}
+	return
+}
+
+func (m *GraphClient) KShortestPaths(dbName string, graphName string, from string, to string, k int, config *ara.ShortestPathConfig, newPath func() ara.Path) (r0 []ara.Path, r1 int, r2 error) {
+	m.record("KShortestPaths", dbName, graphName, from, to, k, config, newPath)
+	if m.KShortestPathsFunc != nil {
+		return m.KShortestPathsFunc(dbName, graphName, from, to, k, config, newPath)
+	}
+	return
+}
+
+// SimpleQueryClient is a mock of arangogo.SimpleQueryClient.
+type SimpleQueryClient struct {
+	Recorder
+
+	ByExampleFunc        func(dbName string, config ara.ByExampleConfig) (*ara.Cursor, int, error)
+	FirstExampleFunc     func(dbName string, config ara.FirstExampleConfig, docPtr interface{}) (int, error)
+	AnyFunc              func(dbName string, config ara.AnyConfig, docPtr interface{}) (bool, int, error)
+	RangeFunc            func(dbName string, config ara.RangeConfig) (*ara.Cursor, int, error)
+	NearFunc             func(dbName string, config ara.NearConfig) (*ara.Cursor, int, error)
+	WithinFunc           func(dbName string, config ara.WithinConfig) (*ara.Cursor, int, error)
+	FulltextFunc         func(dbName string, config ara.FulltextConfig) (*ara.Cursor, int, error)
+	LookupByKeysFunc     func(dbName string, config ara.LookupByKeysConfig, docsPtr interface{}) (int, error)
+	RemoveByKeysFunc     func(dbName string, config ara.RemoveByKeysConfig, oldDocsPtr interface{}) (ara.RemoveByKeysResult, int, error)
+	RemoveByExampleFunc  func(dbName string, config ara.RemoveByExampleConfig) (int, int, error)
+	UpdateByExampleFunc  func(dbName string, config ara.UpdateByExampleConfig) (int, int, error)
+	ReplaceByExampleFunc func(dbName string, config ara.ReplaceByExampleConfig) (int, int, error)
+}
+
+var _ ara.SimpleQueryClient = (*SimpleQueryClient)(nil)
+
+func (m *SimpleQueryClient) ByExample(dbName string, config ara.ByExampleConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("ByExample", dbName, config)
+	if m.ByExampleFunc != nil {
+		return m.ByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) FirstExample(dbName string, config ara.FirstExampleConfig, docPtr interface{}) (r0 int, r1 error) {
+	m.record("FirstExample", dbName, config, docPtr)
+	if m.FirstExampleFunc != nil {
+		return m.FirstExampleFunc(dbName, config, docPtr)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) Any(dbName string, config ara.AnyConfig, docPtr interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("Any", dbName, config, docPtr)
+	if m.AnyFunc != nil {
+		return m.AnyFunc(dbName, config, docPtr)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) Range(dbName string, config ara.RangeConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Range", dbName, config)
+	if m.RangeFunc != nil {
+		return m.RangeFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) Near(dbName string, config ara.NearConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Near", dbName, config)
+	if m.NearFunc != nil {
+		return m.NearFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) Within(dbName string, config ara.WithinConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Within", dbName, config)
+	if m.WithinFunc != nil {
+		return m.WithinFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) Fulltext(dbName string, config ara.FulltextConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Fulltext", dbName, config)
+	if m.FulltextFunc != nil {
+		return m.FulltextFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) LookupByKeys(dbName string, config ara.LookupByKeysConfig, docsPtr interface{}) (r0 int, r1 error) {
+	m.record("LookupByKeys", dbName, config, docsPtr)
+	if m.LookupByKeysFunc != nil {
+		return m.LookupByKeysFunc(dbName, config, docsPtr)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) RemoveByKeys(dbName string, config ara.RemoveByKeysConfig, oldDocsPtr interface{}) (r0 ara.RemoveByKeysResult, r1 int, r2 error) {
+	m.record("RemoveByKeys", dbName, config, oldDocsPtr)
+	if m.RemoveByKeysFunc != nil {
+		return m.RemoveByKeysFunc(dbName, config, oldDocsPtr)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) RemoveByExample(dbName string, config ara.RemoveByExampleConfig) (r0 int, r1 int, r2 error) {
+	m.record("RemoveByExample", dbName, config)
+	if m.RemoveByExampleFunc != nil {
+		return m.RemoveByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) UpdateByExample(dbName string, config ara.UpdateByExampleConfig) (r0 int, r1 int, r2 error) {
+	m.record("UpdateByExample", dbName, config)
+	if m.UpdateByExampleFunc != nil {
+		return m.UpdateByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *SimpleQueryClient) ReplaceByExample(dbName string, config ara.ReplaceByExampleConfig) (r0 int, r1 int, r2 error) {
+	m.record("ReplaceByExample", dbName, config)
+	if m.ReplaceByExampleFunc != nil {
+		return m.ReplaceByExampleFunc(dbName, config)
+	}
+	return
+}
+
+// JobClient is a mock of arangogo.JobClient.
+type JobClient struct {
+	Recorder
+
+	AsyncFunc        func(mode string, job *ara.AsyncJob) ara.Client
+	GetJobResultFunc func(dbName string, jobID string, respBody interface{}) (bool, int, error)
+	GetJobStatusFunc func(dbName string, jobID string) (string, int, error)
+	ListJobsFunc     func(dbName string, status string, count int) ([]string, int, error)
+	CancelJobFunc    func(dbName string, jobID string) (int, error)
+	DeleteJobsFunc   func(dbName string, target string, config *ara.DeleteJobsConfig) (int, error)
+}
+
+var _ ara.JobClient = (*JobClient)(nil)
+
+func (m *JobClient) Async(mode string, job *ara.AsyncJob) (r0 ara.Client) {
+	m.record("Async", mode, job)
+	if m.AsyncFunc != nil {
+		return m.AsyncFunc(mode, job)
+	}
+	return
+}
+
+func (m *JobClient) GetJobResult(dbName string, jobID string, respBody interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("GetJobResult", dbName, jobID, respBody)
+	if m.GetJobResultFunc != nil {
+		return m.GetJobResultFunc(dbName, jobID, respBody)
+	}
+	return
+}
+
+func (m *JobClient) GetJobStatus(dbName string, jobID string) (r0 string, r1 int, r2 error) {
+	m.record("GetJobStatus", dbName, jobID)
+	if m.GetJobStatusFunc != nil {
+		return m.GetJobStatusFunc(dbName, jobID)
+	}
+	return
+}
+
+func (m *JobClient) ListJobs(dbName string, status string, count int) (r0 []string, r1 int, r2 error) {
+	m.record("ListJobs", dbName, status, count)
+	if m.ListJobsFunc != nil {
+		return m.ListJobsFunc(dbName, status, count)
+	}
+	return
+}
+
+func (m *JobClient) CancelJob(dbName string, jobID string) (r0 int, r1 error) {
+	m.record("CancelJob", dbName, jobID)
+	if m.CancelJobFunc != nil {
+		return m.CancelJobFunc(dbName, jobID)
+	}
+	return
+}
+
+func (m *JobClient) DeleteJobs(dbName string, target string, config *ara.DeleteJobsConfig) (r0 int, r1 error) {
+	m.record("DeleteJobs", dbName, target, config)
+	if m.DeleteJobsFunc != nil {
+		return m.DeleteJobsFunc(dbName, target, config)
+	}
+	return
+}
+
+// BatchClient is a mock of arangogo.BatchClient.
+type BatchClient struct {
+	Recorder
+
+	NewBatchFunc func(dbName string) ara.Batch
+}
+
+var _ ara.BatchClient = (*BatchClient)(nil)
+
+func (m *BatchClient) NewBatch(dbName string) (r0 ara.Batch) {
+	m.record("NewBatch", dbName)
+	if m.NewBatchFunc != nil {
+		return m.NewBatchFunc(dbName)
+	}
+	return
+}
+
+// Batch is a mock of arangogo.Batch.
+type Batch struct {
+	Recorder
+
+	AddFunc  func(op func(c ara.Client))
+	SendFunc func() (int, error)
+}
+
+var _ ara.Batch = (*Batch)(nil)
+
+func (m *Batch) Add(op func(c ara.Client)) {
+	m.record("Add", op)
+	if m.AddFunc != nil {
+		m.AddFunc(op)
+	}
+}
+
+func (m *Batch) Send() (r0 int, r1 error) {
+	m.record("Send")
+	if m.SendFunc != nil {
+		return m.SendFunc()
+	}
+	return
+}
+
+// ServerClient is a mock of arangogo.ServerClient.
+type ServerClient struct {
+	Recorder
+
+	VersionFunc           func() (ara.VersionInfo, int, error)
+	ServerRoleFunc        func() (string, int, error)
+	EngineFunc            func() (ara.EngineInfo, int, error)
+	PingFunc              func(ctx context.Context) (int, error)
+	CheckAvailabilityFunc func(ctx context.Context) (int, error)
+	WaitForReadyFunc      func(ctx context.Context, config *ara.WaitForReadyConfig) error
+}
+
+var _ ara.ServerClient = (*ServerClient)(nil)
+
+func (m *ServerClient) Version() (r0 ara.VersionInfo, r1 int, r2 error) {
+	m.record("Version")
+	if m.VersionFunc != nil {
+		return m.VersionFunc()
+	}
+	return
+}
+
+func (m *ServerClient) ServerRole() (r0 string, r1 int, r2 error) {
+	m.record("ServerRole")
+	if m.ServerRoleFunc != nil {
+		return m.ServerRoleFunc()
+	}
+	return
+}
+
+func (m *ServerClient) Engine() (r0 ara.EngineInfo, r1 int, r2 error) {
+	m.record("Engine")
+	if m.EngineFunc != nil {
+		return m.EngineFunc()
+	}
+	return
+}
+
+func (m *ServerClient) Ping(ctx context.Context) (r0 int, r1 error) {
+	m.record("Ping", ctx)
+	if m.PingFunc != nil {
+		return m.PingFunc(ctx)
+	}
+	return
+}
+
+func (m *ServerClient) CheckAvailability(ctx context.Context) (r0 int, r1 error) {
+	m.record("CheckAvailability", ctx)
+	if m.CheckAvailabilityFunc != nil {
+		return m.CheckAvailabilityFunc(ctx)
+	}
+	return
+}
+
+func (m *ServerClient) WaitForReady(ctx context.Context, config *ara.WaitForReadyConfig) (r0 error) {
+	m.record("WaitForReady", ctx, config)
+	if m.WaitForReadyFunc != nil {
+		return m.WaitForReadyFunc(ctx, config)
+	}
+	return
+}
+
+// Client is a mock of arangogo.Client.
+type Client struct {
+	Recorder
+
+	CreateDatabaseFunc         func(config ara.CreateDatabaseConfig) error
+	DropDatabaseFunc           func(name string) error
+	ListDatabasesFunc          func() ([]string, error)
+	ListUserDatabasesFunc      func() ([]string, error)
+	CreateCollectionFunc       func(dbName string, config ara.CreateCollectionConfig) (ara.CreateCollectionResult, int, error)
+	DropCollectionFunc         func(dbName string, collectionName string) (ara.DropCollectionResult, int, error)
+	ListCollectionsFunc        func(dbName string) ([]ara.Collection, int, error)
+	TruncateCollectionFunc     func(dbName string, collectionName string) (ara.Collection, int, error)
+	ReadDocumentFunc           func(dbName string, documentHandle string, config *ara.ReadDocumentConfig, documentPtr interface{}) (int, error)
+	ReadDocumentHeaderFunc     func(dbName string, documentHandle string, config *ara.ReadDocumentHeaderConfig) (string, int, error)
+	ListAllDocumentsFunc       func(dbName string, config ara.ListAllDocumentsConfig) (*ara.Cursor, int, error)
+	CreateDocumentFunc         func(dbName string, collName string, data interface{}, config *ara.CreateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (ara.Document, int, error)
+	CreateDocumentsFunc        func(dbName string, collName string, data interface{}, config *ara.CreateDocumentsConfig, oldDocsPtr interface{}, newDocsPtr interface{}) (ara.CreateDocumentsResult, int, error)
+	ReplaceDocumentFunc        func(dbName string, docHandle string, data interface{}, config *ara.ReplaceDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (ara.ReplaceDocumentResult, int, error)
+	UpdateDocumentFunc         func(dbName string, docHandle string, data interface{}, config *ara.UpdateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (ara.UpdateDocumentResult, int, error)
+	RemoveDocumentFunc         func(dbName string, collName string, key string, config *ara.RemoveDocumentConfig, docPtr interface{}) (ara.Document, int, error)
+	UpdateWithRetryFunc        func(dbName string, docHandle string, docPtr interface{}, mutate func(docPtr interface{}) error, config *ara.UpdateWithRetryConfig) (ara.ReplaceDocumentResult, int, error)
+	ListGraphsFunc             func(dbName string, graphsPtr interface{}) (int, error)
+	CreateGraphFunc            func(dbName string, data interface{}) (ara.Graph, int, error)
+	GetGraphFunc               func(dbName string, graphName string) (ara.Graph, int, error)
+	DropGraphFunc              func(dbName string, graphName string, config *ara.DropGraphConfig) (bool, int, error)
+	EnsureGraphFunc            func(dbName string, definition ara.CreateGraphConfig, config *ara.EnsureGraphConfig) (ara.EnsureGraphResult, int, error)
+	ListVertexCollectionsFunc  func(dbName string, graphName string) ([]string, int, error)
+	AddVertexCollectionFunc    func(dbName string, graphName string, collectionName string, config *ara.AddVertexCollectionConfig) (ara.AddVertexCollectionResult, int, error)
+	RemoveVertexCollectionFunc func(dbName string, graphName string, collectionName string, config *ara.RemoveVertexCollectionConfig) (ara.RemoveVertexCollectionResult, int, error)
+	ListEdgeDefinitionsFunc    func(dbName string, graphName string) ([]string, int, error)
+	AddEdgeDefinitionFunc      func(dbName string, graphName string, edgeDefinition interface{}) (ara.AddEdgeDefinitionResult, int, error)
+	RemoveEdgeDefinitionFunc   func(dbName string, graphName string, definitionName string) (ara.RemoveEdgeDefinitionResult, int, error)
+	ReplaceEdgeDefinitionFunc  func(dbName string, graphName string, definitionName string, edgeDefinition ara.EdgeDefinition, config *ara.ReplaceEdgeDefinitionConfig) (ara.Graph, int, error)
+	CreateVertexFunc           func(dbName string, graphName string, collName string, data interface{}, config *ara.CreateVertexConfig, newVertexPtr interface{}) (ara.CreateVertexResult, int, error)
+	GetVertexFunc              func(dbName string, graphName string, collName string, vertexKey string, config *ara.GetVertexConfig, vertexPtr interface{}) (ara.GetVertexResult, int, error)
+	ModifyVertexFunc           func(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ModifyVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (ara.ModifyVertexResult, int, error)
+	ReplaceVertexFunc          func(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ReplaceVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (ara.ReplaceVertexResult, int, error)
+	RemoveVertexFunc           func(dbName string, graphName string, collName string, vertexKey string, config *ara.RemoveVertexConfig, oldVertexPtr interface{}) (bool, int, error)
+	UpdateVertexWithRetryFunc  func(dbName string, graphName string, collName string, vertexKey string, vertexPtr interface{}, mutate func(vertexPtr interface{}) error, config *ara.UpdateWithRetryConfig) (ara.ReplaceVertexResult, int, error)
+	CreateEdgeFunc             func(dbName string, graphName string, collName string, data interface{}, config *ara.CreateEdgeConfig, newEdgePtr interface{}) (ara.CreateEdgeResult, int, error)
+	GetEdgeFunc                func(dbName string, graphName string, collName string, edgeKey string, config *ara.GetEdgeConfig, edgePtr interface{}) (ara.GetEdgeResult, int, error)
+	ModifyEdgeFunc             func(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ModifyEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (ara.ModifyEdgeResult, int, error)
+	ReplaceEdgeFunc            func(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ReplaceEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (ara.ReplaceEdgeResult, int, error)
+	RemoveEdgeFunc             func(dbName string, graphName string, collName string, edgeKey string, config *ara.RemoveEdgeConfig, oldEdgePtr interface{}) (bool, int, error)
+	EdgesFunc                  func(dbName string, collName string, vertexHandle string, direction string, edgesPtr interface{}) (ara.ListAllDocumentsResultStats, int, error)
+	TraverseFunc               func(dbName string, graphName string, startVertex string, config ara.TraversalConfig, verticesPtr interface{}, pathsPtr interface{}) (int, error)
+	ShortestPathFunc           func(dbName string, graphName string, from string, to string, config *ara.ShortestPathConfig, path *ara.Path) (bool, int, error)
+	KShortestPathsFunc         func(dbName string, graphName string, from string, to string, k int, config *ara.ShortestPathConfig, newPath func() ara.Path) ([]ara.Path, int, error)
+	ByExampleFunc              func(dbName string, config ara.ByExampleConfig) (*ara.Cursor, int, error)
+	FirstExampleFunc           func(dbName string, config ara.FirstExampleConfig, docPtr interface{}) (int, error)
+	AnyFunc                    func(dbName string, config ara.AnyConfig, docPtr interface{}) (bool, int, error)
+	RangeFunc                  func(dbName string, config ara.RangeConfig) (*ara.Cursor, int, error)
+	NearFunc                   func(dbName string, config ara.NearConfig) (*ara.Cursor, int, error)
+	WithinFunc                 func(dbName string, config ara.WithinConfig) (*ara.Cursor, int, error)
+	FulltextFunc               func(dbName string, config ara.FulltextConfig) (*ara.Cursor, int, error)
+	LookupByKeysFunc           func(dbName string, config ara.LookupByKeysConfig, docsPtr interface{}) (int, error)
+	RemoveByKeysFunc           func(dbName string, config ara.RemoveByKeysConfig, oldDocsPtr interface{}) (ara.RemoveByKeysResult, int, error)
+	RemoveByExampleFunc        func(dbName string, config ara.RemoveByExampleConfig) (int, int, error)
+	UpdateByExampleFunc        func(dbName string, config ara.UpdateByExampleConfig) (int, int, error)
+	ReplaceByExampleFunc       func(dbName string, config ara.ReplaceByExampleConfig) (int, int, error)
+	AsyncFunc                  func(mode string, job *ara.AsyncJob) ara.Client
+	GetJobResultFunc           func(dbName string, jobID string, respBody interface{}) (bool, int, error)
+	GetJobStatusFunc           func(dbName string, jobID string) (string, int, error)
+	ListJobsFunc               func(dbName string, status string, count int) ([]string, int, error)
+	CancelJobFunc              func(dbName string, jobID string) (int, error)
+	DeleteJobsFunc             func(dbName string, target string, config *ara.DeleteJobsConfig) (int, error)
+	NewBatchFunc               func(dbName string) ara.Batch
+	VersionFunc                func() (ara.VersionInfo, int, error)
+	ServerRoleFunc             func() (string, int, error)
+	EngineFunc                 func() (ara.EngineInfo, int, error)
+	PingFunc                   func(ctx context.Context) (int, error)
+	CheckAvailabilityFunc      func(ctx context.Context) (int, error)
+	WaitForReadyFunc           func(ctx context.Context, config *ara.WaitForReadyConfig) error
+}
+
+var _ ara.Client = (*Client)(nil)
+
+func (m *Client) CreateDatabase(config ara.CreateDatabaseConfig) (r0 error) {
+	m.record("CreateDatabase", config)
+	if m.CreateDatabaseFunc != nil {
+		return m.CreateDatabaseFunc(config)
+	}
+	return
+}
+
+func (m *Client) DropDatabase(name string) (r0 error) {
+	m.record("DropDatabase", name)
+	if m.DropDatabaseFunc != nil {
+		return m.DropDatabaseFunc(name)
+	}
+	return
+}
+
+func (m *Client) ListDatabases() (r0 []string, r1 error) {
+	m.record("ListDatabases")
+	if m.ListDatabasesFunc != nil {
+		return m.ListDatabasesFunc()
+	}
+	return
+}
+
+func (m *Client) ListUserDatabases() (r0 []string, r1 error) {
+	m.record("ListUserDatabases")
+	if m.ListUserDatabasesFunc != nil {
+		return m.ListUserDatabasesFunc()
+	}
+	return
+}
+
+func (m *Client) CreateCollection(dbName string, config ara.CreateCollectionConfig) (r0 ara.CreateCollectionResult, r1 int, r2 error) {
+	m.record("CreateCollection", dbName, config)
+	if m.CreateCollectionFunc != nil {
+		return m.CreateCollectionFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) DropCollection(dbName string, collectionName string) (r0 ara.DropCollectionResult, r1 int, r2 error) {
+	m.record("DropCollection", dbName, collectionName)
+	if m.DropCollectionFunc != nil {
+		return m.DropCollectionFunc(dbName, collectionName)
+	}
+	return
+}
+
+func (m *Client) ListCollections(dbName string) (r0 []ara.Collection, r1 int, r2 error) {
+	m.record("ListCollections", dbName)
+	if m.ListCollectionsFunc != nil {
+		return m.ListCollectionsFunc(dbName)
+	}
+	return
+}
+
+func (m *Client) TruncateCollection(dbName string, collectionName string) (r0 ara.Collection, r1 int, r2 error) {
+	m.record("TruncateCollection", dbName, collectionName)
+	if m.TruncateCollectionFunc != nil {
+		return m.TruncateCollectionFunc(dbName, collectionName)
+	}
+	return
+}
+
+func (m *Client) ReadDocument(dbName string, documentHandle string, config *ara.ReadDocumentConfig, documentPtr interface{}) (r0 int, r1 error) {
+	m.record("ReadDocument", dbName, documentHandle, config, documentPtr)
+	if m.ReadDocumentFunc != nil {
+		return m.ReadDocumentFunc(dbName, documentHandle, config, documentPtr)
+	}
+	return
+}
+
+func (m *Client) ReadDocumentHeader(dbName string, documentHandle string, config *ara.ReadDocumentHeaderConfig) (r0 string, r1 int, r2 error) {
+	m.record("ReadDocumentHeader", dbName, documentHandle, config)
+	if m.ReadDocumentHeaderFunc != nil {
+		return m.ReadDocumentHeaderFunc(dbName, documentHandle, config)
+	}
+	return
+}
+
+func (m *Client) ListAllDocuments(dbName string, config ara.ListAllDocumentsConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("ListAllDocuments", dbName, config)
+	if m.ListAllDocumentsFunc != nil {
+		return m.ListAllDocumentsFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) CreateDocument(dbName string, collName string, data interface{}, config *ara.CreateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (r0 ara.Document, r1 int, r2 error) {
+	m.record("CreateDocument", dbName, collName, data, config, oldDocPtr, newDocPtr)
+	if m.CreateDocumentFunc != nil {
+		return m.CreateDocumentFunc(dbName, collName, data, config, oldDocPtr, newDocPtr)
+	}
+	return
+}
+
+func (m *Client) CreateDocuments(dbName string, collName string, data interface{}, config *ara.CreateDocumentsConfig, oldDocsPtr interface{}, newDocsPtr interface{}) (r0 ara.CreateDocumentsResult, r1 int, r2 error) {
+	m.record("CreateDocuments", dbName, collName, data, config, oldDocsPtr, newDocsPtr)
+	if m.CreateDocumentsFunc != nil {
+		return m.CreateDocumentsFunc(dbName, collName, data, config, oldDocsPtr, newDocsPtr)
+	}
+	return
+}
+
+func (m *Client) ReplaceDocument(dbName string, docHandle string, data interface{}, config *ara.ReplaceDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (r0 ara.ReplaceDocumentResult, r1 int, r2 error) {
+	m.record("ReplaceDocument", dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	if m.ReplaceDocumentFunc != nil {
+		return m.ReplaceDocumentFunc(dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	}
+	return
+}
+
+func (m *Client) UpdateDocument(dbName string, docHandle string, data interface{}, config *ara.UpdateDocumentConfig, oldDocPtr interface{}, newDocPtr interface{}) (r0 ara.UpdateDocumentResult, r1 int, r2 error) {
+	m.record("UpdateDocument", dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	if m.UpdateDocumentFunc != nil {
+		return m.UpdateDocumentFunc(dbName, docHandle, data, config, oldDocPtr, newDocPtr)
+	}
+	return
+}
+
+func (m *Client) RemoveDocument(dbName string, collName string, key string, config *ara.RemoveDocumentConfig, docPtr interface{}) (r0 ara.Document, r1 int, r2 error) {
+	m.record("RemoveDocument", dbName, collName, key, config, docPtr)
+	if m.RemoveDocumentFunc != nil {
+		return m.RemoveDocumentFunc(dbName, collName, key, config, docPtr)
+	}
+	return
+}
+
+func (m *Client) UpdateWithRetry(dbName string, docHandle string, docPtr interface{}, mutate func(docPtr interface{}) error, config *ara.UpdateWithRetryConfig) (r0 ara.ReplaceDocumentResult, r1 int, r2 error) {
+	m.record("UpdateWithRetry", dbName, docHandle, docPtr, mutate, config)
+	if m.UpdateWithRetryFunc != nil {
+		return m.UpdateWithRetryFunc(dbName, docHandle, docPtr, mutate, config)
+	}
+	return
+}
+
+func (m *Client) ListGraphs(dbName string, graphsPtr interface{}) (r0 int, r1 error) {
+	m.record("ListGraphs", dbName, graphsPtr)
+	if m.ListGraphsFunc != nil {
+		return m.ListGraphsFunc(dbName, graphsPtr)
+	}
+	return
+}
+
+func (m *Client) CreateGraph(dbName string, data interface{}) (r0 ara.Graph, r1 int, r2 error) {
+	m.record("CreateGraph", dbName, data)
+	if m.CreateGraphFunc != nil {
+		return m.CreateGraphFunc(dbName, data)
+	}
+	return
+}
+
+func (m *Client) GetGraph(dbName string, graphName string) (r0 ara.Graph, r1 int, r2 error) {
+	m.record("GetGraph", dbName, graphName)
+	if m.GetGraphFunc != nil {
+		return m.GetGraphFunc(dbName, graphName)
+	}
+	return
+}
+
+func (m *Client) DropGraph(dbName string, graphName string, config *ara.DropGraphConfig) (r0 bool, r1 int, r2 error) {
+	m.record("DropGraph", dbName, graphName, config)
+	if m.DropGraphFunc != nil {
+		return m.DropGraphFunc(dbName, graphName, config)
+	}
+	return
+}
+
+func (m *Client) EnsureGraph(dbName string, definition ara.CreateGraphConfig, config *ara.EnsureGraphConfig) (r0 ara.EnsureGraphResult, r1 int, r2 error) {
+	m.record("EnsureGraph", dbName, definition, config)
+	if m.EnsureGraphFunc != nil {
+		return m.EnsureGraphFunc(dbName, definition, config)
+	}
+	return
+}
+
+func (m *Client) ListVertexCollections(dbName string, graphName string) (r0 []string, r1 int, r2 error) {
+	m.record("ListVertexCollections", dbName, graphName)
+	if m.ListVertexCollectionsFunc != nil {
+		return m.ListVertexCollectionsFunc(dbName, graphName)
+	}
+	return
+}
+
+func (m *Client) AddVertexCollection(dbName string, graphName string, collectionName string, config *ara.AddVertexCollectionConfig) (r0 ara.AddVertexCollectionResult, r1 int, r2 error) {
+	m.record("AddVertexCollection", dbName, graphName, collectionName, config)
+	if m.AddVertexCollectionFunc != nil {
+		return m.AddVertexCollectionFunc(dbName, graphName, collectionName, config)
+	}
+	return
+}
+
+func (m *Client) RemoveVertexCollection(dbName string, graphName string, collectionName string, config *ara.RemoveVertexCollectionConfig) (r0 ara.RemoveVertexCollectionResult, r1 int, r2 error) {
+	m.record("RemoveVertexCollection", dbName, graphName, collectionName, config)
+	if m.RemoveVertexCollectionFunc != nil {
+		return m.RemoveVertexCollectionFunc(dbName, graphName, collectionName, config)
+	}
+	return
+}
+
+func (m *Client) ListEdgeDefinitions(dbName string, graphName string) (r0 []string, r1 int, r2 error) {
+	m.record("ListEdgeDefinitions", dbName, graphName)
+	if m.ListEdgeDefinitionsFunc != nil {
+		return m.ListEdgeDefinitionsFunc(dbName, graphName)
+	}
+	return
+}
+
+func (m *Client) AddEdgeDefinition(dbName string, graphName string, edgeDefinition interface{}) (r0 ara.AddEdgeDefinitionResult, r1 int, r2 error) {
+	m.record("AddEdgeDefinition", dbName, graphName, edgeDefinition)
+	if m.AddEdgeDefinitionFunc != nil {
+		return m.AddEdgeDefinitionFunc(dbName, graphName, edgeDefinition)
+	}
+	return
+}
+
+func (m *Client) RemoveEdgeDefinition(dbName string, graphName string, definitionName string) (r0 ara.RemoveEdgeDefinitionResult, r1 int, r2 error) {
+	m.record("RemoveEdgeDefinition", dbName, graphName, definitionName)
+	if m.RemoveEdgeDefinitionFunc != nil {
+		return m.RemoveEdgeDefinitionFunc(dbName, graphName, definitionName)
+	}
+	return
+}
+
+func (m *Client) ReplaceEdgeDefinition(dbName string, graphName string, definitionName string, edgeDefinition ara.EdgeDefinition, config *ara.ReplaceEdgeDefinitionConfig) (r0 ara.Graph, r1 int, r2 error) {
+	m.record("ReplaceEdgeDefinition", dbName, graphName, definitionName, edgeDefinition, config)
+	if m.ReplaceEdgeDefinitionFunc != nil {
+		return m.ReplaceEdgeDefinitionFunc(dbName, graphName, definitionName, edgeDefinition, config)
+	}
+	return
+}
+
+func (m *Client) CreateVertex(dbName string, graphName string, collName string, data interface{}, config *ara.CreateVertexConfig, newVertexPtr interface{}) (r0 ara.CreateVertexResult, r1 int, r2 error) {
+	m.record("CreateVertex", dbName, graphName, collName, data, config, newVertexPtr)
+	if m.CreateVertexFunc != nil {
+		return m.CreateVertexFunc(dbName, graphName, collName, data, config, newVertexPtr)
+	}
+	return
+}
+
+func (m *Client) GetVertex(dbName string, graphName string, collName string, vertexKey string, config *ara.GetVertexConfig, vertexPtr interface{}) (r0 ara.GetVertexResult, r1 int, r2 error) {
+	m.record("GetVertex", dbName, graphName, collName, vertexKey, config, vertexPtr)
+	if m.GetVertexFunc != nil {
+		return m.GetVertexFunc(dbName, graphName, collName, vertexKey, config, vertexPtr)
+	}
+	return
+}
+
+func (m *Client) ModifyVertex(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ModifyVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (r0 ara.ModifyVertexResult, r1 int, r2 error) {
+	m.record("ModifyVertex", dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	if m.ModifyVertexFunc != nil {
+		return m.ModifyVertexFunc(dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	}
+	return
+}
+
+func (m *Client) ReplaceVertex(dbName string, graphName string, collName string, vertexKey string, data interface{}, config *ara.ReplaceVertexConfig, oldVertexPtr interface{}, newVertexPtr interface{}) (r0 ara.ReplaceVertexResult, r1 int, r2 error) {
+	m.record("ReplaceVertex", dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	if m.ReplaceVertexFunc != nil {
+		return m.ReplaceVertexFunc(dbName, graphName, collName, vertexKey, data, config, oldVertexPtr, newVertexPtr)
+	}
+	return
+}
+
+func (m *Client) RemoveVertex(dbName string, graphName string, collName string, vertexKey string, config *ara.RemoveVertexConfig, oldVertexPtr interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("RemoveVertex", dbName, graphName, collName, vertexKey, config, oldVertexPtr)
+	if m.RemoveVertexFunc != nil {
+		return m.RemoveVertexFunc(dbName, graphName, collName, vertexKey, config, oldVertexPtr)
+	}
+	return
+}
+
+func (m *Client) UpdateVertexWithRetry(dbName string, graphName string, collName string, vertexKey string, vertexPtr interface{}, mutate func(vertexPtr interface{}) error, config *ara.UpdateWithRetryConfig) (r0 ara.ReplaceVertexResult, r1 int, r2 error) {
+	m.record("UpdateVertexWithRetry", dbName, graphName, collName, vertexKey, vertexPtr, mutate, config)
+	if m.UpdateVertexWithRetryFunc != nil {
+		return m.UpdateVertexWithRetryFunc(dbName, graphName, collName, vertexKey, vertexPtr, mutate, config)
+	}
+	return
+}
+
+func (m *Client) CreateEdge(dbName string, graphName string, collName string, data interface{}, config *ara.CreateEdgeConfig, newEdgePtr interface{}) (r0 ara.CreateEdgeResult, r1 int, r2 error) {
+	m.record("CreateEdge", dbName, graphName, collName, data, config, newEdgePtr)
+	if m.CreateEdgeFunc != nil {
+		return m.CreateEdgeFunc(dbName, graphName, collName, data, config, newEdgePtr)
+	}
+	return
+}
+
+func (m *Client) GetEdge(dbName string, graphName string, collName string, edgeKey string, config *ara.GetEdgeConfig, edgePtr interface{}) (r0 ara.GetEdgeResult, r1 int, r2 error) {
+	m.record("GetEdge", dbName, graphName, collName, edgeKey, config, edgePtr)
+	if m.GetEdgeFunc != nil {
+		return m.GetEdgeFunc(dbName, graphName, collName, edgeKey, config, edgePtr)
+	}
+	return
+}
+
+func (m *Client) ModifyEdge(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ModifyEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (r0 ara.ModifyEdgeResult, r1 int, r2 error) {
+	m.record("ModifyEdge", dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	if m.ModifyEdgeFunc != nil {
+		return m.ModifyEdgeFunc(dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	}
+	return
+}
+
+func (m *Client) ReplaceEdge(dbName string, graphName string, collName string, edgeKey string, data interface{}, config *ara.ReplaceEdgeConfig, oldEdgePtr interface{}, newEdgePtr interface{}) (r0 ara.ReplaceEdgeResult, r1 int, r2 error) {
+	m.record("ReplaceEdge", dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	if m.ReplaceEdgeFunc != nil {
+		return m.ReplaceEdgeFunc(dbName, graphName, collName, edgeKey, data, config, oldEdgePtr, newEdgePtr)
+	}
+	return
+}
+
+func (m *Client) RemoveEdge(dbName string, graphName string, collName string, edgeKey string, config *ara.RemoveEdgeConfig, oldEdgePtr interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("RemoveEdge", dbName, graphName, collName, edgeKey, config, oldEdgePtr)
+	if m.RemoveEdgeFunc != nil {
+		return m.RemoveEdgeFunc(dbName, graphName, collName, edgeKey, config, oldEdgePtr)
+	}
+	return
+}
+
+func (m *Client) Edges(dbName string, collName string, vertexHandle string, direction string, edgesPtr interface{}) (r0 ara.ListAllDocumentsResultStats, r1 int, r2 error) {
+	m.record("Edges", dbName, collName, vertexHandle, direction, edgesPtr)
+	if m.EdgesFunc != nil {
+		return m.EdgesFunc(dbName, collName, vertexHandle, direction, edgesPtr)
+	}
+	return
+}
+
+func (m *Client) Traverse(dbName string, graphName string, startVertex string, config ara.TraversalConfig, verticesPtr interface{}, pathsPtr interface{}) (r0 int, r1 error) {
+	m.record("Traverse", dbName, graphName, startVertex, config, verticesPtr, pathsPtr)
+	if m.TraverseFunc != nil {
+		return m.TraverseFunc(dbName, graphName, startVertex, config, verticesPtr, pathsPtr)
+	}
+	return
+}
+
+func (m *Client) ShortestPath(dbName string, graphName string, from string, to string, config *ara.ShortestPathConfig, path *ara.Path) (r0 bool, r1 int, r2 error) {
+	m.record("ShortestPath", dbName, graphName, from, to, config, path)
+	if m.ShortestPathFunc != nil {
+		return m.ShortestPathFunc(dbName, graphName, from, to, config, path)
+	}
+	return
+}
+
+func (m *Client) KShortestPaths(dbName string, graphName string, from string, to string, k int, config *ara.ShortestPathConfig, newPath func() ara.Path) (r0 []ara.Path, r1 int, r2 error) {
+	m.record("KShortestPaths", dbName, graphName, from, to, k, config, newPath)
+	if m.KShortestPathsFunc != nil {
+		return m.KShortestPathsFunc(dbName, graphName, from, to, k, config, newPath)
+	}
+	return
+}
+
+func (m *Client) ByExample(dbName string, config ara.ByExampleConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("ByExample", dbName, config)
+	if m.ByExampleFunc != nil {
+		return m.ByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) FirstExample(dbName string, config ara.FirstExampleConfig, docPtr interface{}) (r0 int, r1 error) {
+	m.record("FirstExample", dbName, config, docPtr)
+	if m.FirstExampleFunc != nil {
+		return m.FirstExampleFunc(dbName, config, docPtr)
+	}
+	return
+}
+
+func (m *Client) Any(dbName string, config ara.AnyConfig, docPtr interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("Any", dbName, config, docPtr)
+	if m.AnyFunc != nil {
+		return m.AnyFunc(dbName, config, docPtr)
+	}
+	return
+}
+
+func (m *Client) Range(dbName string, config ara.RangeConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Range", dbName, config)
+	if m.RangeFunc != nil {
+		return m.RangeFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) Near(dbName string, config ara.NearConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Near", dbName, config)
+	if m.NearFunc != nil {
+		return m.NearFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) Within(dbName string, config ara.WithinConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Within", dbName, config)
+	if m.WithinFunc != nil {
+		return m.WithinFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) Fulltext(dbName string, config ara.FulltextConfig) (r0 *ara.Cursor, r1 int, r2 error) {
+	m.record("Fulltext", dbName, config)
+	if m.FulltextFunc != nil {
+		return m.FulltextFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) LookupByKeys(dbName string, config ara.LookupByKeysConfig, docsPtr interface{}) (r0 int, r1 error) {
+	m.record("LookupByKeys", dbName, config, docsPtr)
+	if m.LookupByKeysFunc != nil {
+		return m.LookupByKeysFunc(dbName, config, docsPtr)
+	}
+	return
+}
+
+func (m *Client) RemoveByKeys(dbName string, config ara.RemoveByKeysConfig, oldDocsPtr interface{}) (r0 ara.RemoveByKeysResult, r1 int, r2 error) {
+	m.record("RemoveByKeys", dbName, config, oldDocsPtr)
+	if m.RemoveByKeysFunc != nil {
+		return m.RemoveByKeysFunc(dbName, config, oldDocsPtr)
+	}
+	return
+}
+
+func (m *Client) RemoveByExample(dbName string, config ara.RemoveByExampleConfig) (r0 int, r1 int, r2 error) {
+	m.record("RemoveByExample", dbName, config)
+	if m.RemoveByExampleFunc != nil {
+		return m.RemoveByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) UpdateByExample(dbName string, config ara.UpdateByExampleConfig) (r0 int, r1 int, r2 error) {
+	m.record("UpdateByExample", dbName, config)
+	if m.UpdateByExampleFunc != nil {
+		return m.UpdateByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) ReplaceByExample(dbName string, config ara.ReplaceByExampleConfig) (r0 int, r1 int, r2 error) {
+	m.record("ReplaceByExample", dbName, config)
+	if m.ReplaceByExampleFunc != nil {
+		return m.ReplaceByExampleFunc(dbName, config)
+	}
+	return
+}
+
+func (m *Client) Async(mode string, job *ara.AsyncJob) (r0 ara.Client) {
+	m.record("Async", mode, job)
+	if m.AsyncFunc != nil {
+		return m.AsyncFunc(mode, job)
+	}
+	return
+}
+
+func (m *Client) GetJobResult(dbName string, jobID string, respBody interface{}) (r0 bool, r1 int, r2 error) {
+	m.record("GetJobResult", dbName, jobID, respBody)
+	if m.GetJobResultFunc != nil {
+		return m.GetJobResultFunc(dbName, jobID, respBody)
+	}
+	return
+}
+
+func (m *Client) GetJobStatus(dbName string, jobID string) (r0 string, r1 int, r2 error) {
+	m.record("GetJobStatus", dbName, jobID)
+	if m.GetJobStatusFunc != nil {
+		return m.GetJobStatusFunc(dbName, jobID)
+	}
+	return
+}
+
+func (m *Client) ListJobs(dbName string, status string, count int) (r0 []string, r1 int, r2 error) {
+	m.record("ListJobs", dbName, status, count)
+	if m.ListJobsFunc != nil {
+		return m.ListJobsFunc(dbName, status, count)
+	}
+	return
+}
+
+func (m *Client) CancelJob(dbName string, jobID string) (r0 int, r1 error) {
+	m.record("CancelJob", dbName, jobID)
+	if m.CancelJobFunc != nil {
+		return m.CancelJobFunc(dbName, jobID)
+	}
+	return
+}
+
+func (m *Client) DeleteJobs(dbName string, target string, config *ara.DeleteJobsConfig) (r0 int, r1 error) {
+	m.record("DeleteJobs", dbName, target, config)
+	if m.DeleteJobsFunc != nil {
+		return m.DeleteJobsFunc(dbName, target, config)
+	}
+	return
+}
+
+func (m *Client) NewBatch(dbName string) (r0 ara.Batch) {
+	m.record("NewBatch", dbName)
+	if m.NewBatchFunc != nil {
+		return m.NewBatchFunc(dbName)
+	}
+	return
+}
+
+func (m *Client) Version() (r0 ara.VersionInfo, r1 int, r2 error) {
+	m.record("Version")
+	if m.VersionFunc != nil {
+		return m.VersionFunc()
+	}
+	return
+}
+
+func (m *Client) ServerRole() (r0 string, r1 int, r2 error) {
+	m.record("ServerRole")
+	if m.ServerRoleFunc != nil {
+		return m.ServerRoleFunc()
+	}
+	return
+}
+
+func (m *Client) Engine() (r0 ara.EngineInfo, r1 int, r2 error) {
+	m.record("Engine")
+	if m.EngineFunc != nil {
+		return m.EngineFunc()
+	}
+	return
+}
+
+func (m *Client) Ping(ctx context.Context) (r0 int, r1 error) {
+	m.record("Ping", ctx)
+	if m.PingFunc != nil {
+		return m.PingFunc(ctx)
+	}
+	return
+}
+
+func (m *Client) CheckAvailability(ctx context.Context) (r0 int, r1 error) {
+	m.record("CheckAvailability", ctx)
+	if m.CheckAvailabilityFunc != nil {
+		return m.CheckAvailabilityFunc(ctx)
+	}
+	return
+}
+
+func (m *Client) WaitForReady(ctx context.Context, config *ara.WaitForReadyConfig) (r0 error) {
+	m.record("WaitForReady", ctx, config)
+	if m.WaitForReadyFunc != nil {
+		return m.WaitForReadyFunc(ctx, config)
+	}
+	return
+}
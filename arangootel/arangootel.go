@@ -0,0 +1,270 @@
+// Package arangootel instruments arangogo connections with OpenTelemetry
+// traces and metrics.
+//
+// Add the middleware to the connection config and send requests with a
+// connection returned by Connection.WithContext to make the spans children
+// of the span in the context.
+//
+//	mw, err := arangootel.NewMiddleware(nil)
+//	...
+//	c, err := ara.NewConnection(&ara.Config{Middlewares: []ara.Middleware{mw}})
+//	...
+//	c.WithContext(ctx).ReadDocument(...)
+package arangootel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/hnakamur/arangogo/arangootel"
+
+const (
+	DBNameKey        = attribute.Key("db.namespace")
+	CollectionKey    = attribute.Key("db.collection.name")
+	OperationKey     = attribute.Key("db.operation.name")
+	QueryHashKey     = attribute.Key("arangodb.query.hash")
+	ErrorNumKey      = attribute.Key("arangodb.error_num")
+	MethodKey        = attribute.Key("http.request.method")
+	StatusCodeKey    = attribute.Key("http.response.status_code")
+	DBSystemKey      = attribute.Key("db.system.name")
+	dbSystemArangoDB = "arangodb"
+)
+
+type Config struct {
+	// TracerProvider defaults to the global tracer provider.
+	TracerProvider trace.TracerProvider
+	// MeterProvider defaults to the global meter provider.
+	MeterProvider metric.MeterProvider
+	// Propagator injects the trace context into request headers.
+	// It defaults to the W3C trace context propagator, whose traceparent
+	// header is logged by ArangoDB.
+	Propagator propagation.TextMapPropagator
+}
+
+type instruments struct {
+	tracer        trace.Tracer
+	propagator    propagation.TextMapPropagator
+	requests      metric.Int64Counter
+	duration      metric.Float64Histogram
+	inFlight      metric.Int64UpDownCounter
+	bytesSent     metric.Int64Counter
+	bytesReceived metric.Int64Counter
+	retries       metric.Int64Counter
+}
+
+// NewMiddleware returns a middleware which records a span and metrics for
+// each request. config can be nil to use the defaults.
+func NewMiddleware(config *Config) (ara.Middleware, error) {
+	var c Config
+	if config != nil {
+		c = *config
+	}
+	if c.TracerProvider == nil {
+		c.TracerProvider = otel.GetTracerProvider()
+	}
+	if c.MeterProvider == nil {
+		c.MeterProvider = otel.GetMeterProvider()
+	}
+	if c.Propagator == nil {
+		c.Propagator = propagation.TraceContext{}
+	}
+
+	in, err := newInstruments(&c)
+	if err != nil {
+		return nil, err
+	}
+	return in.middleware, nil
+}
+
+func newInstruments(c *Config) (*instruments, error) {
+	meter := c.MeterProvider.Meter(instrumentationName)
+	in := &instruments{
+		tracer:     c.TracerProvider.Tracer(instrumentationName),
+		propagator: c.Propagator,
+	}
+	var err error
+	in.requests, err = meter.Int64Counter("arangodb.client.requests",
+		metric.WithDescription("Number of requests sent to ArangoDB."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create requests counter: %v", err)
+	}
+	in.duration, err = meter.Float64Histogram("arangodb.client.request.duration",
+		metric.WithDescription("Duration of requests to ArangoDB."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %v", err)
+	}
+	in.inFlight, err = meter.Int64UpDownCounter("arangodb.client.requests.in_flight",
+		metric.WithDescription("Number of requests to ArangoDB waiting for responses."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-flight counter: %v", err)
+	}
+	in.bytesSent, err = meter.Int64Counter("arangodb.client.sent_bytes",
+		metric.WithDescription("Size of request bodies sent to ArangoDB."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sent bytes counter: %v", err)
+	}
+	in.bytesReceived, err = meter.Int64Counter("arangodb.client.received_bytes",
+		metric.WithDescription("Size of response bodies received from ArangoDB."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create received bytes counter: %v", err)
+	}
+	in.retries, err = meter.Int64Counter("arangodb.client.retries",
+		metric.WithDescription("Number of requests to ArangoDB which were resent."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create retries counter: %v", err)
+	}
+	return in, nil
+}
+
+func (in *instruments) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		ctx := req.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		info := parseRequest(req)
+
+		attrs := []attribute.KeyValue{
+			DBSystemKey.String(dbSystemArangoDB),
+			DBNameKey.String(info.dbName),
+			OperationKey.String(info.operation),
+			MethodKey.String(req.Method),
+		}
+		spanAttrs := attrs
+		if info.collection != "" {
+			spanAttrs = append(spanAttrs, CollectionKey.String(info.collection))
+		}
+		if info.queryHash != "" {
+			spanAttrs = append(spanAttrs, QueryHashKey.String(info.queryHash))
+		}
+		ctx, span := in.tracer.Start(ctx, req.Method+" "+info.operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(spanAttrs...))
+		defer span.End()
+
+		if req.Header == nil {
+			req.Header = make(http.Header)
+		}
+		in.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+		req.Context = ctx
+
+		opt := metric.WithAttributes(attrs...)
+		if req.Attempt > 0 {
+			in.retries.Add(ctx, 1, opt)
+		}
+		in.inFlight.Add(ctx, 1, opt)
+		in.bytesSent.Add(ctx, int64(len(req.Payload)), opt)
+		start := time.Now()
+		resp, err := next(req)
+		elapsed := time.Since(start).Seconds()
+		in.inFlight.Add(ctx, -1, opt)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			opt = metric.WithAttributes(append(attrs, attribute.String("error.type", "transport"))...)
+			in.requests.Add(ctx, 1, opt)
+			in.duration.Record(ctx, elapsed, opt)
+			return nil, err
+		}
+
+		span.SetAttributes(StatusCodeKey.Int(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			if errorNum := resp.ErrorNum(); errorNum != 0 {
+				span.SetAttributes(ErrorNumKey.Int(errorNum))
+			}
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+		opt = metric.WithAttributes(append(attrs, StatusCodeKey.Int(resp.StatusCode))...)
+		in.requests.Add(ctx, 1, opt)
+		in.duration.Record(ctx, elapsed, opt)
+		in.bytesReceived.Add(ctx, int64(len(resp.Body)), opt)
+		return resp, nil
+	}
+}
+
+type requestInfo struct {
+	dbName     string
+	operation  string
+	collection string
+	queryHash  string
+}
+
+// collectionAPIs are the APIs whose path has the collection name after the
+// API name.
+var collectionAPIs = map[string]bool{
+	"collection": true,
+	"document":   true,
+	"edge":       true,
+	"edges":      true,
+}
+
+// parseRequest gets the database, the API and the collection from the path
+// like /_db/{dbName}/_api/document/{collName}/{key}, and the collection or
+// the AQL query from the payload.
+func parseRequest(req *ara.Request) requestInfo {
+	info := requestInfo{dbName: ara.SystemDatabaseName}
+	path, rawQuery := req.Path, ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, rawQuery = path[:i], path[i+1:]
+	}
+	segs := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segs) >= 2 && segs[0] == "_db" {
+		info.dbName, _ = url.PathUnescape(segs[1])
+		segs = segs[2:]
+	}
+	if len(segs) >= 2 && (segs[0] == "_api" || segs[0] == "_admin") {
+		info.operation = segs[1]
+		switch {
+		case segs[1] == "simple" && len(segs) >= 3:
+			info.operation = "simple/" + segs[2]
+		case collectionAPIs[segs[1]] && len(segs) >= 3:
+			info.collection, _ = url.PathUnescape(segs[2])
+		}
+	} else {
+		info.operation = strings.Join(segs, "/")
+	}
+	if info.collection == "" && rawQuery != "" {
+		if q, err := url.ParseQuery(rawQuery); err == nil {
+			info.collection = q.Get("collection")
+		}
+	}
+
+	if len(req.Payload) > 0 && (info.collection == "" || info.operation == "cursor") {
+		var body struct {
+			Collection string `json:"collection"`
+			Query      string `json:"query"`
+		}
+		if ara.BodyCodec(req.Header).Unmarshal(req.Payload, &body) == nil {
+			if info.collection == "" {
+				info.collection = body.Collection
+			}
+			if body.Query != "" && (info.operation == "cursor" || info.operation == "explain") {
+				sum := sha256.Sum256([]byte(body.Query))
+				info.queryHash = hex.EncodeToString(sum[:])
+			}
+		}
+	}
+	return info
+}
@@ -0,0 +1,313 @@
+package arangootel_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangootel"
+	"github.com/hnakamur/arangogo/arangotest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testDBName = "test"
+
+// requestRecorder is a middleware after the instrumentation which keeps the
+// requests as they are sent.
+type requestRecorder struct {
+	mu   sync.Mutex
+	reqs []ara.Request
+}
+
+func (r *requestRecorder) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		r.mu.Lock()
+		r.reqs = append(r.reqs, *req)
+		r.mu.Unlock()
+		return next(req)
+	}
+}
+
+func (r *requestRecorder) last() ara.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reqs[len(r.reqs)-1]
+}
+
+type fixture struct {
+	server   *arangotest.Server
+	conn     *ara.Connection
+	spans    *tracetest.SpanRecorder
+	tracer   trace.Tracer
+	reader   *sdkmetric.ManualReader
+	requests *requestRecorder
+}
+
+// setup returns a connection instrumented with an in-memory tracer provider
+// and a manual metric reader, to a server with the users collection in the
+// test database. before are the middlewares before the instrumentation.
+func setup(t *testing.T, before ...ara.Middleware) *fixture {
+	t.Helper()
+	f := &fixture{
+		server:   arangotest.NewServer(nil),
+		spans:    tracetest.NewSpanRecorder(),
+		reader:   sdkmetric.NewManualReader(),
+		requests: new(requestRecorder),
+	}
+	t.Cleanup(f.server.Close)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(f.spans))
+	f.tracer = tp.Tracer("test")
+	mw, err := arangootel.NewMiddleware(&arangootel.Config{
+		TracerProvider: tp,
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(f.reader)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ara.NewConnection(f.server.ConnectionConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateDatabase(ara.CreateDatabaseConfig{Name: testDBName}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.CreateCollection(testDBName, ara.CreateCollectionConfig{Name: "users"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "alice"}, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	config := f.server.ConnectionConfig()
+	config.Middlewares = append(before, mw, f.requests.middleware)
+	f.conn, err = ara.NewConnection(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func attributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func checkAttributes(t *testing.T, name string, got []attribute.KeyValue, want map[attribute.Key]interface{}) {
+	t.Helper()
+	attrs := attributes(got)
+	for k, v := range want {
+		if a, ok := attrs[k]; !ok || a.AsInterface() != v {
+			t.Errorf("%s: %s = %v, want %v", name, k, a.AsInterface(), v)
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	f := setup(t)
+	if _, err := f.conn.ReadDocument(testDBName, "users/alice", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.conn.ReadDocument(testDBName, "users/missing", nil, nil); err == nil {
+		t.Fatal("got no error for a missing document")
+	}
+
+	spans := f.spans.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	for _, s := range spans {
+		if s.Name() != "GET document" || s.SpanKind() != trace.SpanKindClient {
+			t.Errorf("span %s, kind %v", s.Name(), s.SpanKind())
+		}
+		checkAttributes(t, s.Name(), s.Attributes(), map[attribute.Key]interface{}{
+			arangootel.DBSystemKey:   "arangodb",
+			arangootel.DBNameKey:     testDBName,
+			arangootel.CollectionKey: "users",
+			arangootel.OperationKey:  "document",
+			arangootel.MethodKey:     http.MethodGet,
+		})
+	}
+
+	checkAttributes(t, "found", spans[0].Attributes(), map[attribute.Key]interface{}{
+		arangootel.StatusCodeKey: int64(http.StatusOK),
+	})
+	if _, ok := attributes(spans[0].Attributes())[arangootel.ErrorNumKey]; ok || spans[0].Status().Code == codes.Error {
+		t.Errorf("found: status %v, attributes %v", spans[0].Status(), spans[0].Attributes())
+	}
+	checkAttributes(t, "missing", spans[1].Attributes(), map[attribute.Key]interface{}{
+		arangootel.StatusCodeKey: int64(http.StatusNotFound),
+		arangootel.ErrorNumKey:   int64(1202),
+	})
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("missing: status = %v, want error", spans[1].Status())
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	f := setup(t)
+	f.server.Close()
+	if _, err := f.conn.ReadDocument(testDBName, "users/alice", nil, nil); err == nil {
+		t.Fatal("got no error from a closed server")
+	}
+	spans := f.spans.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error || len(spans[0].Events()) == 0 {
+		t.Fatalf("spans = %v", spans)
+	}
+	sum := collectSum(t, f.reader, "arangodb.client.requests")
+	if len(sum.DataPoints) != 1 {
+		t.Fatalf("data points = %v", sum.DataPoints)
+	}
+	if v, _ := sum.DataPoints[0].Attributes.Value("error.type"); v.AsString() != "transport" {
+		t.Errorf("attributes = %v", sum.DataPoints[0].Attributes)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	f := setup(t)
+	ctx, parent := f.tracer.Start(context.Background(), "parent")
+	if _, err := f.conn.WithContext(ctx).ReadDocument(testDBName, "users/alice", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := f.spans.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	child := spans[0]
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() || child.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("parent = %v, want %v", child.Parent(), parent.SpanContext())
+	}
+	want := "00-" + child.SpanContext().TraceID().String() + "-" + child.SpanContext().SpanID().String() + "-01"
+	if got := f.requests.last().Header.Get("traceparent"); got != want {
+		t.Errorf("traceparent = %q, want %q", got, want)
+	}
+}
+
+func TestQueryHash(t *testing.T) {
+	f := setup(t)
+	// The server does not run queries, but the span is recorded anyway.
+	f.conn.ShortestPath(testDBName, "g", "users/alice", "users/bob", nil, nil)
+
+	var body struct {
+		Query string `json:"query"`
+	}
+	req := f.requests.last()
+	if err := json.Unmarshal(req.Payload, &body); err != nil || body.Query == "" {
+		t.Fatalf("payload = %s, err = %v", req.Payload, err)
+	}
+	sum := sha256.Sum256([]byte(body.Query))
+	spans := f.spans.Ended()
+	s := spans[len(spans)-1]
+	if s.Name() != "POST cursor" {
+		t.Fatalf("span = %s", s.Name())
+	}
+	checkAttributes(t, s.Name(), s.Attributes(), map[attribute.Key]interface{}{
+		arangootel.QueryHashKey: hex.EncodeToString(sum[:]),
+		arangootel.OperationKey: "cursor",
+	})
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	m := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, metric := range sm.Metrics {
+			m[metric.Name] = metric.Data
+		}
+	}
+	return m
+}
+
+func collectSum(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Sum[int64] {
+	t.Helper()
+	sum, ok := collect(t, reader)[name].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("no %s", name)
+	}
+	return sum
+}
+
+// sumByStatus returns the values of the data points by status code.
+func sumByStatus(sum metricdata.Sum[int64]) map[int64]int64 {
+	m := make(map[int64]int64)
+	for _, dp := range sum.DataPoints {
+		status, _ := dp.Attributes.Value(arangootel.StatusCodeKey)
+		m[status.AsInt64()] += dp.Value
+	}
+	return m
+}
+
+func TestMetrics(t *testing.T) {
+	// The second attempt of reading alice is counted as a retry.
+	retry := func(next ara.Handler) ara.Handler {
+		return func(req *ara.Request) (*ara.Response, error) {
+			if _, err := next(req); err != nil {
+				return nil, err
+			}
+			req.Attempt++
+			return next(req)
+		}
+	}
+	f := setup(t, retry)
+	if _, err := f.conn.ReadDocument(testDBName, "users/alice", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	f.conn.ReadDocument(testDBName, "users/missing", nil, nil)
+
+	metrics := collect(t, f.reader)
+	requests := sumByStatus(collectSum(t, f.reader, "arangodb.client.requests"))
+	if requests[http.StatusOK] != 2 || requests[http.StatusNotFound] != 2 {
+		t.Errorf("requests = %v", requests)
+	}
+	if got := collectSum(t, f.reader, "arangodb.client.retries"); len(got.DataPoints) != 1 || got.DataPoints[0].Value != 2 {
+		t.Errorf("retries = %+v", got.DataPoints)
+	}
+	for _, dp := range collectSum(t, f.reader, "arangodb.client.requests.in_flight").DataPoints {
+		if dp.Value != 0 {
+			t.Errorf("in flight = %d", dp.Value)
+		}
+	}
+	received := sumByStatus(collectSum(t, f.reader, "arangodb.client.received_bytes"))
+	if received[http.StatusOK] == 0 || received[http.StatusNotFound] == 0 {
+		t.Errorf("received bytes = %v", received)
+	}
+
+	duration, ok := metrics["arangodb.client.request.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatal("no duration histogram")
+	}
+	var count uint64
+	for _, dp := range duration.DataPoints {
+		count += dp.Count
+		checkAttributes(t, "duration", dp.Attributes.ToSlice(), map[attribute.Key]interface{}{
+			arangootel.DBNameKey:    testDBName,
+			arangootel.OperationKey: "document",
+		})
+		if _, ok := dp.Attributes.Value(arangootel.CollectionKey); ok {
+			t.Error("metrics have the collection attribute")
+		}
+	}
+	if count != 4 {
+		t.Errorf("durations = %d, want 4", count)
+	}
+}
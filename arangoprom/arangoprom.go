@@ -0,0 +1,370 @@
+// Package arangoprom exposes the metrics of arangogo connections and
+// ArangoDB servers to Prometheus.
+//
+// The client-side metrics are recorded by the middleware of the collector,
+// and the server metrics are fetched from /_admin/statistics and
+// /_admin/metrics with the connection set by SetConnection when the
+// collector is scraped. The scrapes are sent without the middlewares of the
+// connection, so they are not counted in the client-side metrics.
+//
+//	col := arangoprom.NewCollector(&arangoprom.Config{Endpoint: url})
+//	c, err := ara.NewConnection(&ara.Config{
+//		URL:         url,
+//		Middlewares: []ara.Middleware{col.Middleware},
+//	})
+//	...
+//	col.SetConnection(c)
+//	prometheus.MustRegister(col)
+package arangoprom
+
+import (
+	"bytes"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	namespace     = "arangodb"
+	endpointLabel = "endpoint"
+	databaseLabel = "database"
+	methodLabel   = "method"
+	codeLabel     = "code"
+)
+
+type Config struct {
+	// Endpoint is the value of the endpoint label of all metrics.
+	Endpoint string
+	// DurationBuckets are the buckets of the request duration histogram.
+	// They default to prometheus.DefBuckets.
+	DurationBuckets []float64
+	// DisableServerStatistics disables fetching /_admin/statistics.
+	DisableServerStatistics bool
+	// DisableServerMetrics disables fetching /_admin/metrics.
+	DisableServerMetrics bool
+}
+
+// Collector is a prometheus.Collector for an ArangoDB endpoint. It is an
+// unchecked collector since the server metrics are not known in advance.
+type Collector struct {
+	config Config
+
+	mu   sync.Mutex
+	conn *ara.Connection
+
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+	bytesSent     *prometheus.CounterVec
+	bytesReceived *prometheus.CounterVec
+	scrapeErrors  *prometheus.CounterVec
+
+	statDescs statisticsDescs
+}
+
+func NewCollector(config *Config) *Collector {
+	var c Config
+	if config != nil {
+		c = *config
+	}
+	if c.DurationBuckets == nil {
+		c.DurationBuckets = prometheus.DefBuckets
+	}
+	constLabels := prometheus.Labels{endpointLabel: c.Endpoint}
+	return &Collector{
+		config: c,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "requests_total",
+			Help:        "Number of requests sent to ArangoDB.",
+			ConstLabels: constLabels,
+		}, []string{databaseLabel, methodLabel, codeLabel}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "request_duration_seconds",
+			Help:        "Duration of requests to ArangoDB.",
+			ConstLabels: constLabels,
+			Buckets:     c.DurationBuckets,
+		}, []string{databaseLabel, methodLabel}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "requests_in_flight",
+			Help:        "Number of requests to ArangoDB waiting for responses.",
+			ConstLabels: constLabels,
+		}, []string{databaseLabel}),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "sent_bytes_total",
+			Help:        "Size of request bodies sent to ArangoDB.",
+			ConstLabels: constLabels,
+		}, []string{databaseLabel, methodLabel}),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "received_bytes_total",
+			Help:        "Size of response bodies received from ArangoDB.",
+			ConstLabels: constLabels,
+		}, []string{databaseLabel, methodLabel}),
+		scrapeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "scrape_errors_total",
+			Help:        "Number of failures to fetch server metrics.",
+			ConstLabels: constLabels,
+		}, []string{"source"}),
+		statDescs: newStatisticsDescs(constLabels),
+	}
+}
+
+// SetConnection sets the connection used to fetch the server metrics.
+// The server metrics are not collected until it is set.
+func (c *Collector) SetConnection(conn *ara.Connection) {
+	if conn != nil {
+		conn = conn.WithoutMiddlewares()
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+}
+
+func (c *Collector) connection() *ara.Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn
+}
+
+// Middleware records the client-side metrics of requests.
+func (c *Collector) Middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		db := databaseFromPath(req.Path)
+		inFlight := c.inFlight.WithLabelValues(db)
+		inFlight.Inc()
+		start := time.Now()
+		resp, err := next(req)
+		elapsed := time.Since(start).Seconds()
+		inFlight.Dec()
+
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+			c.bytesReceived.WithLabelValues(db, req.Method).Add(float64(len(resp.Body)))
+		}
+		c.requests.WithLabelValues(db, req.Method, code).Inc()
+		c.duration.WithLabelValues(db, req.Method).Observe(elapsed)
+		c.bytesSent.WithLabelValues(db, req.Method).Add(float64(len(req.Payload)))
+		return resp, err
+	}
+}
+
+func databaseFromPath(path string) string {
+	if !strings.HasPrefix(path, "/_db/") {
+		return ara.SystemDatabaseName
+	}
+	db := strings.TrimPrefix(path, "/_db/")
+	if i := strings.IndexAny(db, "/?"); i >= 0 {
+		db = db[:i]
+	}
+	if s, err := url.PathUnescape(db); err == nil {
+		db = s
+	}
+	return db
+}
+
+// Describe sends nothing so that the collector is unchecked.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	if conn := c.connection(); conn != nil {
+		if !c.config.DisableServerStatistics {
+			c.collectStatistics(conn, ch)
+		}
+		if !c.config.DisableServerMetrics {
+			c.collectMetrics(conn, ch)
+		}
+	}
+
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.bytesSent.Collect(ch)
+	c.bytesReceived.Collect(ch)
+	c.scrapeErrors.Collect(ch)
+}
+
+type statisticsDescs struct {
+	uptime          *prometheus.Desc
+	physicalMemory  *prometheus.Desc
+	residentMemory  *prometheus.Desc
+	virtualMemory   *prometheus.Desc
+	threads         *prometheus.Desc
+	cpuSeconds      *prometheus.Desc
+	pageFaults      *prometheus.Desc
+	httpConnections *prometheus.Desc
+	httpRequests    *prometheus.Desc
+	requestTime     *prometheus.Desc
+	queueTime       *prometheus.Desc
+}
+
+func newStatisticsDescs(constLabels prometheus.Labels) statisticsDescs {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "statistics", name), help, labels, constLabels)
+	}
+	return statisticsDescs{
+		uptime:          desc("uptime_seconds", "Uptime of the server."),
+		physicalMemory:  desc("physical_memory_bytes", "Physical memory of the server host."),
+		residentMemory:  desc("resident_memory_bytes", "Resident memory size of the server process."),
+		virtualMemory:   desc("virtual_memory_bytes", "Virtual memory size of the server process."),
+		threads:         desc("threads", "Number of threads of the server process."),
+		cpuSeconds:      desc("cpu_seconds_total", "CPU time of the server process.", "mode"),
+		pageFaults:      desc("page_faults_total", "Number of page faults of the server process.", "type"),
+		httpConnections: desc("http_connections", "Number of client HTTP connections."),
+		httpRequests:    desc("http_requests_total", "Number of HTTP requests handled by the server.", methodLabel),
+		requestTime:     desc("request_time_seconds", "Time spent to handle requests on the server."),
+		queueTime:       desc("queue_time_seconds", "Time requests spent in the server queue."),
+	}
+}
+
+func (c *Collector) collectStatistics(conn *ara.Connection, ch chan<- prometheus.Metric) {
+	stats, _, err := conn.GetServerStatistics()
+	if err != nil {
+		c.scrapeErrors.WithLabelValues("statistics").Inc()
+		return
+	}
+
+	d := &c.statDescs
+	gauge := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labels...)
+	}
+	counter := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v, labels...)
+	}
+	gauge(d.uptime, stats.Server.Uptime)
+	gauge(d.physicalMemory, float64(stats.Server.PhysicalMemory))
+	gauge(d.residentMemory, float64(stats.System.ResidentSize))
+	gauge(d.virtualMemory, float64(stats.System.VirtualSize))
+	gauge(d.threads, float64(stats.System.NumberOfThreads))
+	counter(d.cpuSeconds, stats.System.UserTime, "user")
+	counter(d.cpuSeconds, stats.System.SystemTime, "system")
+	counter(d.pageFaults, float64(stats.System.MinorPageFaults), "minor")
+	counter(d.pageFaults, float64(stats.System.MajorPageFaults), "major")
+	gauge(d.httpConnections, float64(stats.Client.HTTPConnections))
+
+	h := stats.HTTP
+	for _, r := range []struct {
+		method string
+		count  int64
+	}{
+		{"GET", h.RequestsGet},
+		{"HEAD", h.RequestsHead},
+		{"POST", h.RequestsPost},
+		{"PUT", h.RequestsPut},
+		{"PATCH", h.RequestsPatch},
+		{"DELETE", h.RequestsDelete},
+		{"OPTIONS", h.RequestsOptions},
+		{"other", h.RequestsOther},
+	} {
+		counter(d.httpRequests, float64(r.count), r.method)
+	}
+	ch <- prometheus.MustNewConstSummary(d.requestTime, uint64(stats.Client.RequestTime.Count), stats.Client.RequestTime.Sum, nil)
+	ch <- prometheus.MustNewConstSummary(d.queueTime, uint64(stats.Client.QueueTime.Count), stats.Client.QueueTime.Sum, nil)
+}
+
+func (c *Collector) collectMetrics(conn *ara.Connection, ch chan<- prometheus.Metric) {
+	b, _, err := conn.GetServerMetrics()
+	if err != nil {
+		c.scrapeErrors.WithLabelValues("metrics").Inc()
+		return
+	}
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(b))
+	if err != nil {
+		c.scrapeErrors.WithLabelValues("metrics").Inc()
+		return
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, m := range constMetrics(families[name], c.config.Endpoint) {
+			ch <- m
+		}
+	}
+}
+
+// constMetrics converts the metrics of a family fetched from the server
+// adding the endpoint label. The label names of the family are the union of
+// the label names of the metrics since a collector must use the same label
+// names for all metrics of a family.
+func constMetrics(f *dto.MetricFamily, endpoint string) []prometheus.Metric {
+	var labelNames []string
+	seen := map[string]bool{endpointLabel: true}
+	for _, m := range f.GetMetric() {
+		for _, lp := range m.GetLabel() {
+			if !seen[lp.GetName()] {
+				seen[lp.GetName()] = true
+				labelNames = append(labelNames, lp.GetName())
+			}
+		}
+	}
+	sort.Strings(labelNames)
+	desc := prometheus.NewDesc(f.GetName(), f.GetHelp(), labelNames, prometheus.Labels{endpointLabel: endpoint})
+
+	var metrics []prometheus.Metric
+	for _, m := range f.GetMetric() {
+		values := make(map[string]string, len(m.GetLabel()))
+		for _, lp := range m.GetLabel() {
+			values[lp.GetName()] = lp.GetValue()
+		}
+		labelValues := make([]string, len(labelNames))
+		for i, n := range labelNames {
+			labelValues[i] = values[n]
+		}
+
+		var metric prometheus.Metric
+		var err error
+		switch f.GetType() {
+		case dto.MetricType_COUNTER:
+			metric, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, m.GetCounter().GetValue(), labelValues...)
+		case dto.MetricType_GAUGE:
+			metric, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.GetGauge().GetValue(), labelValues...)
+		case dto.MetricType_HISTOGRAM:
+			h := m.GetHistogram()
+			buckets := make(map[float64]uint64, len(h.GetBucket()))
+			for _, b := range h.GetBucket() {
+				buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+			}
+			metric, err = prometheus.NewConstHistogram(desc, h.GetSampleCount(), h.GetSampleSum(), buckets, labelValues...)
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			quantiles := make(map[float64]float64, len(s.GetQuantile()))
+			for _, q := range s.GetQuantile() {
+				quantiles[q.GetQuantile()] = q.GetValue()
+			}
+			metric, err = prometheus.NewConstSummary(desc, s.GetSampleCount(), s.GetSampleSum(), quantiles, labelValues...)
+		default:
+			metric, err = prometheus.NewConstMetric(desc, prometheus.UntypedValue, m.GetUntyped().GetValue(), labelValues...)
+		}
+		if err != nil {
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
@@ -0,0 +1,124 @@
+package arangoprom_test
+
+import (
+	"strings"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangoprom"
+	"github.com/hnakamur/arangogo/arangotest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const endpoint = "test"
+
+// setup returns a collector whose middleware and scrapes use a connection
+// to s. The version is set so that no request detects it.
+func setup(t *testing.T, s *arangotest.Server, arangoVersion int) (*arangoprom.Collector, *ara.Connection) {
+	t.Helper()
+	col := arangoprom.NewCollector(&arangoprom.Config{Endpoint: endpoint})
+	config := s.ConnectionConfig()
+	config.ArangoVersion = arangoVersion
+	config.Middlewares = []ara.Middleware{col.Middleware}
+	c, err := ara.NewConnection(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col.SetConnection(c)
+	return col, c
+}
+
+const clientRequests = `
+# HELP arangodb_client_requests_total Number of requests sent to ArangoDB.
+# TYPE arangodb_client_requests_total counter
+arangodb_client_requests_total{code="200",database="_system",endpoint="test",method="GET"} 1
+arangodb_client_requests_total{code="404",database="_system",endpoint="test",method="GET"} 1
+`
+
+// serverRequests returns the server metrics when the server has got the
+// number of GET requests before the statistics and the total number of
+// requests before the metrics, both counting the scrape itself.
+func serverRequests(statisticsGets, metricsTotal string) string {
+	return `
+# HELP arangodb_statistics_http_requests_total Number of HTTP requests handled by the server.
+# TYPE arangodb_statistics_http_requests_total counter
+arangodb_statistics_http_requests_total{endpoint="test",method="DELETE"} 0
+arangodb_statistics_http_requests_total{endpoint="test",method="GET"} ` + statisticsGets + `
+arangodb_statistics_http_requests_total{endpoint="test",method="HEAD"} 0
+arangodb_statistics_http_requests_total{endpoint="test",method="OPTIONS"} 0
+arangodb_statistics_http_requests_total{endpoint="test",method="PATCH"} 0
+arangodb_statistics_http_requests_total{endpoint="test",method="POST"} 0
+arangodb_statistics_http_requests_total{endpoint="test",method="PUT"} 0
+arangodb_statistics_http_requests_total{endpoint="test",method="other"} 0
+# HELP arangodb_http_request_statistics_total_requests_total Total number of HTTP requests
+# TYPE arangodb_http_request_statistics_total_requests_total counter
+arangodb_http_request_statistics_total_requests_total{endpoint="test"} ` + metricsTotal + `
+`
+}
+
+func TestCollect(t *testing.T) {
+	s := arangotest.NewServer(nil)
+	defer s.Close()
+	col, c := setup(t, s, 31100)
+
+	if _, _, err := c.Version(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadDocument(ara.SystemDatabaseName, "users/alice", nil, nil); err == nil {
+		t.Fatal("got no error for a missing collection")
+	}
+
+	names := []string{
+		"arangodb_client_requests_total",
+		"arangodb_statistics_http_requests_total",
+		"arangodb_http_request_statistics_total_requests_total",
+	}
+	// The scrapes reach the server but are not counted by the middleware.
+	for _, counts := range [][2]string{{"3", "4"}, {"5", "6"}} {
+		want := clientRequests + serverRequests(counts[0], counts[1])
+		if err := testutil.CollectAndCompare(col, strings.NewReader(want), names...); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestScrapeErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		arangoVersion int
+		closed        bool
+		want          string
+	}{
+		{
+			// /_admin/metrics requires 3.6.
+			name:          "old server",
+			arangoVersion: 30500,
+			want:          `arangodb_client_scrape_errors_total{endpoint="test",source="metrics"} 1`,
+		},
+		{
+			name:          "closed server",
+			arangoVersion: 31100,
+			closed:        true,
+			want: `arangodb_client_scrape_errors_total{endpoint="test",source="metrics"} 1
+arangodb_client_scrape_errors_total{endpoint="test",source="statistics"} 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := arangotest.NewServer(nil)
+			defer s.Close()
+			col, _ := setup(t, s, tt.arangoVersion)
+			if tt.closed {
+				s.Close()
+			}
+
+			want := `
+# HELP arangodb_client_scrape_errors_total Number of failures to fetch server metrics.
+# TYPE arangodb_client_scrape_errors_total counter
+` + tt.want + "\n"
+			if err := testutil.CollectAndCompare(col, strings.NewReader(want), "arangodb_client_scrape_errors_total", "arangodb_client_requests_total"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package arangotest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestMethods are the methods counted separately in /_admin/statistics.
+// The requests with other methods are counted as "other".
+var requestMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// countRequest counts a request for /_admin/statistics and /_admin/metrics.
+// A request is counted before it is served, so a request for the statistics
+// counts itself.
+func (s *Server) countRequest(method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests == nil {
+		s.requests = make(map[string]int64)
+	}
+	if !contains(requestMethods, method) {
+		method = "other"
+	}
+	s.requests[method]++
+}
+
+func (s *Server) totalRequests() int64 {
+	var n int64
+	for _, count := range s.requests {
+		n += count
+	}
+	return n
+}
+
+// statistics returns the request counts and the uptime of the server. The
+// statistics of the process and the host are zero.
+func (s *Server) statistics(r *request) (*response, error) {
+	if len(r.segs) != 1 {
+		return nil, errUnknownPath()
+	}
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed()
+	}
+	now := time.Now()
+	return newResponse(http.StatusOK, map[string]interface{}{
+		"error":   false,
+		"code":    http.StatusOK,
+		"time":    float64(now.UnixNano()) / 1e9,
+		"enabled": true,
+		"system":  map[string]interface{}{},
+		"client":  map[string]interface{}{},
+		"http": map[string]interface{}{
+			"requestsTotal":   s.totalRequests(),
+			"requestsGet":     s.requests[http.MethodGet],
+			"requestsHead":    s.requests[http.MethodHead],
+			"requestsPost":    s.requests[http.MethodPost],
+			"requestsPut":     s.requests[http.MethodPut],
+			"requestsPatch":   s.requests[http.MethodPatch],
+			"requestsDelete":  s.requests[http.MethodDelete],
+			"requestsOptions": s.requests[http.MethodOptions],
+			"requestsOther":   s.requests["other"],
+		},
+		"server": map[string]interface{}{
+			"uptime": now.Sub(s.started).Seconds(),
+		},
+	}), nil
+}
+
+// metrics returns the request counts in the Prometheus text format.
+func (s *Server) metrics(r *request) (*response, error) {
+	if len(r.segs) != 1 {
+		return nil, errUnknownPath()
+	}
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed()
+	}
+	var b strings.Builder
+	b.WriteString("# HELP arangodb_http_request_statistics_total_requests_total Total number of HTTP requests\n")
+	b.WriteString("# TYPE arangodb_http_request_statistics_total_requests_total counter\n")
+	fmt.Fprintf(&b, "arangodb_http_request_statistics_total_requests_total %d\n", s.totalRequests())
+	return newResponse(http.StatusOK, plainText(b.String())), nil
+}
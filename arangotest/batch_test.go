@@ -0,0 +1,190 @@
+package arangotest_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+)
+
+const batchPath = "POST /_db/" + testDBName + "/_api/batch"
+
+// statusRecorder is a middleware which keeps the requests with the status
+// of their responses in the order the responses arrived. The responses of
+// the batch parts arrive after the response of the batch.
+type statusRecorder struct {
+	mu        sync.Mutex
+	responses []string
+}
+
+func (r *statusRecorder) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		resp, err := next(req)
+		status := "error"
+		if err == nil {
+			status = fmt.Sprint(resp.StatusCode)
+		}
+		r.mu.Lock()
+		r.responses = append(r.responses, req.Method+" "+req.Path+" "+status)
+		r.mu.Unlock()
+		return resp, err
+	}
+}
+
+func (r *statusRecorder) all() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.responses...)
+}
+
+func newBatchConnection(t *testing.T, middlewares ...ara.Middleware) (*ara.Connection, *statusRecorder) {
+	t.Helper()
+	s, c, _ := setup(t)
+	createUser(t, c, "alice", "Alice")
+	rec := new(statusRecorder)
+	config := s.ConnectionConfig()
+	config.Middlewares = append([]ara.Middleware{rec.middleware}, middlewares...)
+	return newConnection(t, config), rec
+}
+
+func TestBatchMiddlewares(t *testing.T) {
+	c, rec := newBatchConnection(t)
+	checkBatchResult(t, sendBatch(t, c))
+
+	got := rec.all()
+	if len(got) != 4 || got[0] != batchPath+" 200" {
+		t.Fatalf("responses = %v, want the batch and then the parts", got)
+	}
+	parts := got[1:]
+	sort.Strings(parts)
+	want := []string{
+		"GET /_db/test/_api/document/users/alice 200",
+		"GET /_db/test/_api/document/users/missing 404",
+		"POST /_db/test/_api/document/users 202",
+	}
+	if fmt.Sprint(parts) != fmt.Sprint(want) {
+		t.Errorf("parts = %v, want %v", parts, want)
+	}
+}
+
+func TestBatchOperationWithSeveralRequests(t *testing.T) {
+	c, rec := newBatchConnection(t)
+
+	var created ara.Document
+	var read user
+	var errs [2]error
+	b := c.NewBatch(testDBName)
+	b.Add(func(c ara.Client) {
+		created, _, errs[0] = c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "bob", "name": "Bob"}, nil, nil, nil)
+		if errs[0] == nil {
+			_, errs[1] = c.ReadDocument(testDBName, created.ID, nil, &read)
+		}
+	})
+	if _, err := b.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if errs[0] != nil || errs[1] != nil || created.ID != "users/bob" || read.Name != "Bob" {
+		t.Fatalf("errs = %v, created = %+v, read = %+v", errs, created, read)
+	}
+
+	// Only the first request is in the batch. The second one is sent
+	// separately after the batch response and before Send returns.
+	want := []string{
+		batchPath + " 200",
+		"POST /_db/test/_api/document/users 202",
+		"GET /_db/test/_api/document/users/bob 200",
+	}
+	if got := rec.all(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("responses = %v, want %v", got, want)
+	}
+}
+
+func TestBatchOperationWithoutRequest(t *testing.T) {
+	c, rec := newBatchConnection(t)
+
+	var read user
+	var readErr error
+	b := c.NewBatch(testDBName)
+	b.Add(func(c ara.Client) {})
+	b.Add(func(c ara.Client) {
+		_, readErr = c.ReadDocument(testDBName, "users/alice", nil, &read)
+	})
+	if _, err := b.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if readErr != nil || read.Name != "Alice" {
+		t.Errorf("err = %v, read = %+v", readErr, read)
+	}
+	if got := rec.all(); len(got) != 2 || got[0] != batchPath+" 200" {
+		t.Errorf("responses = %v", got)
+	}
+
+	// No batch request is sent when no operation sends a request.
+	b = c.NewBatch(testDBName)
+	b.Add(func(c ara.Client) {})
+	rc, err := b.Send()
+	if err != nil || rc != 0 {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+	if got := rec.all(); len(got) != 2 {
+		t.Errorf("responses = %v, want no more requests", got)
+	}
+}
+
+// failingMiddleware returns err instead of sending the requests whose
+// method and path has the suffix.
+func failingMiddleware(suffix string, err error) ara.Middleware {
+	return func(next ara.Handler) ara.Handler {
+		return func(req *ara.Request) (*ara.Response, error) {
+			if strings.HasSuffix(req.Method+" "+req.Path, suffix) {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
+
+func TestBatchPartError(t *testing.T) {
+	partErr := errors.New("rejected part")
+	c, rec := newBatchConnection(t, failingMiddleware("/users/missing", partErr))
+
+	r := sendBatch(t, c)
+	if r.errs[2] == nil || !strings.Contains(r.errs[2].Error(), partErr.Error()) || r.missingRC != 0 {
+		t.Errorf("rc = %d, err = %v, want %v", r.missingRC, r.errs[2], partErr)
+	}
+	if r.errs[0] != nil || r.errs[1] != nil || r.read.Name != "Alice" {
+		t.Errorf("errs = %v, read = %+v", r.errs, r.read)
+	}
+	// The failed part ends before the batch is sent without being put in it.
+	got := rec.all()
+	if len(got) != 4 || got[0] != "GET /_db/test/_api/document/users/missing error" || got[1] != batchPath+" 200" {
+		t.Errorf("responses = %v", got)
+	}
+}
+
+func TestBatchRequestError(t *testing.T) {
+	batchErr := errors.New("rejected batch")
+	c, _ := newBatchConnection(t, failingMiddleware(batchPath, batchErr))
+
+	var errs [2]error
+	b := c.NewBatch(testDBName)
+	b.Add(func(c ara.Client) {
+		_, errs[0] = c.ReadDocument(testDBName, "users/alice", nil, nil)
+	})
+	b.Add(func(c ara.Client) {
+		_, errs[1] = c.ReadDocument(testDBName, "users/missing", nil, nil)
+	})
+	_, err := b.Send()
+	if err == nil || !strings.Contains(err.Error(), batchErr.Error()) {
+		t.Errorf("err = %v, want %v", err, batchErr)
+	}
+	for i, err := range errs {
+		if err == nil || !strings.Contains(err.Error(), batchErr.Error()) {
+			t.Errorf("part %d: err = %v, want %v", i, err, batchErr)
+		}
+	}
+}
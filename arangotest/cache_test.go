@@ -0,0 +1,275 @@
+package arangotest_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+func documentPath(handle string) string {
+	return "GET /_db/" + testDBName + "/_api/document/" + handle
+}
+
+// newCachedConnection returns a connection to s with a document cache and
+// the recorders of its requests.
+func newCachedConnection(t *testing.T, s *arangotest.Server, config ara.DocumentCacheConfig, middlewares ...ara.Middleware) (*ara.Connection, *pathRecorder, *responseRecorder) {
+	t.Helper()
+	paths := new(pathRecorder)
+	rec := new(responseRecorder)
+	cc := s.ConnectionConfig()
+	cc.DocumentCache = &config
+	cc.Middlewares = append([]ara.Middleware{paths.middleware, rec.middleware}, middlewares...)
+	return newConnection(t, cc), paths, rec
+}
+
+func readUser(t *testing.T, c *ara.Connection, handle string) user {
+	t.Helper()
+	var u user
+	rc, err := c.ReadDocument(testDBName, handle, nil, &u)
+	if err != nil || rc != http.StatusOK {
+		t.Fatalf("read %s: rc = %d, err = %v", handle, rc, err)
+	}
+	return u
+}
+
+func checkRequests(t *testing.T, paths *pathRecorder, handle string, want int) {
+	t.Helper()
+	if n := paths.count(documentPath(handle)); n != want {
+		t.Errorf("requests for %s = %d, want %d", handle, n, want)
+	}
+}
+
+func TestDocumentCacheTTL(t *testing.T) {
+	s, c, _ := setup(t)
+	createUser(t, c, "alice", "Alice")
+	cc, paths, rec := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: 50 * time.Millisecond})
+
+	readUser(t, cc, "users/alice")
+	if u := readUser(t, cc, "users/alice"); u.Name != "Alice" {
+		t.Errorf("cached read = %+v", u)
+	}
+	checkRequests(t, paths, "users/alice", 1)
+
+	time.Sleep(60 * time.Millisecond)
+	if u := readUser(t, cc, "users/alice"); u.Name != "Alice" {
+		t.Errorf("revalidated read = %+v", u)
+	}
+	checkRequests(t, paths, "users/alice", 2)
+	if got := rec.last().StatusCode; got != http.StatusNotModified {
+		t.Errorf("revalidation status = %d, want 304", got)
+	}
+	readUser(t, cc, "users/alice")
+	checkRequests(t, paths, "users/alice", 2)
+
+	// A document changed by another connection is read again after the TTL.
+	if _, _, err := c.ReplaceDocument(testDBName, "users/alice", map[string]interface{}{"name": "Alice 2"}, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if u := readUser(t, cc, "users/alice"); u.Name != "Alice 2" {
+		t.Errorf("read after change = %+v", u)
+	}
+	if got := rec.last().StatusCode; got != http.StatusOK {
+		t.Errorf("status = %d, want 200", got)
+	}
+}
+
+func TestDocumentCacheLRU(t *testing.T) {
+	s, c, _ := setup(t)
+	for _, key := range []string{"alice", "bob", "carol"} {
+		createUser(t, c, key, key)
+	}
+	cc, paths, _ := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: time.Hour, MaxEntries: 2})
+
+	readUser(t, cc, "users/alice")
+	readUser(t, cc, "users/bob")
+	readUser(t, cc, "users/alice")
+	// bob is the least recently used and is evicted.
+	readUser(t, cc, "users/carol")
+
+	readUser(t, cc, "users/alice")
+	readUser(t, cc, "users/carol")
+	checkRequests(t, paths, "users/alice", 1)
+	checkRequests(t, paths, "users/carol", 1)
+	readUser(t, cc, "users/bob")
+	checkRequests(t, paths, "users/bob", 2)
+}
+
+// writeDuringRead is a middleware which runs write once after the first read
+// of handle got its response, so the response is older than the write.
+type writeDuringRead struct {
+	handle string
+	once   sync.Once
+	write  func()
+}
+
+func (w *writeDuringRead) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		resp, err := next(req)
+		if req.Method+" "+req.Path == documentPath(w.handle) {
+			w.once.Do(w.write)
+		}
+		return resp, err
+	}
+}
+
+func TestDocumentCacheReadRacingWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T, c *ara.Connection)
+		check func(t *testing.T, c *ara.Connection)
+	}{
+		{
+			name: "replace",
+			write: func(t *testing.T, c *ara.Connection) {
+				if _, _, err := c.ReplaceDocument(testDBName, "users/alice", map[string]interface{}{"name": "Alice 2"}, nil, nil, nil); err != nil {
+					t.Error(err)
+				}
+			},
+			check: func(t *testing.T, c *ara.Connection) {
+				if u := readUser(t, c, "users/alice"); u.Name != "Alice 2" {
+					t.Errorf("read after replace = %+v", u)
+				}
+			},
+		},
+		{
+			name: "remove",
+			write: func(t *testing.T, c *ara.Connection) {
+				if _, _, err := c.RemoveDocument(testDBName, "users", "alice", nil, nil); err != nil {
+					t.Error(err)
+				}
+			},
+			check: func(t *testing.T, c *ara.Connection) {
+				rc, err := c.ReadDocument(testDBName, "users/alice", nil, nil)
+				checkError(t, err, rc, http.StatusNotFound, 1202)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c, _ := setup(t)
+			createUser(t, c, "alice", "Alice")
+			var cc *ara.Connection
+			w := &writeDuringRead{handle: "users/alice", write: func() { tt.write(t, cc) }}
+			cc, paths, _ := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: time.Hour}, w.middleware)
+
+			if u := readUser(t, cc, "users/alice"); u.Name != "Alice" {
+				t.Errorf("first read = %+v", u)
+			}
+			tt.check(t, cc)
+			checkRequests(t, paths, "users/alice", 2)
+		})
+	}
+}
+
+func TestDocumentCacheAsyncWrites(t *testing.T) {
+	s, c, _ := setup(t)
+	createUser(t, c, "alice", "Alice")
+	cc, paths, rec := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: time.Hour})
+
+	readUser(t, cc, "users/alice")
+	var job ara.AsyncJob
+	if _, _, err := cc.Async(ara.AsyncModeStore, &job).ReplaceDocument(testDBName, "users/alice", map[string]interface{}{"name": "Alice 2"}, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The document written asynchronously is read again, revalidated once
+	// and then served from the cache.
+	if u := readUser(t, cc, "users/alice"); u.Name != "Alice 2" {
+		t.Errorf("read after async write = %+v", u)
+	}
+	checkRequests(t, paths, "users/alice", 2)
+	if u := readUser(t, cc, "users/alice"); u.Name != "Alice 2" {
+		t.Errorf("revalidated read = %+v", u)
+	}
+	checkRequests(t, paths, "users/alice", 3)
+	if got := rec.last().StatusCode; got != http.StatusNotModified {
+		t.Errorf("revalidation status = %d, want 304", got)
+	}
+	readUser(t, cc, "users/alice")
+	checkRequests(t, paths, "users/alice", 3)
+}
+
+func TestDocumentCacheManyAsyncWrites(t *testing.T) {
+	s, c, _ := setup(t)
+	for _, key := range []string{"alice", "bob", "carol"} {
+		createUser(t, c, key, key)
+	}
+	cc, paths, _ := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: time.Hour, MaxEntries: 1})
+
+	// More asynchronous writes than MaxEntries make every document
+	// revalidated once.
+	ac := cc.Async(ara.AsyncModeStore, nil)
+	for _, key := range []string{"bob", "carol"} {
+		if _, _, err := ac.ReplaceDocument(testDBName, "users/"+key, map[string]interface{}{"name": key + " 2"}, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		readUser(t, cc, "users/alice")
+	}
+	checkRequests(t, paths, "users/alice", 2)
+}
+
+func TestDocumentCacheConditionalReads(t *testing.T) {
+	s, c, _ := setup(t)
+	doc := createUser(t, c, "alice", "Alice")
+	cc, paths, _ := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: time.Hour})
+
+	readUser(t, cc, "users/alice")
+	var u user
+	rc, err := cc.ReadDocument(testDBName, "users/alice", &ara.ReadDocumentConfig{IfMatch: `"` + doc.Rev + `"`}, &u)
+	if err != nil || rc != http.StatusOK || u.Name != "Alice" {
+		t.Errorf("If-Match: rc = %d, err = %v, doc = %+v", rc, err, u)
+	}
+	rc, err = cc.ReadDocument(testDBName, "users/alice", &ara.ReadDocumentConfig{IfMatch: `"other"`}, nil)
+	checkError(t, err, rc, http.StatusPreconditionFailed, 1200)
+	rc, err = cc.ReadDocument(testDBName, "users/alice", &ara.ReadDocumentConfig{IfNoneMatch: `"` + doc.Rev + `"`}, nil)
+	if err != nil || rc != http.StatusNotModified {
+		t.Errorf("If-None-Match: rc = %d, err = %v", rc, err)
+	}
+	checkRequests(t, paths, "users/alice", 4)
+
+	readUser(t, cc, "users/alice")
+	checkRequests(t, paths, "users/alice", 4)
+}
+
+func TestDocumentCacheGraphDeletions(t *testing.T) {
+	s, c, _ := setup(t)
+	cc, _, _ := newCachedConnection(t, s, ara.DocumentCacheConfig{TTL: time.Hour})
+	_, _, err := c.CreateGraph(testDBName, ara.CreateGraphConfig{
+		Name: testGraphName,
+		EdgeDefinitions: []ara.EdgeDefinition{
+			{Collection: "knows", From: []string{"persons"}, To: []string{"persons"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createPerson(t, c, "alice", "Alice")
+	createPerson(t, c, "bob", "Bob")
+	if _, _, err := c.CreateEdge(testDBName, testGraphName, "knows",
+		map[string]interface{}{"_key": "ab", "_from": "persons/alice", "_to": "persons/bob"}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a vertex removes its edges.
+	readUser(t, cc, "knows/ab")
+	if _, _, err := cc.RemoveVertex(testDBName, testGraphName, "persons", "alice", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := cc.ReadDocument(testDBName, "knows/ab", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+
+	// Dropping the graph with its collections removes its vertices.
+	readUser(t, cc, "persons/bob")
+	if _, _, err := cc.DropGraph(testDBName, testGraphName, &ara.DropGraphConfig{DropCollections: ara.TruePtr()}); err != nil {
+		t.Fatal(err)
+	}
+	rc, err = cc.ReadDocument(testDBName, "persons/bob", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1203)
+}
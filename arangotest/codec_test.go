@@ -0,0 +1,113 @@
+package arangotest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+// newVelocyPackConnection returns a connection to s using VelocyPack and a
+// document cache, and the recorder of its requests.
+func newVelocyPackConnection(t *testing.T, s *arangotest.Server) (*ara.Connection, *pathRecorder, *responseRecorder) {
+	t.Helper()
+	paths := new(pathRecorder)
+	rec := new(responseRecorder)
+	config := s.ConnectionConfig()
+	config.Codec = ara.VelocyPackCodec{}
+	config.DocumentCache = &ara.DocumentCacheConfig{TTL: time.Hour}
+	config.Middlewares = []ara.Middleware{paths.middleware, rec.middleware}
+	return newConnection(t, config), paths, rec
+}
+
+func checkVelocyPackResponse(t *testing.T, rec *responseRecorder) {
+	t.Helper()
+	if got := rec.last().Header.Get("Content-Type"); got != ara.ContentTypeVelocyPack {
+		t.Errorf("Content-Type = %s, want %s", got, ara.ContentTypeVelocyPack)
+	}
+}
+
+func TestVelocyPackDocumentCache(t *testing.T) {
+	s, c, _ := setup(t)
+	doc := createUser(t, c, "alice", "Alice")
+	vc, paths, rec := newVelocyPackConnection(t, s)
+
+	for i := 0; i < 2; i++ {
+		var u user
+		rc, err := vc.ReadDocument(testDBName, "users/alice", nil, &u)
+		if err != nil || rc != http.StatusOK {
+			t.Fatalf("rc = %d, err = %v", rc, err)
+		}
+		if u.Name != "Alice" || u.Rev != doc.Rev {
+			t.Errorf("read %d: %+v", i, u)
+		}
+	}
+	checkVelocyPackResponse(t, rec)
+	if n := paths.count("GET /_db/" + testDBName + "/_api/document/users/alice"); n != 1 {
+		t.Errorf("requests = %d, want 1 with the second read from the cache", n)
+	}
+}
+
+func TestVelocyPackAny(t *testing.T) {
+	s, c, _ := setup(t)
+	vc, _, rec := newVelocyPackConnection(t, s)
+
+	var u user
+	found, rc, err := vc.Any(testDBName, ara.AnyConfig{Collection: "users"}, &u)
+	if err != nil || rc != http.StatusOK || found {
+		t.Fatalf("empty collection: found = %v, rc = %d, err = %v", found, rc, err)
+	}
+
+	doc := createUser(t, c, "alice", "Alice")
+	found, rc, err = vc.Any(testDBName, ara.AnyConfig{Collection: "users"}, &u)
+	if err != nil || rc != http.StatusOK || !found {
+		t.Fatalf("found = %v, rc = %d, err = %v", found, rc, err)
+	}
+	checkVelocyPackResponse(t, rec)
+	if u.Key != "alice" || u.Rev != doc.Rev || u.Name != "Alice" {
+		t.Errorf("document = %+v", u)
+	}
+
+	_, rc, err = vc.Any(testDBName, ara.AnyConfig{Collection: "missing"}, &u)
+	checkError(t, err, rc, http.StatusNotFound, 1203)
+}
+
+func TestVelocyPackCreateDocuments(t *testing.T) {
+	s, c, _ := setup(t)
+	createUser(t, c, "alice", "Alice")
+	vc, _, rec := newVelocyPackConnection(t, s)
+
+	docs := []interface{}{
+		map[string]interface{}{"_key": "bob", "name": "Bob", "age": 20},
+		map[string]interface{}{"_key": "alice", "name": "Alice 2"},
+		map[string]interface{}{"_key": "carol", "name": "Carol"},
+	}
+	var oldDocs, newDocs []user
+	r, rc, err := vc.CreateDocuments(testDBName, "users", docs,
+		&ara.CreateDocumentsConfig{ReturnNew: ara.TruePtr(), ReturnOld: ara.TruePtr()}, &oldDocs, &newDocs)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	checkVelocyPackResponse(t, rec)
+	if r.ErrorCount != 1 || r.ErrorCodes[1210] != 1 || len(r.Documents) != len(docs) {
+		t.Fatalf("result = %+v", r)
+	}
+	if len(newDocs) != len(docs) || len(oldDocs) != len(docs) {
+		t.Fatalf("old = %+v, new = %+v", oldDocs, newDocs)
+	}
+	for i, want := range []user{
+		{Document: ara.Document{Key: "bob", Rev: r.Documents[0].Rev}, Name: "Bob", Age: 20},
+		{},
+		{Document: ara.Document{Key: "carol", Rev: r.Documents[2].Rev}, Name: "Carol"},
+	} {
+		got := newDocs[i]
+		if got.Key != want.Key || got.Rev != want.Rev || got.Name != want.Name || got.Age != want.Age {
+			t.Errorf("new[%d] = %+v, want %+v", i, got, want)
+		}
+		if oldDocs[i].Key != "" {
+			t.Errorf("old[%d] = %+v, want none", i, oldDocs[i])
+		}
+	}
+}
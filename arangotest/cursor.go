@@ -0,0 +1,164 @@
+package arangotest
+
+import (
+	"net/http"
+	"sort"
+)
+
+const defaultBatchSize = 1000
+
+type cursor struct {
+	id        string
+	dbName    string
+	results   []interface{}
+	batchSize int
+}
+
+// nextBatch returns the body for the next batch and removes the cursor from
+// the server when it is exhausted.
+func (s *Server) nextBatch(cur *cursor, code int) *response {
+	n := cur.batchSize
+	if n > len(cur.results) {
+		n = len(cur.results)
+	}
+	batch := cur.results[:n]
+	cur.results = cur.results[n:]
+	hasMore := len(cur.results) > 0
+
+	body := map[string]interface{}{
+		"error":   false,
+		"code":    code,
+		"result":  batch,
+		"hasMore": hasMore,
+		"cached":  false,
+		"extra": map[string]interface{}{
+			"stats": map[string]interface{}{
+				"writesExecuted": 0,
+				"writesIgnored":  0,
+				"scannedFull":    n,
+				"scannedIndex":   0,
+				"filtered":       0,
+			},
+		},
+	}
+	if hasMore {
+		body["id"] = cur.id
+		s.cursors[cur.id] = cur
+	} else {
+		delete(s.cursors, cur.id)
+	}
+	return newResponse(code, body)
+}
+
+func (s *Server) newCursor(r *request, results []interface{}, batchSize int) *response {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	cur := &cursor{
+		id:        s.nextID(),
+		dbName:    r.dbName,
+		results:   results,
+		batchSize: batchSize,
+	}
+	resp := s.nextBatch(cur, http.StatusCreated)
+	resp.body.(map[string]interface{})["count"] = len(results)
+	return resp
+}
+
+func (s *Server) cursorAPI(r *request) (*response, error) {
+	if len(r.segs) != 2 {
+		if r.Method == http.MethodPost {
+			return nil, errNotImplemented()
+		}
+		return nil, errMethodNotAllowed()
+	}
+	cur := s.cursors[r.segs[1]]
+	if cur == nil || cur.dbName != r.dbName {
+		return nil, newError(http.StatusNotFound, errorNumCursorNotFound, "cursor not found")
+	}
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		return s.nextBatch(cur, http.StatusOK), nil
+	case http.MethodDelete:
+		delete(s.cursors, cur.id)
+		return newResponse(http.StatusAccepted, map[string]interface{}{
+			"error": false,
+			"code":  http.StatusAccepted,
+			"id":    cur.id,
+		}), nil
+	}
+	return nil, errMethodNotAllowed()
+}
+
+func (s *Server) simpleAPI(r *request) (*response, error) {
+	if len(r.segs) != 2 || r.Method != http.MethodPut {
+		return nil, errMethodNotAllowed()
+	}
+	switch r.segs[1] {
+	case "all-keys":
+		return s.allKeys(r)
+	case "any":
+		return anyDocument(r)
+	}
+	return nil, errNotImplemented()
+}
+
+func (s *Server) allKeys(r *request) (*response, error) {
+	var payload struct {
+		Collection string `json:"collection"`
+		Type       string `json:"type"`
+		BatchSize  int    `json:"batchSize"`
+	}
+	if err := r.decodeBody(&payload); err != nil {
+		return nil, err
+	}
+	coll := r.db.collections[payload.Collection]
+	if coll == nil {
+		return nil, errCollectionNotFound(payload.Collection)
+	}
+
+	keys := make([]string, 0, len(coll.docs))
+	for key := range coll.docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	results := make([]interface{}, len(keys))
+	for i, key := range keys {
+		switch payload.Type {
+		case "key":
+			results[i] = key
+		case "id":
+			results[i] = coll.name + "/" + key
+		default:
+			results[i] = "/_db/" + r.dbName + "/_api/document/" + coll.name + "/" + key
+		}
+	}
+	return s.newCursor(r, results, payload.BatchSize), nil
+}
+
+// anyDocument returns the document with the smallest key instead of a random
+// one so that tests are repeatable.
+func anyDocument(r *request) (*response, error) {
+	var payload struct {
+		Collection string `json:"collection"`
+	}
+	if err := r.decodeBody(&payload); err != nil {
+		return nil, err
+	}
+	coll := r.db.collections[payload.Collection]
+	if coll == nil {
+		return nil, errCollectionNotFound(payload.Collection)
+	}
+
+	var doc document
+	for key, d := range coll.docs {
+		if doc == nil || key < doc["_key"].(string) {
+			doc = d
+		}
+	}
+	return newResponse(http.StatusOK, map[string]interface{}{
+		"error":    false,
+		"code":     http.StatusOK,
+		"document": doc,
+	}), nil
+}
@@ -0,0 +1,243 @@
+package arangotest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	ara "github.com/hnakamur/arangogo"
+)
+
+const (
+	collectionTypeDocument = 2
+	collectionTypeEdge     = 3
+)
+
+type database struct {
+	id          string
+	name        string
+	collections map[string]*collection
+	graphs      map[string]*graph
+}
+
+type collection struct {
+	id   string
+	name string
+	typ  int
+	docs map[string]document
+}
+
+func (s *Server) newDatabase(name string) *database {
+	return &database{
+		id:          s.nextID(),
+		name:        name,
+		collections: make(map[string]*collection),
+		graphs:      make(map[string]*graph),
+	}
+}
+
+var (
+	databaseNameRegexp   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,63}$`)
+	collectionNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]{0,255}$`)
+)
+
+func (s *Server) databaseAPI(r *request) (*response, error) {
+	switch {
+	case len(r.segs) == 1 && r.Method == http.MethodGet:
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error":  false,
+			"code":   http.StatusOK,
+			"result": s.databaseNames(),
+		}), nil
+	case len(r.segs) == 1 && r.Method == http.MethodPost:
+		return s.createDatabase(r)
+	case len(r.segs) == 2 && r.segs[1] == "user" && r.Method == http.MethodGet:
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error":  false,
+			"code":   http.StatusOK,
+			"result": s.databaseNames(),
+		}), nil
+	case len(r.segs) == 2 && r.segs[1] == "current" && r.Method == http.MethodGet:
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error": false,
+			"code":  http.StatusOK,
+			"result": map[string]interface{}{
+				"id":       r.db.id,
+				"name":     r.db.name,
+				"isSystem": r.db.name == ara.SystemDatabaseName,
+			},
+		}), nil
+	case len(r.segs) == 2 && r.Method == http.MethodDelete:
+		return s.dropDatabase(r, r.segs[1])
+	}
+	return nil, errMethodNotAllowed()
+}
+
+func (s *Server) databaseNames() []string {
+	names := make([]string, 0, len(s.databases))
+	for name := range s.databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) createDatabase(r *request) (*response, error) {
+	if r.dbName != ara.SystemDatabaseName {
+		return nil, newError(http.StatusForbidden, errorNumUseSystemDatabase, "operation only allowed in system database")
+	}
+	var payload struct {
+		Name string `json:"name"`
+	}
+	if err := r.decodeBody(&payload); err != nil {
+		return nil, err
+	}
+	if !databaseNameRegexp.MatchString(payload.Name) {
+		return nil, newError(http.StatusBadRequest, errorNumDatabaseNameInvalid, "database name invalid")
+	}
+	if _, ok := s.databases[payload.Name]; ok {
+		return nil, newError(http.StatusConflict, errorNumDuplicateName, "duplicate database name '%s'", payload.Name)
+	}
+	s.databases[payload.Name] = s.newDatabase(payload.Name)
+	return newResponse(http.StatusCreated, map[string]interface{}{
+		"error":  false,
+		"code":   http.StatusCreated,
+		"result": true,
+	}), nil
+}
+
+func (s *Server) dropDatabase(r *request, name string) (*response, error) {
+	if r.dbName != ara.SystemDatabaseName || name == ara.SystemDatabaseName {
+		return nil, newError(http.StatusForbidden, errorNumUseSystemDatabase, "operation only allowed in system database")
+	}
+	if _, ok := s.databases[name]; !ok {
+		return nil, newError(http.StatusNotFound, errorNumDatabaseNotFound, "database not found")
+	}
+	delete(s.databases, name)
+	for id, cur := range s.cursors {
+		if cur.dbName == name {
+			delete(s.cursors, id)
+		}
+	}
+	return newResponse(http.StatusOK, map[string]interface{}{
+		"error":  false,
+		"code":   http.StatusOK,
+		"result": true,
+	}), nil
+}
+
+func (c *collection) info() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          c.id,
+		"name":        c.name,
+		"isSystem":    strings.HasPrefix(c.name, "_"),
+		"status":      3,
+		"type":        c.typ,
+		"waitForSync": false,
+		"isVolatile":  false,
+	}
+}
+
+func (s *Server) collectionAPI(r *request) (*response, error) {
+	if len(r.segs) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			names := make([]string, 0, len(r.db.collections))
+			for name := range r.db.collections {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			result := make([]map[string]interface{}, len(names))
+			for i, name := range names {
+				result[i] = r.db.collections[name].info()
+			}
+			return newResponse(http.StatusOK, map[string]interface{}{
+				"error":  false,
+				"code":   http.StatusOK,
+				"result": result,
+			}), nil
+		case http.MethodPost:
+			return s.createCollection(r)
+		}
+		return nil, errMethodNotAllowed()
+	}
+
+	coll := r.db.collections[r.segs[1]]
+	if coll == nil {
+		return nil, errCollectionNotFound(r.segs[1])
+	}
+	var sub string
+	if len(r.segs) == 3 {
+		sub = r.segs[2]
+	} else if len(r.segs) > 3 {
+		return nil, errUnknownPath()
+	}
+	switch {
+	case sub == "" && r.Method == http.MethodGet,
+		sub == "properties" && r.Method == http.MethodGet:
+		return newResponse(http.StatusOK, coll.info()), nil
+	case sub == "count" && r.Method == http.MethodGet:
+		info := coll.info()
+		info["count"] = len(coll.docs)
+		return newResponse(http.StatusOK, info), nil
+	case sub == "" && r.Method == http.MethodDelete:
+		delete(r.db.collections, coll.name)
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error": false,
+			"code":  http.StatusOK,
+			"id":    coll.id,
+		}), nil
+	case sub == "truncate" && r.Method == http.MethodPut:
+		coll.docs = make(map[string]document)
+		return newResponse(http.StatusOK, coll.info()), nil
+	}
+	return nil, errMethodNotAllowed()
+}
+
+func (s *Server) createCollection(r *request) (*response, error) {
+	var payload struct {
+		Name string `json:"name"`
+		Type int    `json:"type"`
+	}
+	if err := r.decodeBody(&payload); err != nil {
+		return nil, err
+	}
+	typ := payload.Type
+	if typ == 0 {
+		typ = collectionTypeDocument
+	}
+	coll, err := s.addCollection(r.db, payload.Name, typ)
+	if err != nil {
+		return nil, err
+	}
+	return newResponse(http.StatusOK, coll.info()), nil
+}
+
+func (s *Server) addCollection(db *database, name string, typ int) (*collection, error) {
+	if !collectionNameRegexp.MatchString(name) {
+		return nil, newError(http.StatusBadRequest, errorNumIllegalName, "illegal name")
+	}
+	if typ != collectionTypeDocument && typ != collectionTypeEdge {
+		return nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid collection type")
+	}
+	if _, ok := db.collections[name]; ok {
+		return nil, newError(http.StatusConflict, errorNumDuplicateName, "duplicate name: %s", name)
+	}
+	coll := &collection{
+		id:   s.nextID(),
+		name: name,
+		typ:  typ,
+		docs: make(map[string]document),
+	}
+	db.collections[name] = coll
+	return coll, nil
+}
+
+// ensureCollection returns the collection creating it if it does not exist.
+func (s *Server) ensureCollection(db *database, name string, typ int) (*collection, error) {
+	if coll := db.collections[name]; coll != nil {
+		return coll, nil
+	}
+	return s.addCollection(db, name, typ)
+}
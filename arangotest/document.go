@@ -0,0 +1,361 @@
+package arangotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type document map[string]interface{}
+
+var documentKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-:.@()+,=;$!*'%]{1,254}$`)
+
+type writeOptions struct {
+	ifMatch       string
+	ignoreRevs    bool
+	keepNull      bool
+	mergeObjects  bool
+	overwriteMode string
+}
+
+func newWriteOptions(r *request) writeOptions {
+	opts := writeOptions{
+		ifMatch:       unquoteETag(r.Header.Get("If-Match")),
+		ignoreRevs:    r.boolParam("ignoreRevs", true),
+		keepNull:      r.boolParam("keepNull", true),
+		mergeObjects:  r.boolParam("mergeObjects", true),
+		overwriteMode: r.query.Get("overwriteMode"),
+	}
+	if opts.overwriteMode == "" && r.boolParam("overwrite", false) {
+		opts.overwriteMode = "replace"
+	}
+	return opts
+}
+
+func unquoteETag(v string) string {
+	return strings.Trim(v, `"`)
+}
+
+func (d document) rev() string {
+	rev, _ := d["_rev"].(string)
+	return rev
+}
+
+func (d document) meta() map[string]interface{} {
+	return map[string]interface{}{
+		"_id":  d["_id"],
+		"_key": d["_key"],
+		"_rev": d["_rev"],
+	}
+}
+
+func toDocument(v interface{}) (document, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, newError(http.StatusBadRequest, errorNumDocumentTypeInvalid, "invalid document type")
+	}
+	return document(m), nil
+}
+
+func (s *Server) checkEdge(coll *collection, doc document) error {
+	if coll.typ != collectionTypeEdge {
+		return nil
+	}
+	for _, attr := range []string{"_from", "_to"} {
+		v, ok := doc[attr].(string)
+		if !ok || strings.Count(v, "/") != 1 || strings.HasPrefix(v, "/") || strings.HasSuffix(v, "/") {
+			return newError(http.StatusBadRequest, errorNumInvalidEdgeAttribute, "invalid edge attribute")
+		}
+	}
+	return nil
+}
+
+func (s *Server) checkRev(old, body document, opts writeOptions) error {
+	if opts.ifMatch != "" && opts.ifMatch != old.rev() {
+		return errPreconditionFailed(old)
+	}
+	if !opts.ignoreRevs && body != nil && body.rev() != "" && body.rev() != old.rev() {
+		return errPreconditionFailed(old)
+	}
+	return nil
+}
+
+// insertDocument stores a new document. oldDoc is the document overwritten
+// with the overwrite mode.
+func (s *Server) insertDocument(coll *collection, doc document, opts writeOptions) (newDoc, oldDoc document, err error) {
+	var key string
+	if v, ok := doc["_key"]; ok {
+		key, ok = v.(string)
+		if !ok || !documentKeyRegexp.MatchString(key) {
+			return nil, nil, newError(http.StatusBadRequest, errorNumDocumentKeyBad, "illegal document key")
+		}
+	} else {
+		key = s.nextID()
+	}
+
+	if old, ok := coll.docs[key]; ok {
+		switch opts.overwriteMode {
+		case "ignore":
+			return old, nil, nil
+		case "replace":
+			return s.replaceDocument(coll, key, doc, opts)
+		case "update":
+			return s.updateDocument(coll, key, doc, opts)
+		default:
+			return nil, nil, newError(http.StatusConflict, errorNumUniqueConstraintViolated,
+				"unique constraint violated - in index primary of type primary over '_key'; conflicting key: %s", key)
+		}
+	}
+
+	if err := s.checkEdge(coll, doc); err != nil {
+		return nil, nil, err
+	}
+	newDoc = make(document, len(doc)+3)
+	for k, v := range doc {
+		newDoc[k] = v
+	}
+	newDoc["_key"] = key
+	newDoc["_id"] = coll.name + "/" + key
+	newDoc["_rev"] = s.nextRev()
+	coll.docs[key] = newDoc
+	return newDoc, nil, nil
+}
+
+func (s *Server) replaceDocument(coll *collection, key string, doc document, opts writeOptions) (newDoc, oldDoc document, err error) {
+	oldDoc, ok := coll.docs[key]
+	if !ok {
+		return nil, nil, errDocumentNotFound()
+	}
+	if err := s.checkRev(oldDoc, doc, opts); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkEdge(coll, doc); err != nil {
+		return nil, nil, err
+	}
+
+	newDoc = make(document, len(doc)+3)
+	for k, v := range doc {
+		newDoc[k] = v
+	}
+	newDoc["_key"] = key
+	newDoc["_id"] = oldDoc["_id"]
+	newDoc["_rev"] = s.nextRev()
+	coll.docs[key] = newDoc
+	return newDoc, oldDoc, nil
+}
+
+func (s *Server) updateDocument(coll *collection, key string, patch document, opts writeOptions) (newDoc, oldDoc document, err error) {
+	oldDoc, ok := coll.docs[key]
+	if !ok {
+		return nil, nil, errDocumentNotFound()
+	}
+	if err := s.checkRev(oldDoc, patch, opts); err != nil {
+		return nil, nil, err
+	}
+
+	newDoc = document(mergeObject(oldDoc, patch, opts.keepNull, opts.mergeObjects))
+	newDoc["_key"] = key
+	newDoc["_id"] = oldDoc["_id"]
+	if err := s.checkEdge(coll, newDoc); err != nil {
+		return nil, nil, err
+	}
+	newDoc["_rev"] = s.nextRev()
+	coll.docs[key] = newDoc
+	return newDoc, oldDoc, nil
+}
+
+func (s *Server) removeDocument(coll *collection, key string, opts writeOptions) (oldDoc document, err error) {
+	oldDoc, ok := coll.docs[key]
+	if !ok {
+		return nil, errDocumentNotFound()
+	}
+	if err := s.checkRev(oldDoc, nil, opts); err != nil {
+		return nil, err
+	}
+	delete(coll.docs, key)
+	return oldDoc, nil
+}
+
+// mergeObject returns a new object with the attributes of patch merged into
+// old. Stored objects are never modified since they are shared between
+// revisions.
+func mergeObject(old, patch map[string]interface{}, keepNull, mergeObjects bool) map[string]interface{} {
+	merged := make(map[string]interface{}, len(old)+len(patch))
+	for k, v := range old {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		if v == nil && !keepNull {
+			delete(merged, k)
+			continue
+		}
+		if mergeObjects {
+			oldObj, ok1 := merged[k].(map[string]interface{})
+			patchObj, ok2 := v.(map[string]interface{})
+			if ok1 && ok2 {
+				merged[k] = mergeObject(oldObj, patchObj, keepNull, mergeObjects)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// writeResult returns the body of a write operation with the old and new
+// documents when they are requested.
+func writeResult(r *request, newDoc, oldDoc document) map[string]interface{} {
+	var result map[string]interface{}
+	if newDoc != nil {
+		result = newDoc.meta()
+	} else {
+		result = oldDoc.meta()
+	}
+	if oldDoc != nil && newDoc != nil {
+		result["_oldRev"] = oldDoc["_rev"]
+	}
+	if oldDoc != nil && r.boolParam("returnOld", false) {
+		result["old"] = oldDoc
+	}
+	if newDoc != nil && r.boolParam("returnNew", false) {
+		result["new"] = newDoc
+	}
+	return result
+}
+
+func (s *Server) documentAPI(r *request) (*response, error) {
+	collName := r.query.Get("collection")
+	if len(r.segs) >= 2 {
+		collName = r.segs[1]
+	}
+	coll := r.db.collections[collName]
+	if coll == nil {
+		return nil, errCollectionNotFound(collName)
+	}
+
+	switch len(r.segs) {
+	case 1, 2:
+		if r.Method != http.MethodPost {
+			return nil, errNotImplemented()
+		}
+		return s.createDocuments(r, coll)
+	case 3:
+		key := r.segs[2]
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			return s.readDocument(r, coll, key)
+		case http.MethodPut, http.MethodPatch:
+			var doc document
+			if err := r.decodeBody(&doc); err != nil {
+				return nil, err
+			}
+			var newDoc, oldDoc document
+			var err error
+			if r.Method == http.MethodPut {
+				newDoc, oldDoc, err = s.replaceDocument(coll, key, doc, newWriteOptions(r))
+			} else {
+				newDoc, oldDoc, err = s.updateDocument(coll, key, doc, newWriteOptions(r))
+			}
+			if err != nil {
+				return nil, err
+			}
+			resp := newResponse(writeCode(r), writeResult(r, newDoc, oldDoc))
+			resp.header = etagHeader(newDoc)
+			return resp, nil
+		case http.MethodDelete:
+			oldDoc, err := s.removeDocument(coll, key, newWriteOptions(r))
+			if err != nil {
+				return nil, err
+			}
+			code := http.StatusAccepted
+			if r.boolParam("waitForSync", false) {
+				code = http.StatusOK
+			}
+			return newResponse(code, writeResult(r, nil, oldDoc)), nil
+		}
+	}
+	return nil, errMethodNotAllowed()
+}
+
+func etagHeader(doc document) http.Header {
+	h := make(http.Header)
+	h.Set("ETag", `"`+doc.rev()+`"`)
+	return h
+}
+
+func (s *Server) readDocument(r *request, coll *collection, key string) (*response, error) {
+	doc, ok := coll.docs[key]
+	if !ok {
+		return nil, errDocumentNotFound()
+	}
+	if v := r.Header.Get("If-None-Match"); v != "" && unquoteETag(v) == doc.rev() {
+		return &response{code: http.StatusNotModified, header: etagHeader(doc)}, nil
+	}
+	if v := r.Header.Get("If-Match"); v != "" && unquoteETag(v) != doc.rev() {
+		// Reads also return the current revision in the ETag header.
+		resp := errPreconditionFailed(doc).response()
+		resp.header = etagHeader(doc)
+		return resp, nil
+	}
+	resp := newResponse(http.StatusOK, doc)
+	resp.header = etagHeader(doc)
+	return resp, nil
+}
+
+func (s *Server) createDocuments(r *request, coll *collection) (*response, error) {
+	var body interface{}
+	if err := r.decodeBody(&body); err != nil {
+		return nil, err
+	}
+	opts := newWriteOptions(r)
+	silent := r.boolParam("silent", false)
+
+	items, ok := body.([]interface{})
+	if !ok {
+		doc, err := toDocument(body)
+		if err != nil {
+			return nil, err
+		}
+		newDoc, oldDoc, err := s.insertDocument(coll, doc, opts)
+		if err != nil {
+			return nil, err
+		}
+		if silent {
+			return newResponse(writeCode(r), map[string]interface{}{}), nil
+		}
+		resp := newResponse(writeCode(r), writeResult(r, newDoc, oldDoc))
+		resp.header = etagHeader(newDoc)
+		return resp, nil
+	}
+
+	results := make([]interface{}, 0, len(items))
+	errorCodes := make(map[string]int)
+	for _, item := range items {
+		doc, err := toDocument(item)
+		var newDoc, oldDoc document
+		if err == nil {
+			newDoc, oldDoc, err = s.insertDocument(coll, doc, opts)
+		}
+		if err != nil {
+			apiErr := err.(*apiError)
+			errorCodes[strconv.Itoa(apiErr.errorNum)]++
+			results = append(results, apiErr.itemBody())
+			continue
+		}
+		if !silent {
+			results = append(results, writeResult(r, newDoc, oldDoc))
+		}
+	}
+	resp := newResponse(writeCode(r), results)
+	if len(errorCodes) > 0 {
+		b, err := json.Marshal(errorCodes)
+		if err != nil {
+			return nil, err
+		}
+		resp.header = make(http.Header)
+		resp.header.Set("X-Arango-Error-Codes", string(b))
+	}
+	return resp, nil
+}
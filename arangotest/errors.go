@@ -0,0 +1,117 @@
+package arangotest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// The error numbers of ArangoDB returned by the server.
+const (
+	errorNumInternal                  = 4
+	errorNumNotImplemented            = 9
+	errorNumBadParameter              = 10
+	errorNumShuttingDown              = 30
+	errorNumUnauthorized              = 11
+	errorNumHTTPNotFound              = 404
+	errorNumMethodNotAllowed          = 405
+	errorNumCorruptedJSON             = 600
+	errorNumConflict                  = 1200
+	errorNumDocumentNotFound          = 1202
+	errorNumDataSourceNotFound        = 1203
+	errorNumDuplicateName             = 1207
+	errorNumIllegalName               = 1208
+	errorNumUniqueConstraintViolated  = 1210
+	errorNumDocumentKeyBad            = 1221
+	errorNumDocumentTypeInvalid       = 1227
+	errorNumDatabaseNotFound          = 1228
+	errorNumDatabaseNameInvalid       = 1229
+	errorNumUseSystemDatabase         = 1230
+	errorNumInvalidEdgeAttribute      = 1233
+	errorNumCursorNotFound            = 1600
+	errorNumGraphCollectionMultiUse   = 1920
+	errorNumGraphNotFound             = 1924
+	errorNumGraphDuplicate            = 1925
+	errorNumGraphVertexColNotFound    = 1926
+	errorNumGraphNotInOrphans         = 1928
+	errorNumGraphCollectionInEdgeDef  = 1929
+	errorNumGraphEdgeCollNotUsed      = 1930
+	errorNumGraphCollectionInOrphans  = 1938
+	errorNumGraphInvalidEdgeReference = 1906
+)
+
+type apiError struct {
+	code     int
+	errorNum int
+	message  string
+	// extra are the fields added to the error body like _rev for
+	// precondition failures.
+	extra map[string]interface{}
+}
+
+func newError(code, errorNum int, format string, args ...interface{}) *apiError {
+	return &apiError{
+		code:     code,
+		errorNum: errorNum,
+		message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("status=%d, errorNum=%d, errorMessage=%s", e.code, e.errorNum, e.message)
+}
+
+func (e *apiError) response() *response {
+	body := map[string]interface{}{
+		"error":        true,
+		"code":         e.code,
+		"errorNum":     e.errorNum,
+		"errorMessage": e.message,
+	}
+	for k, v := range e.extra {
+		body[k] = v
+	}
+	return newResponse(e.code, body)
+}
+
+// itemBody is the error in the result of a multiple document operation.
+func (e *apiError) itemBody() map[string]interface{} {
+	return map[string]interface{}{
+		"error":        true,
+		"errorNum":     e.errorNum,
+		"errorMessage": e.message,
+	}
+}
+
+func errUnknownPath() *apiError {
+	return newError(http.StatusNotFound, errorNumHTTPNotFound, "unknown path")
+}
+
+func errMethodNotAllowed() *apiError {
+	return newError(http.StatusMethodNotAllowed, errorNumMethodNotAllowed, "method not supported")
+}
+
+func errNotImplemented() *apiError {
+	return newError(http.StatusNotImplemented, errorNumNotImplemented, "not implemented by arangotest")
+}
+
+func errCollectionNotFound(name string) *apiError {
+	return newError(http.StatusNotFound, errorNumDataSourceNotFound, "collection or view not found: %s", name)
+}
+
+func errDocumentNotFound() *apiError {
+	return newError(http.StatusNotFound, errorNumDocumentNotFound, "document not found")
+}
+
+func errGraphNotFound(name string) *apiError {
+	return newError(http.StatusNotFound, errorNumGraphNotFound, "graph '%s' not found", name)
+}
+
+func errPreconditionFailed(doc document) *apiError {
+	e := newError(http.StatusPreconditionFailed, errorNumConflict, "conflict, _rev values do not match")
+	e.extra = map[string]interface{}{
+		"_id":  doc["_id"],
+		"_key": doc["_key"],
+		"_rev": doc["_rev"],
+	}
+	return e
+}
@@ -0,0 +1,250 @@
+package arangotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	ara "github.com/hnakamur/arangogo"
+)
+
+// Fixture is the content of a golden file which has the requests and
+// responses recorded with a server of ArangoVersion.
+type Fixture struct {
+	ArangoVersion int           `json:"arangoVersion"`
+	Interactions  []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	// Body is set for JSON bodies, and RawBody is set for other bodies.
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody []byte          `json:"rawBody,omitempty"`
+}
+
+type FixtureResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	RawBody    []byte          `json:"rawBody,omitempty"`
+}
+
+// FixturePath returns the path of the golden file for the name and the
+// ArangoDB version which is the value of Config.ArangoVersion, so that the
+// responses of different server versions are kept side by side.
+func FixturePath(dir, name string, arangoVersion int) string {
+	return filepath.Join(dir, strconv.Itoa(arangoVersion), name+".json")
+}
+
+// Recorder records the requests and the responses passing its middleware
+// and saves them to a golden file.
+//
+// Requests to /_api/batch are not recorded. The parts of a batch pass
+// through the middlewares as separate requests and are recorded instead, so
+// the Replayer returns their responses without sending the batch.
+type Recorder struct {
+	path    string
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+func NewRecorder(path string, arangoVersion int) *Recorder {
+	return &Recorder{
+		path:    path,
+		fixture: Fixture{ArangoVersion: arangoVersion},
+	}
+}
+
+func (r *Recorder) Middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		resp, err := next(req)
+		if err != nil {
+			return nil, err
+		}
+		fr := newFixtureRequest(req)
+		if isBatchPath(fr.Path) {
+			return resp, nil
+		}
+		header := resp.Header.Clone()
+		header.Del("Date")
+		i := Interaction{
+			Request: fr,
+			Response: FixtureResponse{
+				StatusCode: resp.StatusCode,
+				Header:     header,
+			},
+		}
+		i.Response.Body, i.Response.RawBody = splitBody(resp.Body)
+
+		r.mu.Lock()
+		r.fixture.Interactions = append(r.fixture.Interactions, i)
+		r.mu.Unlock()
+		return resp, nil
+	}
+}
+
+// Save writes the recorded interactions to the golden file creating the
+// directory if needed.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(r.path), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create fixture directory: %v", err)
+	}
+	err = ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write fixture: %v", err)
+	}
+	return nil
+}
+
+// Replayer returns the recorded responses without sending requests.
+// A request is matched on the method, the path, the query and the body
+// with JSON bodies compared after normalization. Each recorded response is
+// returned once in the recorded order, so repeated requests get the
+// responses in the same order as they were recorded.
+type Replayer struct {
+	mu      sync.Mutex
+	fixture Fixture
+	used    []bool
+}
+
+// NewReplayer loads the golden file. arangoVersion must be the version the
+// fixture was recorded with.
+func NewReplayer(path string, arangoVersion int) (*Replayer, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %v", err)
+	}
+	var f Fixture
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode fixture: %v", err)
+	}
+	if f.ArangoVersion != arangoVersion {
+		return nil, fmt.Errorf("fixture version mismatch: fixture=%d, requested=%d", f.ArangoVersion, arangoVersion)
+	}
+	return &Replayer{
+		fixture: f,
+		used:    make([]bool, len(f.Interactions)),
+	}, nil
+}
+
+func (p *Replayer) Middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		key := newFixtureRequest(req)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for i, in := range p.fixture.Interactions {
+			if p.used[i] || !in.Request.matches(key) {
+				continue
+			}
+			p.used[i] = true
+			body := []byte(in.Response.Body)
+			if in.Response.RawBody != nil {
+				body = in.Response.RawBody
+			}
+			return &ara.Response{
+				StatusCode: in.Response.StatusCode,
+				Header:     in.Response.Header.Clone(),
+				Body:       body,
+			}, nil
+		}
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.Path)
+	}
+}
+
+// Unused returns the number of recorded interactions which were not
+// replayed. It is for tests to check all expected requests were sent.
+func (p *Replayer) Unused() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, used := range p.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+func newFixtureRequest(req *ara.Request) FixtureRequest {
+	path, rawQuery := req.Path, ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, rawQuery = path[:i], path[i+1:]
+	}
+	r := FixtureRequest{
+		Method: req.Method,
+		Path:   path,
+		Query:  normalizeQuery(rawQuery),
+	}
+	r.Body, r.RawBody = splitBody(req.Payload)
+	return r
+}
+
+func (r FixtureRequest) matches(other FixtureRequest) bool {
+	return r.Method == other.Method && r.Path == other.Path && r.Query == other.Query &&
+		bytes.Equal(normalizeJSON(r.Body), normalizeJSON(other.Body)) &&
+		bytes.Equal(r.RawBody, other.RawBody)
+}
+
+// normalizeQuery sorts the query parameters by their names.
+func normalizeQuery(rawQuery string) string {
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	return q.Encode()
+}
+
+// splitBody returns a JSON body as json.RawMessage and other bodies as raw
+// bytes.
+func splitBody(b []byte) (json.RawMessage, []byte) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if json.Valid(b) {
+		return normalizeJSON(b), nil
+	}
+	return nil, b
+}
+
+// normalizeJSON re-encodes a JSON value so that the object keys are sorted
+// and the white spaces are removed.
+func normalizeJSON(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return b
+	}
+	nb, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return nb
+}
@@ -0,0 +1,180 @@
+package arangotest_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+const fixtureVersion = 31100
+
+// pathRecorder is a middleware which keeps the paths of the requests.
+type pathRecorder struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (r *pathRecorder) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		r.mu.Lock()
+		r.paths = append(r.paths, req.Method+" "+req.Path)
+		r.mu.Unlock()
+		return next(req)
+	}
+}
+
+func (r *pathRecorder) count(path string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, p := range r.paths {
+		if p == path {
+			n++
+		}
+	}
+	return n
+}
+
+type batchResult struct {
+	created   ara.Document
+	createdRC int
+	read      user
+	readRC    int
+	missingRC int
+	errs      [3]error
+}
+
+// sendBatch creates bob, reads alice and reads a missing document in a batch.
+func sendBatch(t *testing.T, c *ara.Connection) batchResult {
+	t.Helper()
+	var r batchResult
+	b := c.NewBatch(testDBName)
+	b.Add(func(c ara.Client) {
+		r.created, r.createdRC, r.errs[0] = c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "bob", "name": "Bob"}, nil, nil, nil)
+	})
+	b.Add(func(c ara.Client) {
+		r.readRC, r.errs[1] = c.ReadDocument(testDBName, "users/alice", nil, &r.read)
+	})
+	b.Add(func(c ara.Client) {
+		r.missingRC, r.errs[2] = c.ReadDocument(testDBName, "users/missing", nil, nil)
+	})
+	if _, err := b.Send(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func checkBatchResult(t *testing.T, r batchResult) {
+	t.Helper()
+	if r.errs[0] != nil || r.createdRC != http.StatusAccepted || r.created.ID != "users/bob" {
+		t.Errorf("create: rc = %d, err = %v, doc = %+v", r.createdRC, r.errs[0], r.created)
+	}
+	if r.errs[1] != nil || r.readRC != http.StatusOK || r.read.Name != "Alice" {
+		t.Errorf("read: rc = %d, err = %v, doc = %+v", r.readRC, r.errs[1], r.read)
+	}
+	checkError(t, r.errs[2], r.missingRC, http.StatusNotFound, 1202)
+}
+
+func TestRecordAndReplayBatch(t *testing.T) {
+	s, c, _ := setup(t)
+	createUser(t, c, "alice", "Alice")
+	path := arangotest.FixturePath(t.TempDir(), "batch", fixtureVersion)
+
+	recorder := arangotest.NewRecorder(path, fixtureVersion)
+	paths := new(pathRecorder)
+	config := s.ConnectionConfig()
+	config.ArangoVersion = fixtureVersion
+	config.Middlewares = []ara.Middleware{paths.middleware, recorder.Middleware}
+	recorded := sendBatch(t, newConnection(t, config))
+	checkBatchResult(t, recorded)
+	if n := paths.count("POST /_db/" + testDBName + "/_api/batch"); n != 1 {
+		t.Errorf("batch requests = %d, want 1; requests = %v", n, paths.paths)
+	}
+	if n := paths.count("GET /_db/" + testDBName + "/_api/document/users/alice"); n != 1 {
+		t.Errorf("parts seen by the middleware = %v", paths.paths)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := arangotest.NewReplayer(path, fixtureVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	config.Middlewares = []ara.Middleware{replayer.Middleware}
+	replayed := sendBatch(t, newConnection(t, config))
+	checkBatchResult(t, replayed)
+	if replayed.created.Rev != recorded.created.Rev || replayed.read.Rev != recorded.read.Rev {
+		t.Errorf("replayed = %+v, recorded = %+v", replayed, recorded)
+	}
+	if n := replayer.Unused(); n != 0 {
+		t.Errorf("unused interactions = %d, want 0", n)
+	}
+}
+
+func TestReplayerMatchesNormalizedRequests(t *testing.T) {
+	s, c, _ := setup(t)
+	path := arangotest.FixturePath(t.TempDir(), "documents", fixtureVersion)
+
+	recorder := arangotest.NewRecorder(path, fixtureVersion)
+	config := s.ConnectionConfig()
+	config.ArangoVersion = fixtureVersion
+	config.Middlewares = []ara.Middleware{recorder.Middleware}
+	rc := newConnection(t, config)
+	doc, _, err := rc.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "alice", "name": "Alice", "age": 30},
+		&ara.CreateDocumentConfig{ReturnNew: ara.TruePtr(), WaitForSync: ara.TruePtr()}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := rc.UpdateDocument(testDBName, "users/alice", map[string]interface{}{"age": 31 + i}, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	replayer, err := arangotest.NewReplayer(path, fixtureVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Middlewares = []ara.Middleware{replayer.Middleware}
+	c = newConnection(t, config)
+
+	// The keys of the body and the query parameters are in a different order.
+	got, rc2, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"age": 30, "name": "Alice", "_key": "alice"},
+		&ara.CreateDocumentConfig{WaitForSync: ara.TruePtr(), ReturnNew: ara.TruePtr()}, nil, nil)
+	if err != nil || rc2 != http.StatusCreated || got != doc {
+		t.Fatalf("rc = %d, err = %v, doc = %+v, want %+v", rc2, err, got, doc)
+	}
+	first, _, err := c.UpdateDocument(testDBName, "users/alice", map[string]interface{}{"age": 31}, nil, nil, nil)
+	if err != nil || first.OldRev != doc.Rev {
+		t.Fatalf("err = %v, result = %+v", err, first)
+	}
+	if _, _, err := c.UpdateDocument(testDBName, "users/alice", map[string]interface{}{"age": 31}, nil, nil, nil); err == nil {
+		t.Error("got no error for a request with a different body")
+	}
+	second, _, err := c.UpdateDocument(testDBName, "users/alice", map[string]interface{}{"age": 32}, nil, nil, nil)
+	if err != nil || second.OldRev != first.Rev {
+		t.Fatalf("err = %v, result = %+v", err, second)
+	}
+	if n := replayer.Unused(); n != 0 {
+		t.Errorf("unused interactions = %d, want 0", n)
+	}
+	if _, err := arangotest.NewReplayer(path, 30700); err == nil {
+		t.Error("got no error for a different version")
+	}
+}
+
+func TestFixturePath(t *testing.T) {
+	if got, want := arangotest.FixturePath("testdata", "users", 31100), filepath.Join("testdata", "31100", "users.json"); got != want {
+		t.Errorf("path = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,546 @@
+package arangotest
+
+import (
+	"net/http"
+	"sort"
+
+	ara "github.com/hnakamur/arangogo"
+)
+
+type graph struct {
+	name              string
+	rev               string
+	edgeDefinitions   []ara.EdgeDefinition
+	orphanCollections []string
+}
+
+func (g *graph) body() map[string]interface{} {
+	edgeDefinitions := g.edgeDefinitions
+	if edgeDefinitions == nil {
+		edgeDefinitions = []ara.EdgeDefinition{}
+	}
+	orphans := g.orphanCollections
+	if orphans == nil {
+		orphans = []string{}
+	}
+	return map[string]interface{}{
+		"name":              g.name,
+		"_key":              g.name,
+		"_id":               "_graphs/" + g.name,
+		"_rev":              g.rev,
+		"edgeDefinitions":   edgeDefinitions,
+		"orphanCollections": orphans,
+	}
+}
+
+func graphResponse(code int, g *graph) *response {
+	return newResponse(code, map[string]interface{}{
+		"error": false,
+		"code":  code,
+		"graph": g.body(),
+	})
+}
+
+func (g *graph) edgeDefinition(collName string) int {
+	for i, def := range g.edgeDefinitions {
+		if def.Collection == collName {
+			return i
+		}
+	}
+	return -1
+}
+
+// usedInEdgeDefinitions returns whether the collection is a vertex
+// collection of an edge definition.
+func (g *graph) usedInEdgeDefinitions(collName string) bool {
+	for _, def := range g.edgeDefinitions {
+		if contains(def.From, collName) || contains(def.To, collName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *graph) vertexCollections() []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(colls []string) {
+		for _, name := range colls {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	for _, def := range g.edgeDefinitions {
+		add(def.From)
+		add(def.To)
+	}
+	add(g.orphanCollections)
+	sort.Strings(names)
+	return names
+}
+
+func (g *graph) edgeCollections() []string {
+	names := make([]string, len(g.edgeDefinitions))
+	for i, def := range g.edgeDefinitions {
+		names[i] = def.Collection
+	}
+	sort.Strings(names)
+	return names
+}
+
+// updateOrphans removes the orphan collections which are used in edge
+// definitions, and adds the vertex collections no longer used in edge
+// definitions to orphans like ArangoDB does.
+func (g *graph) updateOrphans(before []string) {
+	var orphans []string
+	for _, name := range g.orphanCollections {
+		if !g.usedInEdgeDefinitions(name) {
+			orphans = append(orphans, name)
+		}
+	}
+	for _, name := range before {
+		if !g.usedInEdgeDefinitions(name) && !contains(orphans, name) {
+			orphans = append(orphans, name)
+		}
+	}
+	g.orphanCollections = orphans
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) ensureEdgeDefinitionCollections(db *database, def ara.EdgeDefinition) error {
+	if _, err := s.ensureCollection(db, def.Collection, collectionTypeEdge); err != nil {
+		return err
+	}
+	for _, names := range [][]string{def.From, def.To} {
+		for _, name := range names {
+			if _, err := s.ensureCollection(db, name, collectionTypeDocument); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) gharialAPI(r *request) (*response, error) {
+	if len(r.segs) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			names := make([]string, 0, len(r.db.graphs))
+			for name := range r.db.graphs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			graphs := make([]map[string]interface{}, len(names))
+			for i, name := range names {
+				graphs[i] = r.db.graphs[name].body()
+			}
+			return newResponse(http.StatusOK, map[string]interface{}{
+				"error":  false,
+				"code":   http.StatusOK,
+				"graphs": graphs,
+			}), nil
+		case http.MethodPost:
+			return s.createGraph(r)
+		}
+		return nil, errMethodNotAllowed()
+	}
+
+	g := r.db.graphs[r.segs[1]]
+	if g == nil {
+		return nil, errGraphNotFound(r.segs[1])
+	}
+	if len(r.segs) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			return graphResponse(http.StatusOK, g), nil
+		case http.MethodDelete:
+			return s.dropGraph(r, g)
+		}
+		return nil, errMethodNotAllowed()
+	}
+
+	switch r.segs[2] {
+	case "vertex":
+		switch len(r.segs) {
+		case 3:
+			return s.vertexCollectionsAPI(r, g)
+		case 4:
+			if r.Method == http.MethodPost {
+				return s.createGraphDocument(r, g, "vertex", r.segs[3])
+			}
+			return s.vertexCollectionsAPI(r, g)
+		case 5:
+			return s.graphDocumentAPI(r, g, "vertex", r.segs[3], r.segs[4])
+		}
+	case "edge":
+		switch len(r.segs) {
+		case 3:
+			return s.edgeDefinitionsAPI(r, g)
+		case 4:
+			if r.Method == http.MethodPost {
+				return s.createGraphDocument(r, g, "edge", r.segs[3])
+			}
+			return s.edgeDefinitionsAPI(r, g)
+		case 5:
+			return s.graphDocumentAPI(r, g, "edge", r.segs[3], r.segs[4])
+		}
+	}
+	return nil, errUnknownPath()
+}
+
+func (s *Server) createGraph(r *request) (*response, error) {
+	var payload struct {
+		Name              string               `json:"name"`
+		EdgeDefinitions   []ara.EdgeDefinition `json:"edgeDefinitions"`
+		OrphanCollections []string             `json:"orphanCollections"`
+	}
+	if err := r.decodeBody(&payload); err != nil {
+		return nil, err
+	}
+	if payload.Name == "" {
+		return nil, newError(http.StatusBadRequest, errorNumBadParameter, "graph name is missing")
+	}
+	if _, ok := r.db.graphs[payload.Name]; ok {
+		return nil, newError(http.StatusConflict, errorNumGraphDuplicate, "graph already exists")
+	}
+
+	g := &graph{name: payload.Name}
+	for _, def := range payload.EdgeDefinitions {
+		if g.edgeDefinition(def.Collection) >= 0 {
+			return nil, newError(http.StatusBadRequest, errorNumGraphCollectionMultiUse, "multi use of edge collection in edge def")
+		}
+		if err := s.ensureEdgeDefinitionCollections(r.db, def); err != nil {
+			return nil, err
+		}
+		g.edgeDefinitions = append(g.edgeDefinitions, def)
+	}
+	for _, name := range payload.OrphanCollections {
+		if _, err := s.ensureCollection(r.db, name, collectionTypeDocument); err != nil {
+			return nil, err
+		}
+	}
+	g.orphanCollections = payload.OrphanCollections
+	g.updateOrphans(nil)
+	g.rev = s.nextRev()
+	r.db.graphs[g.name] = g
+	return graphResponse(writeCode(r), g), nil
+}
+
+func (s *Server) dropGraph(r *request, g *graph) (*response, error) {
+	delete(r.db.graphs, g.name)
+	if r.boolParam("dropCollections", false) {
+		used := make(map[string]bool)
+		for _, other := range r.db.graphs {
+			for _, name := range other.vertexCollections() {
+				used[name] = true
+			}
+			for _, name := range other.edgeCollections() {
+				used[name] = true
+			}
+		}
+		for _, names := range [][]string{g.vertexCollections(), g.edgeCollections()} {
+			for _, name := range names {
+				if !used[name] {
+					delete(r.db.collections, name)
+				}
+			}
+		}
+	}
+	code := writeCode(r)
+	return newResponse(code, map[string]interface{}{
+		"error":   false,
+		"code":    code,
+		"removed": true,
+	}), nil
+}
+
+func (s *Server) vertexCollectionsAPI(r *request, g *graph) (*response, error) {
+	switch {
+	case len(r.segs) == 3 && r.Method == http.MethodGet:
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error":       false,
+			"code":        http.StatusOK,
+			"collections": g.vertexCollections(),
+		}), nil
+	case len(r.segs) == 3 && r.Method == http.MethodPost:
+		var payload struct {
+			Collection string `json:"collection"`
+		}
+		if err := r.decodeBody(&payload); err != nil {
+			return nil, err
+		}
+		if contains(g.orphanCollections, payload.Collection) {
+			return nil, newError(http.StatusBadRequest, errorNumGraphCollectionInOrphans, "collection already used in orphans")
+		}
+		if g.usedInEdgeDefinitions(payload.Collection) {
+			return nil, newError(http.StatusBadRequest, errorNumGraphCollectionInEdgeDef, "collection already used in edge def")
+		}
+		if _, err := s.ensureCollection(r.db, payload.Collection, collectionTypeDocument); err != nil {
+			return nil, err
+		}
+		g.orphanCollections = append(g.orphanCollections, payload.Collection)
+		g.rev = s.nextRev()
+		return graphResponse(writeCode(r), g), nil
+	case len(r.segs) == 4 && r.Method == http.MethodDelete:
+		name := r.segs[3]
+		if !contains(g.orphanCollections, name) {
+			return nil, newError(http.StatusBadRequest, errorNumGraphNotInOrphans, "not in orphan collection")
+		}
+		var orphans []string
+		for _, n := range g.orphanCollections {
+			if n != name {
+				orphans = append(orphans, n)
+			}
+		}
+		g.orphanCollections = orphans
+		if r.boolParam("dropCollection", false) {
+			delete(r.db.collections, name)
+		}
+		g.rev = s.nextRev()
+		return graphResponse(writeCode(r), g), nil
+	}
+	return nil, errMethodNotAllowed()
+}
+
+func (s *Server) edgeDefinitionsAPI(r *request, g *graph) (*response, error) {
+	switch {
+	case len(r.segs) == 3 && r.Method == http.MethodGet:
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error":       false,
+			"code":        http.StatusOK,
+			"collections": g.edgeCollections(),
+		}), nil
+	case len(r.segs) == 3 && r.Method == http.MethodPost:
+		var def ara.EdgeDefinition
+		if err := r.decodeBody(&def); err != nil {
+			return nil, err
+		}
+		if g.edgeDefinition(def.Collection) >= 0 {
+			return nil, newError(http.StatusBadRequest, errorNumGraphCollectionMultiUse, "multi use of edge collection in edge def")
+		}
+		if err := s.ensureEdgeDefinitionCollections(r.db, def); err != nil {
+			return nil, err
+		}
+		g.edgeDefinitions = append(g.edgeDefinitions, def)
+		g.updateOrphans(nil)
+		g.rev = s.nextRev()
+		return graphResponse(writeCode(r), g), nil
+	case len(r.segs) == 4 && (r.Method == http.MethodPut || r.Method == http.MethodDelete):
+		i := g.edgeDefinition(r.segs[3])
+		if i < 0 {
+			return nil, newError(http.StatusNotFound, errorNumGraphEdgeCollNotUsed, "edge collection not used in graph")
+		}
+		before := g.vertexCollections()
+		if r.Method == http.MethodPut {
+			var def ara.EdgeDefinition
+			if err := r.decodeBody(&def); err != nil {
+				return nil, err
+			}
+			if def.Collection != r.segs[3] {
+				return nil, newError(http.StatusBadRequest, errorNumBadParameter, "edge definition collection does not match")
+			}
+			if err := s.ensureEdgeDefinitionCollections(r.db, def); err != nil {
+				return nil, err
+			}
+			g.edgeDefinitions[i] = def
+		} else {
+			g.edgeDefinitions = append(g.edgeDefinitions[:i:i], g.edgeDefinitions[i+1:]...)
+			if r.boolParam("dropCollections", false) {
+				delete(r.db.collections, r.segs[3])
+			}
+		}
+		g.updateOrphans(before)
+		g.rev = s.nextRev()
+		return graphResponse(writeCode(r), g), nil
+	}
+	return nil, errMethodNotAllowed()
+}
+
+// graphCollection returns the collection for vertex or edge operations
+// checking it is a part of the graph.
+func (s *Server) graphCollection(r *request, g *graph, kind, collName string) (*collection, error) {
+	if kind == "vertex" {
+		if !contains(g.vertexCollections(), collName) {
+			return nil, newError(http.StatusNotFound, errorNumGraphVertexColNotFound, "vertex collection does not exist or is not part of the graph")
+		}
+	} else if g.edgeDefinition(collName) < 0 {
+		return nil, newError(http.StatusNotFound, errorNumGraphEdgeCollNotUsed, "edge collection not used in graph")
+	}
+	coll := r.db.collections[collName]
+	if coll == nil {
+		return nil, errCollectionNotFound(collName)
+	}
+	return coll, nil
+}
+
+// checkEdgeVertices checks that the vertices of the edge exist in the
+// vertex collections of the edge definition.
+func (s *Server) checkEdgeVertices(r *request, g *graph, collName string, doc document) error {
+	def := g.edgeDefinitions[g.edgeDefinition(collName)]
+	for _, v := range []struct {
+		attr  string
+		colls []string
+	}{
+		{"_from", def.From},
+		{"_to", def.To},
+	} {
+		id, _ := doc[v.attr].(string)
+		vertexColl, key := splitHandle(id)
+		if !contains(v.colls, vertexColl) {
+			return newError(http.StatusBadRequest, errorNumGraphInvalidEdgeReference, "invalid edge between %s and %s", doc["_from"], doc["_to"])
+		}
+		coll := r.db.collections[vertexColl]
+		if coll == nil {
+			return errCollectionNotFound(vertexColl)
+		}
+		if _, ok := coll.docs[key]; !ok {
+			return errDocumentNotFound()
+		}
+	}
+	return nil
+}
+
+func splitHandle(handle string) (collName, key string) {
+	for i := 0; i < len(handle); i++ {
+		if handle[i] == '/' {
+			return handle[:i], handle[i+1:]
+		}
+	}
+	return "", handle
+}
+
+// graphDocumentResult returns the body of a vertex or edge operation which
+// has the document metadata under kind and the old and new documents at the
+// top level.
+func graphDocumentResult(r *request, kind string, code int, newDoc, oldDoc document) map[string]interface{} {
+	meta := writeResult(r, newDoc, oldDoc)
+	body := map[string]interface{}{
+		"error": false,
+		"code":  code,
+	}
+	for _, k := range []string{"old", "new"} {
+		if v, ok := meta[k]; ok {
+			body[k] = v
+			delete(meta, k)
+		}
+	}
+	if newDoc != nil {
+		body[kind] = meta
+	} else {
+		body["removed"] = true
+	}
+	return body
+}
+
+func (s *Server) createGraphDocument(r *request, g *graph, kind, collName string) (*response, error) {
+	coll, err := s.graphCollection(r, g, kind, collName)
+	if err != nil {
+		return nil, err
+	}
+	var doc document
+	if err := r.decodeBody(&doc); err != nil {
+		return nil, err
+	}
+	if kind == "edge" {
+		if err := s.checkEdge(coll, doc); err != nil {
+			return nil, err
+		}
+		if err := s.checkEdgeVertices(r, g, collName, doc); err != nil {
+			return nil, err
+		}
+	}
+	newDoc, _, err := s.insertDocument(coll, doc, writeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	code := writeCode(r)
+	resp := newResponse(code, graphDocumentResult(r, kind, code, newDoc, nil))
+	resp.header = etagHeader(newDoc)
+	return resp, nil
+}
+
+func (s *Server) graphDocumentAPI(r *request, g *graph, kind, collName, key string) (*response, error) {
+	coll, err := s.graphCollection(r, g, kind, collName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		resp, err := s.readDocument(r, coll, key)
+		if err != nil {
+			return nil, err
+		}
+		if resp.code == http.StatusOK {
+			resp.body = map[string]interface{}{
+				"error": false,
+				"code":  http.StatusOK,
+				kind:    resp.body,
+			}
+		}
+		return resp, nil
+	case http.MethodPut, http.MethodPatch:
+		var doc document
+		if err := r.decodeBody(&doc); err != nil {
+			return nil, err
+		}
+		var newDoc, oldDoc document
+		if r.Method == http.MethodPut {
+			if kind == "edge" {
+				if err := s.checkEdge(coll, doc); err != nil {
+					return nil, err
+				}
+				if err := s.checkEdgeVertices(r, g, collName, doc); err != nil {
+					return nil, err
+				}
+			}
+			newDoc, oldDoc, err = s.replaceDocument(coll, key, doc, newWriteOptions(r))
+		} else {
+			newDoc, oldDoc, err = s.updateDocument(coll, key, doc, newWriteOptions(r))
+		}
+		if err != nil {
+			return nil, err
+		}
+		code := writeCode(r)
+		resp := newResponse(code, graphDocumentResult(r, kind, code, newDoc, oldDoc))
+		resp.header = etagHeader(newDoc)
+		return resp, nil
+	case http.MethodDelete:
+		oldDoc, err := s.removeDocument(coll, key, newWriteOptions(r))
+		if err != nil {
+			return nil, err
+		}
+		if kind == "vertex" {
+			s.removeConnectedEdges(r.db, g, collName+"/"+key)
+		}
+		code := writeCode(r)
+		return newResponse(code, graphDocumentResult(r, kind, code, nil, oldDoc)), nil
+	}
+	return nil, errMethodNotAllowed()
+}
+
+// removeConnectedEdges removes the edges of the graph connected to the
+// removed vertex.
+func (s *Server) removeConnectedEdges(db *database, g *graph, vertexID string) {
+	for _, name := range g.edgeCollections() {
+		coll := db.collections[name]
+		if coll == nil {
+			continue
+		}
+		for key, doc := range coll.docs {
+			if doc["_from"] == vertexID || doc["_to"] == vertexID {
+				delete(coll.docs, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package arangotest_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+)
+
+const testGraphName = "social"
+
+type person struct {
+	ara.Document
+	Name string `json:"name"`
+}
+
+type relation struct {
+	ara.Document
+	From  string `json:"_from"`
+	To    string `json:"_to"`
+	Since int    `json:"since,omitempty"`
+}
+
+// setupGraph creates a graph with persons connected by knows edges.
+func setupGraph(t *testing.T) (*ara.Connection, *responseRecorder) {
+	t.Helper()
+	_, c, rec := setup(t)
+	_, _, err := c.CreateGraph(testDBName, ara.CreateGraphConfig{
+		Name: testGraphName,
+		EdgeDefinitions: []ara.EdgeDefinition{
+			{Collection: "knows", From: []string{"persons"}, To: []string{"persons"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, rec
+}
+
+func createPerson(t *testing.T, c *ara.Connection, key, name string) ara.CreateVertexResult {
+	t.Helper()
+	r, _, err := c.CreateVertex(testDBName, testGraphName, "persons", map[string]interface{}{"_key": key, "name": name}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestGraphNotFound(t *testing.T) {
+	_, c, _ := setup(t)
+
+	_, rc, err := c.GetGraph(testDBName, "missing")
+	checkError(t, err, rc, http.StatusNotFound, 1924)
+
+	_, rc, err = c.CreateVertex(testDBName, "missing", "persons", map[string]interface{}{}, nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1924)
+}
+
+func TestCreateGraph(t *testing.T) {
+	c, _ := setupGraph(t)
+
+	g, rc, err := c.GetGraph(testDBName, testGraphName)
+	if err != nil || rc != http.StatusOK {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if g.Name != testGraphName || g.ID != "_graphs/"+testGraphName || len(g.EdgeDefinitions) != 1 || len(g.OrphanCollections) != 0 {
+		t.Errorf("graph = %+v", g)
+	}
+
+	_, rc, err = c.CreateGraph(testDBName, ara.CreateGraphConfig{Name: testGraphName})
+	checkError(t, err, rc, http.StatusConflict, 1925)
+
+	cols, _, err := c.ListCollections(testDBName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]int)
+	for _, col := range cols {
+		types[col.Name] = col.Type
+	}
+	if types["persons"] != 2 || types["knows"] != 3 {
+		t.Errorf("collection types = %v, want persons 2 and knows 3", types)
+	}
+}
+
+func TestVertex(t *testing.T) {
+	c, rec := setupGraph(t)
+
+	var created person
+	r, rc, err := c.CreateVertex(testDBName, testGraphName, "persons", map[string]interface{}{"_key": "alice", "name": "Alice"},
+		&ara.CreateVertexConfig{ReturnNew: ara.TruePtr()}, &created)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if r.ID != "persons/alice" || created.Name != "Alice" || created.Rev != r.Rev {
+		t.Errorf("result = %+v, new = %+v", r, created)
+	}
+
+	var got person
+	getRes, rc, err := c.GetVertex(testDBName, testGraphName, "persons", "alice", nil, &got)
+	if err != nil || rc != http.StatusOK || got.Name != "Alice" || getRes.Rev != r.Rev {
+		t.Fatalf("rc = %d, err = %v, result = %+v, vertex = %+v", rc, err, getRes, got)
+	}
+
+	getRes, rc, err = c.GetVertex(testDBName, testGraphName, "persons", "alice", &ara.GetVertexConfig{IfNoneMatch: `"` + r.Rev + `"`}, nil)
+	if err != nil || rc != http.StatusNotModified || !getRes.NotModified {
+		t.Errorf("rc = %d, err = %v, result = %+v, want not modified", rc, err, getRes)
+	}
+	getRes, rc, err = c.GetVertex(testDBName, testGraphName, "persons", "alice", &ara.GetVertexConfig{IfMatch: `"_old"`}, nil)
+	if rc != http.StatusPreconditionFailed || !getRes.PreconditionFailed || getRes.Rev != r.Rev {
+		t.Errorf("rc = %d, err = %v, result = %+v, want precondition failed", rc, err, getRes)
+	}
+	if body := errorBody(t, rec.last()); body["errorNum"] != float64(1200) || body["_rev"] != r.Rev {
+		t.Errorf("body = %v, want errorNum 1200 and the current revision", body)
+	}
+
+	var oldVertex, newVertex person
+	m, rc, err := c.ModifyVertex(testDBName, testGraphName, "persons", "alice", map[string]interface{}{"name": "Alice 2"},
+		&ara.ModifyVertexConfig{ReturnOld: ara.TruePtr(), ReturnNew: ara.TruePtr()}, &oldVertex, &newVertex)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if m.OldRev != r.Rev || oldVertex.Name != "Alice" || newVertex.Name != "Alice 2" || newVertex.Rev != m.Rev {
+		t.Errorf("result = %+v, old = %+v, new = %+v", m, oldVertex, newVertex)
+	}
+
+	_, rc, err = c.ReplaceVertex(testDBName, testGraphName, "persons", "alice", map[string]interface{}{"name": "x"},
+		&ara.ReplaceVertexConfig{IfMatch: r.Rev}, nil, nil)
+	checkError(t, err, rc, http.StatusPreconditionFailed, 1200)
+
+	rep, rc, err := c.ReplaceVertex(testDBName, testGraphName, "persons", "alice", map[string]interface{}{"name": "Alice 3"},
+		&ara.ReplaceVertexConfig{IfMatch: m.Rev, WaitForSync: ara.TruePtr()}, nil, nil)
+	if err != nil || rc != http.StatusCreated || rep.OldRev != m.Rev {
+		t.Fatalf("rc = %d, err = %v, result = %+v", rc, err, rep)
+	}
+
+	_, rc, err = c.CreateVertex(testDBName, testGraphName, "others", map[string]interface{}{}, nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1926)
+	_, rc, err = c.GetVertex(testDBName, testGraphName, "persons", "missing", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+}
+
+func TestEdge(t *testing.T) {
+	c, _ := setupGraph(t)
+	createPerson(t, c, "alice", "Alice")
+	createPerson(t, c, "bob", "Bob")
+
+	var created relation
+	r, rc, err := c.CreateEdge(testDBName, testGraphName, "knows",
+		map[string]interface{}{"_key": "ab", "_from": "persons/alice", "_to": "persons/bob", "since": 2010},
+		&ara.CreateEdgeConfig{ReturnNew: ara.TruePtr()}, &created)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if r.ID != "knows/ab" || created.From != "persons/alice" || created.Since != 2010 {
+		t.Errorf("result = %+v, new = %+v", r, created)
+	}
+
+	var got relation
+	getRes, rc, err := c.GetEdge(testDBName, testGraphName, "knows", "ab", nil, &got)
+	if err != nil || rc != http.StatusOK || got.To != "persons/bob" || getRes.Rev != r.Rev {
+		t.Fatalf("rc = %d, err = %v, result = %+v, edge = %+v", rc, err, getRes, got)
+	}
+	getRes, rc, _ = c.GetEdge(testDBName, testGraphName, "knows", "ab", &ara.GetEdgeConfig{IfNoneMatch: `"` + r.Rev + `"`}, nil)
+	if rc != http.StatusNotModified || !getRes.NotModified {
+		t.Errorf("rc = %d, result = %+v, want not modified", rc, getRes)
+	}
+
+	var oldEdge, newEdge relation
+	rep, rc, err := c.ReplaceEdge(testDBName, testGraphName, "knows", "ab",
+		map[string]interface{}{"_from": "persons/bob", "_to": "persons/alice"},
+		&ara.ReplaceEdgeConfig{ReturnOld: ara.TruePtr(), ReturnNew: ara.TruePtr()}, &oldEdge, &newEdge)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if rep.OldRev != r.Rev || oldEdge.Since != 2010 || newEdge.From != "persons/bob" || newEdge.Since != 0 {
+		t.Errorf("result = %+v, old = %+v, new = %+v", rep, oldEdge, newEdge)
+	}
+
+	mod, rc, err := c.ModifyEdge(testDBName, testGraphName, "knows", "ab", map[string]interface{}{"since": 2020},
+		&ara.ModifyEdgeConfig{ReturnNew: ara.TruePtr()}, nil, &newEdge)
+	if err != nil || rc != http.StatusAccepted || mod.OldRev != rep.Rev || newEdge.Since != 2020 || newEdge.To != "persons/alice" {
+		t.Fatalf("rc = %d, err = %v, result = %+v, new = %+v", rc, err, mod, newEdge)
+	}
+
+	tests := []struct {
+		name         string
+		from, to     string
+		wantRC       int
+		wantErrorNum int
+	}{
+		{"missing vertex", "persons/alice", "persons/carol", http.StatusNotFound, 1202},
+		{"collection not in definition", "persons/alice", "users/carol", http.StatusBadRequest, 1906},
+		{"invalid handle", "alice", "persons/bob", http.StatusBadRequest, 1233},
+	}
+	for _, tt := range tests {
+		_, rc, err := c.CreateEdge(testDBName, testGraphName, "knows", map[string]interface{}{"_from": tt.from, "_to": tt.to}, nil, nil)
+		if err == nil {
+			t.Errorf("%s: got no error", tt.name)
+			continue
+		}
+		checkError(t, err, rc, tt.wantRC, tt.wantErrorNum)
+	}
+	_, rc, err = c.CreateEdge(testDBName, testGraphName, "likes", map[string]interface{}{"_from": "persons/alice", "_to": "persons/bob"}, nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1930)
+
+	var removedEdge relation
+	removed, rc, err := c.RemoveEdge(testDBName, testGraphName, "knows", "ab", &ara.RemoveEdgeConfig{ReturnOld: ara.TruePtr()}, &removedEdge)
+	if err != nil || rc != http.StatusAccepted || !removed || removedEdge.Since != 2020 {
+		t.Fatalf("rc = %d, err = %v, removed = %v, old = %+v", rc, err, removed, removedEdge)
+	}
+	_, rc, err = c.GetEdge(testDBName, testGraphName, "knows", "ab", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+}
+
+func TestRemoveVertexRemovesEdges(t *testing.T) {
+	c, _ := setupGraph(t)
+	createPerson(t, c, "alice", "Alice")
+	createPerson(t, c, "bob", "Bob")
+	createPerson(t, c, "carol", "Carol")
+	for _, e := range []struct{ key, from, to string }{
+		{"ab", "alice", "bob"},
+		{"ca", "carol", "alice"},
+		{"bc", "bob", "carol"},
+	} {
+		_, _, err := c.CreateEdge(testDBName, testGraphName, "knows",
+			map[string]interface{}{"_key": e.key, "_from": "persons/" + e.from, "_to": "persons/" + e.to}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var oldVertex person
+	removed, rc, err := c.RemoveVertex(testDBName, testGraphName, "persons", "alice", &ara.RemoveVertexConfig{ReturnOld: ara.TruePtr()}, &oldVertex)
+	if err != nil || rc != http.StatusAccepted || !removed || oldVertex.Name != "Alice" {
+		t.Fatalf("rc = %d, err = %v, removed = %v, old = %+v", rc, err, removed, oldVertex)
+	}
+
+	cur, _, err := c.ListAllDocuments(testDBName, ara.ListAllDocumentsConfig{Collection: "knows", Type: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	if err := cur.All(&keys); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, ",") != "bc" {
+		t.Errorf("edges = %v, want only bc", keys)
+	}
+}
+
+func TestEdgeDefinitions(t *testing.T) {
+	c, _ := setupGraph(t)
+
+	g, _, err := c.AddEdgeDefinition(testDBName, testGraphName, ara.EdgeDefinition{
+		Collection: "likes", From: []string{"persons"}, To: []string{"things"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.EdgeDefinitions) != 2 {
+		t.Errorf("graph = %+v", g)
+	}
+	_, rc, err := c.AddEdgeDefinition(testDBName, testGraphName, ara.EdgeDefinition{Collection: "likes", From: []string{"persons"}, To: []string{"persons"}})
+	checkError(t, err, rc, http.StatusBadRequest, 1920)
+
+	collections, _, err := c.ListVertexCollections(testDBName, testGraphName)
+	if err != nil || strings.Join(collections, ",") != "persons,things" {
+		t.Errorf("vertex collections = %v, err = %v", collections, err)
+	}
+
+	replaced, _, err := c.ReplaceEdgeDefinition(testDBName, testGraphName, "likes",
+		ara.EdgeDefinition{Collection: "likes", From: []string{"persons"}, To: []string{"persons"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(replaced.OrphanCollections, ",") != "things" {
+		t.Errorf("orphans = %v, want things", replaced.OrphanCollections)
+	}
+
+	removed, _, err := c.RemoveEdgeDefinition(testDBName, testGraphName, "likes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed.EdgeDefinitions) != 1 || removed.EdgeDefinitions[0].Collection != "knows" {
+		t.Errorf("graph = %+v", removed)
+	}
+	_, rc, err = c.RemoveEdgeDefinition(testDBName, testGraphName, "likes")
+	checkError(t, err, rc, http.StatusNotFound, 1930)
+
+	edges, _, err := c.ListEdgeDefinitions(testDBName, testGraphName)
+	if err != nil || strings.Join(edges, ",") != "knows" {
+		t.Errorf("edge collections = %v, err = %v", edges, err)
+	}
+}
+
+func TestVertexCollections(t *testing.T) {
+	c, _ := setupGraph(t)
+
+	g, rc, err := c.AddVertexCollection(testDBName, testGraphName, "places", &ara.AddVertexCollectionConfig{WaitForSync: ara.TruePtr()})
+	if err != nil || rc != http.StatusCreated || strings.Join(g.OrphanCollections, ",") != "places" {
+		t.Fatalf("rc = %d, err = %v, graph = %+v", rc, err, g)
+	}
+	_, rc, err = c.AddVertexCollection(testDBName, testGraphName, "persons", nil)
+	checkError(t, err, rc, http.StatusBadRequest, 1929)
+	_, rc, err = c.RemoveVertexCollection(testDBName, testGraphName, "persons", nil)
+	checkError(t, err, rc, http.StatusBadRequest, 1928)
+
+	removed, _, err := c.RemoveVertexCollection(testDBName, testGraphName, "places", nil)
+	if err != nil || len(removed.OrphanCollections) != 0 {
+		t.Errorf("err = %v, graph = %+v", err, removed)
+	}
+}
+
+func TestEnsureGraph(t *testing.T) {
+	c, _ := setupGraph(t)
+
+	definition := ara.CreateGraphConfig{
+		Name: testGraphName,
+		EdgeDefinitions: []ara.EdgeDefinition{
+			{Collection: "knows", From: []string{"persons"}, To: []string{"persons", "bots"}},
+			{Collection: "likes", From: []string{"persons"}, To: []string{"things"}},
+		},
+		OrphanCollections: []string{"places"},
+	}
+	plan, _, err := c.EnsureGraph(testDBName, definition, &ara.EnsureGraphConfig{DryRun: true})
+	if err != nil || len(plan.Changes) != 3 {
+		t.Fatalf("err = %v, changes = %+v", err, plan.Changes)
+	}
+
+	r, _, err := c.EnsureGraph(testDBName, definition, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Graph.EdgeDefinitions) != 2 || strings.Join(r.Graph.OrphanCollections, ",") != "places" {
+		t.Errorf("graph = %+v", r.Graph)
+	}
+
+	r, _, err = c.EnsureGraph(testDBName, definition, nil)
+	if err != nil || len(r.Changes) != 0 {
+		t.Errorf("err = %v, changes = %+v, want none", err, r.Changes)
+	}
+
+	r, rc, err := c.EnsureGraph(testDBName, ara.CreateGraphConfig{Name: "other"}, &ara.EnsureGraphConfig{DryRun: true})
+	if err != nil || rc != 0 || len(r.Changes) != 1 || r.Changes[0] != (ara.GraphChange{Action: ara.GraphChangeCreateGraph}) {
+		t.Errorf("rc = %d, err = %v, changes = %+v", rc, err, r.Changes)
+	}
+}
@@ -0,0 +1,215 @@
+package arangotest_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+const availabilityPath = "GET /_admin/server/availability"
+
+// availabilityRecorder is a middleware which keeps the times of the
+// availability checks. onCheck is called with the number of the check before
+// it is sent.
+type availabilityRecorder struct {
+	mu      sync.Mutex
+	times   []time.Time
+	onCheck func(n int)
+}
+
+func (r *availabilityRecorder) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		if req.Method+" "+req.Path == availabilityPath {
+			r.mu.Lock()
+			r.times = append(r.times, time.Now())
+			n := len(r.times)
+			r.mu.Unlock()
+			if r.onCheck != nil {
+				r.onCheck(n)
+			}
+		}
+		return next(req)
+	}
+}
+
+func (r *availabilityRecorder) checks() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.times...)
+}
+
+func newHealthConnection(t *testing.T, s *arangotest.Server, rec *availabilityRecorder) *ara.Connection {
+	t.Helper()
+	config := s.ConnectionConfig()
+	if rec != nil {
+		config.Middlewares = []ara.Middleware{rec.middleware}
+	}
+	return newConnection(t, config)
+}
+
+func TestPing(t *testing.T) {
+	s := arangotest.NewServer(&arangotest.Config{Username: "root", Password: "secret"})
+	defer s.Close()
+	c := newHealthConnection(t, s, nil)
+	if rc, err := c.Ping(context.Background()); err != nil || rc != http.StatusOK {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+
+	// Ping does not check the availability.
+	s.SetAvailable(false)
+	if _, err := c.Ping(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	rc, err := newConnection(t, &ara.Config{URL: s.URL, Username: "root", Password: "wrong"}).Ping(context.Background())
+	checkError(t, err, rc, http.StatusUnauthorized, 11)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Ping(ctx); err == nil {
+		t.Error("got no error with a canceled context")
+	}
+
+	s.Close()
+	if _, err := c.Ping(context.Background()); err == nil {
+		t.Error("got no error from a closed server")
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	s := arangotest.NewServer(nil)
+	defer s.Close()
+	c := newHealthConnection(t, s, nil)
+	if rc, err := c.CheckAvailability(context.Background()); err != nil || rc != http.StatusOK {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+
+	s.SetAvailable(false)
+	rc, err := c.CheckAvailability(context.Background())
+	checkError(t, err, rc, http.StatusServiceUnavailable, 30)
+
+	s.SetAvailable(true)
+	if rc, err := c.CheckAvailability(context.Background()); err != nil || rc != http.StatusOK {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+}
+
+func TestWaitForReady(t *testing.T) {
+	s := arangotest.NewServer(nil)
+	defer s.Close()
+	s.SetAvailable(false)
+	const delay = 50 * time.Millisecond
+	time.AfterFunc(delay, func() {
+		s.SetAvailable(true)
+	})
+
+	rec := new(availabilityRecorder)
+	c := newHealthConnection(t, s, rec)
+	start := time.Now()
+	err := c.WaitForReady(context.Background(), &ara.WaitForReadyConfig{InitialInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("returned after %v before the server became available", elapsed)
+	}
+	if n := len(rec.checks()); n < 2 {
+		t.Errorf("checks = %d, want checks until the server became available", n)
+	}
+}
+
+func TestWaitForReadyEndpoints(t *testing.T) {
+	ready := arangotest.NewServer(nil)
+	defer ready.Close()
+	starting := arangotest.NewServer(nil)
+	defer starting.Close()
+	starting.SetAvailable(false)
+
+	// The third check is the second one of the starting server since the
+	// ready server is not checked again.
+	rec := &availabilityRecorder{onCheck: func(n int) {
+		if n == 3 {
+			starting.SetAvailable(true)
+		}
+	}}
+	c := newHealthConnection(t, ready, rec)
+	err := c.WaitForReady(context.Background(), &ara.WaitForReadyConfig{
+		Endpoints:       []string{ready.URL, starting.URL},
+		InitialInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(rec.checks()); n != 3 {
+		t.Errorf("checks = %d, want 3", n)
+	}
+
+	if err := c.WaitForReady(context.Background(), &ara.WaitForReadyConfig{Endpoints: []string{"http://[::1"}}); err == nil {
+		t.Error("got no error for an invalid endpoint")
+	}
+}
+
+func TestWaitForReadyBackoff(t *testing.T) {
+	s := arangotest.NewServer(nil)
+	defer s.Close()
+	s.SetAvailable(false)
+	rec := &availabilityRecorder{onCheck: func(n int) {
+		if n == 5 {
+			s.SetAvailable(true)
+		}
+	}}
+	c := newHealthConnection(t, s, rec)
+	err := c.WaitForReady(context.Background(), &ara.WaitForReadyConfig{
+		InitialInterval: 20 * time.Millisecond,
+		MaxInterval:     40 * time.Millisecond,
+		Multiplier:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := rec.checks()
+	if len(checks) != 5 {
+		t.Fatalf("checks = %d, want 5", len(checks))
+	}
+	// The waits grow from 20ms and are capped at 40ms, so the last one is
+	// far shorter than the 160ms it would be without the cap.
+	want := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	for i, w := range want {
+		if gap := checks[i+1].Sub(checks[i]); gap < w {
+			t.Errorf("wait %d = %v, want at least %v", i, gap, w)
+		}
+	}
+	if gap := checks[4].Sub(checks[3]); gap >= 160*time.Millisecond {
+		t.Errorf("last wait = %v, want it capped", gap)
+	}
+}
+
+func TestWaitForReadyCanceled(t *testing.T) {
+	s := arangotest.NewServer(nil)
+	defer s.Close()
+	s.SetAvailable(false)
+	closed := arangotest.NewServer(nil)
+	closed.Close()
+
+	c := newHealthConnection(t, s, nil)
+	for _, endpoints := range [][]string{{s.URL}, {closed.URL}} {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		err := c.WaitForReady(ctx, &ara.WaitForReadyConfig{Endpoints: endpoints, InitialInterval: 5 * time.Millisecond})
+		if err != ctx.Err() || err != context.DeadlineExceeded {
+			t.Errorf("%v: err = %v, want %v", endpoints, err, context.DeadlineExceeded)
+		}
+		cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := c.WaitForReady(ctx, nil); err != context.Canceled {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
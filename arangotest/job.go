@@ -0,0 +1,218 @@
+package arangotest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// job is a request sent with the x-arango-async header.
+type job struct {
+	id      string
+	req     *http.Request
+	body    []byte
+	created time.Time
+	// result is the response of the request. It is nil while the job is
+	// pending.
+	result *response
+}
+
+// SetJobsPaused sets whether asynchronous requests are kept pending instead
+// of executed, to test jobs which have not finished yet. The pending jobs are
+// executed in order when the jobs are resumed.
+func (s *Server) SetJobsPaused(paused bool) {
+	s.mu.Lock()
+	s.jobsPaused = paused
+	var queued []*job
+	if !paused {
+		queued = s.queuedJobs
+		s.queuedJobs = nil
+	}
+	s.mu.Unlock()
+
+	for _, j := range queued {
+		s.runJob(j)
+	}
+}
+
+// serveAsync accepts a request with the x-arango-async header. Unlike
+// ArangoDB, the request is executed before it is accepted unless the jobs
+// are paused, so that its effects are visible to the next request. The
+// response is kept as the result of the job when the mode is "store".
+func (s *Server) serveAsync(w http.ResponseWriter, hr *http.Request) {
+	if err := s.authenticate(hr); err != nil {
+		writeResponse(w, hr, nil, err)
+		return
+	}
+	body, err := readBody(hr)
+	if err != nil {
+		writeResponse(w, hr, nil, err)
+		return
+	}
+	j := &job{
+		req:     hr.Clone(context.Background()),
+		body:    body,
+		created: time.Now(),
+	}
+
+	s.mu.Lock()
+	if hr.Header.Get("x-arango-async") == "store" {
+		j.id = s.nextID()
+		s.jobs = append(s.jobs, j)
+	}
+	paused := s.jobsPaused
+	if paused {
+		s.queuedJobs = append(s.queuedJobs, j)
+	}
+	s.mu.Unlock()
+
+	if !paused {
+		s.runJob(j)
+	}
+	if j.id != "" {
+		w.Header().Set("x-arango-async-id", j.id)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) runJob(j *job) {
+	j.req.Body = io.NopCloser(bytes.NewReader(j.body))
+	resp, err := s.handle(j.req)
+	if err != nil {
+		resp = toAPIError(err).response()
+	}
+
+	s.mu.Lock()
+	j.result = resp
+	s.mu.Unlock()
+}
+
+func (s *Server) findJob(id string) (int, *job) {
+	for i, j := range s.jobs {
+		if j.id == id {
+			return i, j
+		}
+	}
+	return -1, nil
+}
+
+// removeJob removes the job from the list. A pending job is still executed
+// unless it is also removed from the queue.
+func (s *Server) removeJob(i int) *job {
+	j := s.jobs[i]
+	s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+	return j
+}
+
+func (s *Server) dequeueJob(j *job) {
+	for i, q := range s.queuedJobs {
+		if q == j {
+			s.queuedJobs = append(s.queuedJobs[:i], s.queuedJobs[i+1:]...)
+			return
+		}
+	}
+}
+
+func errJobNotFound() error {
+	return newError(http.StatusNotFound, errorNumHTTPNotFound, "job not found")
+}
+
+func (s *Server) jobAPI(r *request) (*response, error) {
+	switch {
+	case len(r.segs) == 2 && r.Method == http.MethodGet:
+		if r.segs[1] == "done" || r.segs[1] == "pending" {
+			return s.listJobs(r, r.segs[1] == "done")
+		}
+		_, j := s.findJob(r.segs[1])
+		if j == nil {
+			return nil, errJobNotFound()
+		}
+		if j.result == nil {
+			return newResponse(http.StatusNoContent, nil), nil
+		}
+		return newResponse(http.StatusOK, nil), nil
+	case len(r.segs) == 2 && r.Method == http.MethodPut:
+		return s.jobResult(r.segs[1])
+	case len(r.segs) == 3 && r.segs[2] == "cancel" && r.Method == http.MethodPut:
+		i, j := s.findJob(r.segs[1])
+		if j == nil {
+			return nil, errJobNotFound()
+		}
+		if j.result == nil {
+			s.dequeueJob(s.removeJob(i))
+		}
+		return newResponse(http.StatusOK, map[string]interface{}{"result": true}), nil
+	case len(r.segs) == 2 && r.Method == http.MethodDelete:
+		return s.deleteJobs(r)
+	}
+	if len(r.segs) == 2 || len(r.segs) == 3 {
+		return nil, errMethodNotAllowed()
+	}
+	return nil, errUnknownPath()
+}
+
+func (s *Server) listJobs(r *request, done bool) (*response, error) {
+	count := -1
+	if v := r.query.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid count")
+		}
+		count = n
+	}
+	ids := []string{}
+	for _, j := range s.jobs {
+		if (j.result != nil) == done && len(ids) != count {
+			ids = append(ids, j.id)
+		}
+	}
+	return newResponse(http.StatusOK, ids), nil
+}
+
+// jobResult returns the response of a finished job and removes the job like
+// ArangoDB does.
+func (s *Server) jobResult(id string) (*response, error) {
+	i, j := s.findJob(id)
+	if j == nil {
+		return nil, errJobNotFound()
+	}
+	if j.result == nil {
+		return newResponse(http.StatusNoContent, nil), nil
+	}
+	s.removeJob(i)
+	resp := *j.result
+	resp.header = resp.header.Clone()
+	if resp.header == nil {
+		resp.header = make(http.Header)
+	}
+	resp.header.Set("x-arango-async-id", j.id)
+	return &resp, nil
+}
+
+func (s *Server) deleteJobs(r *request) (*response, error) {
+	switch target := r.segs[1]; target {
+	case "all":
+		s.jobs = nil
+	case "expired":
+		v := r.query.Get("stamp")
+		stamp, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid stamp")
+		}
+		for i := len(s.jobs) - 1; i >= 0; i-- {
+			if s.jobs[i].created.Unix() < stamp {
+				s.removeJob(i)
+			}
+		}
+	default:
+		i, j := s.findJob(target)
+		if j == nil {
+			return nil, errJobNotFound()
+		}
+		s.removeJob(i)
+	}
+	return newResponse(http.StatusOK, map[string]interface{}{"result": true}), nil
+}
@@ -0,0 +1,321 @@
+package arangotest_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+// asyncRecorder is a middleware which keeps the requests with their
+// x-arango-async header.
+type asyncRecorder struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (r *asyncRecorder) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		r.mu.Lock()
+		r.requests = append(r.requests, fmt.Sprintf("%s %s async=%s", req.Method, req.Path, req.Header.Get("x-arango-async")))
+		r.mu.Unlock()
+		return next(req)
+	}
+}
+
+func (r *asyncRecorder) all() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.requests...)
+}
+
+// setupJobs returns the server, a connection without middlewares and a
+// connection recording its requests.
+func setupJobs(t *testing.T) (*arangotest.Server, *ara.Connection, *ara.Connection, *asyncRecorder) {
+	t.Helper()
+	s, c, _ := setup(t)
+	rec := new(asyncRecorder)
+	config := s.ConnectionConfig()
+	config.Middlewares = []ara.Middleware{rec.middleware}
+	return s, c, newConnection(t, config), rec
+}
+
+func createUserAsync(t *testing.T, c *ara.Connection, mode string, job *ara.AsyncJob, key string) {
+	t.Helper()
+	_, rc, err := c.Async(mode, job).CreateDocument(testDBName, "users", map[string]interface{}{"_key": key, "name": key}, nil, nil, nil)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+}
+
+func TestAsyncStore(t *testing.T) {
+	_, c, jc, rec := setupJobs(t)
+
+	var job ara.AsyncJob
+	createUserAsync(t, jc, ara.AsyncModeStore, &job, "alice")
+	if job.ID() == "" {
+		t.Fatal("job id was not set")
+	}
+	if got, want := rec.all(), []string{"POST /_db/test/_api/document/users async=store"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+	readUser(t, c, "users/alice")
+
+	status, rc, err := jc.GetJobStatus(testDBName, job.ID())
+	if err != nil || rc != http.StatusOK || status != ara.JobStatusDone {
+		t.Errorf("status = %s, rc = %d, err = %v", status, rc, err)
+	}
+
+	var doc ara.Document
+	done, rc, err := jc.GetJobResult(testDBName, job.ID(), &doc)
+	if err != nil || !done || rc != http.StatusAccepted || doc.ID != "users/alice" {
+		t.Errorf("done = %v, rc = %d, err = %v, doc = %+v", done, rc, err, doc)
+	}
+	for _, r := range rec.all()[1:] {
+		if strings.HasSuffix(r, "async=store") {
+			t.Errorf("%s was sent asynchronously", r)
+		}
+	}
+
+	// The result is removed when it is fetched.
+	_, rc, err = jc.GetJobResult(testDBName, job.ID(), nil)
+	checkError(t, err, rc, http.StatusNotFound, 404)
+}
+
+func TestAsyncStoreError(t *testing.T) {
+	_, c, _, _ := setupJobs(t)
+	createUser(t, c, "alice", "Alice")
+
+	var job ara.AsyncJob
+	createUserAsync(t, c, ara.AsyncModeStore, &job, "alice")
+	done, rc, err := c.GetJobResult(testDBName, job.ID(), nil)
+	if !done {
+		t.Error("done = false, want true")
+	}
+	checkError(t, err, rc, http.StatusConflict, 1210)
+}
+
+func TestAsyncFireAndForget(t *testing.T) {
+	_, c, jc, rec := setupJobs(t)
+
+	var job ara.AsyncJob
+	createUserAsync(t, jc, ara.AsyncModeFireAndForget, &job, "alice")
+	if job.ID() != "" {
+		t.Errorf("job id = %s, want none", job.ID())
+	}
+	if got, want := rec.all(), []string{"POST /_db/test/_api/document/users async=true"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+	readUser(t, c, "users/alice")
+	ids, _, err := c.ListJobs(testDBName, ara.JobStatusDone, 0)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("done jobs = %v, err = %v", ids, err)
+	}
+}
+
+func TestPendingJob(t *testing.T) {
+	s, c, _, _ := setupJobs(t)
+	s.SetJobsPaused(true)
+
+	var job ara.AsyncJob
+	createUserAsync(t, c, ara.AsyncModeStore, &job, "alice")
+	status, rc, err := c.GetJobStatus(testDBName, job.ID())
+	if err != nil || rc != http.StatusNoContent || status != ara.JobStatusPending {
+		t.Errorf("status = %s, rc = %d, err = %v", status, rc, err)
+	}
+	var doc ara.Document
+	done, rc, err := c.GetJobResult(testDBName, job.ID(), &doc)
+	if err != nil || done || rc != http.StatusNoContent || doc.ID != "" {
+		t.Errorf("done = %v, rc = %d, err = %v, doc = %+v", done, rc, err, doc)
+	}
+	if ids, _, err := c.ListJobs(testDBName, ara.JobStatusPending, 0); err != nil || fmt.Sprint(ids) != "["+job.ID()+"]" {
+		t.Errorf("pending jobs = %v, err = %v", ids, err)
+	}
+	rc, err = c.ReadDocument(testDBName, "users/alice", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+
+	s.SetJobsPaused(false)
+	if ids, _, err := c.ListJobs(testDBName, ara.JobStatusDone, 0); err != nil || fmt.Sprint(ids) != "["+job.ID()+"]" {
+		t.Errorf("done jobs = %v, err = %v", ids, err)
+	}
+	done, _, err = c.GetJobResult(testDBName, job.ID(), &doc)
+	if err != nil || !done || doc.ID != "users/alice" {
+		t.Errorf("done = %v, err = %v, doc = %+v", done, err, doc)
+	}
+}
+
+func TestListJobsCount(t *testing.T) {
+	_, c, _, _ := setupJobs(t)
+	var jobs [3]ara.AsyncJob
+	for i := range jobs {
+		createUserAsync(t, c, ara.AsyncModeStore, &jobs[i], fmt.Sprintf("user%d", i))
+	}
+	ids, rc, err := c.ListJobs(testDBName, ara.JobStatusDone, 2)
+	if err != nil || rc != http.StatusOK || fmt.Sprint(ids) != fmt.Sprint([]string{jobs[0].ID(), jobs[1].ID()}) {
+		t.Errorf("ids = %v, rc = %d, err = %v", ids, rc, err)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	s, c, _, _ := setupJobs(t)
+	s.SetJobsPaused(true)
+
+	var job ara.AsyncJob
+	createUserAsync(t, c, ara.AsyncModeStore, &job, "alice")
+	rc, err := c.CancelJob(testDBName, job.ID())
+	if err != nil || rc != http.StatusOK {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	s.SetJobsPaused(false)
+
+	rc, err = c.ReadDocument(testDBName, "users/alice", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+	_, rc, err = c.GetJobStatus(testDBName, job.ID())
+	checkError(t, err, rc, http.StatusNotFound, 404)
+	rc, err = c.CancelJob(testDBName, "missing")
+	checkError(t, err, rc, http.StatusNotFound, 404)
+}
+
+func TestDeleteJobs(t *testing.T) {
+	_, c, _, _ := setupJobs(t)
+	var jobs [3]ara.AsyncJob
+	for i := range jobs {
+		createUserAsync(t, c, ara.AsyncModeStore, &jobs[i], fmt.Sprintf("user%d", i))
+	}
+	listDone := func() string {
+		t.Helper()
+		ids, _, err := c.ListJobs(testDBName, ara.JobStatusDone, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Sprint(ids)
+	}
+
+	rc, err := c.DeleteJobs(testDBName, jobs[0].ID(), nil)
+	if err != nil || rc != http.StatusOK {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+	if got, want := listDone(), fmt.Sprint([]string{jobs[1].ID(), jobs[2].ID()}); got != want {
+		t.Errorf("jobs = %s, want %s", got, want)
+	}
+	rc, err = c.DeleteJobs(testDBName, jobs[0].ID(), nil)
+	checkError(t, err, rc, http.StatusNotFound, 404)
+
+	past := time.Now().Add(-time.Hour)
+	if _, err := c.DeleteJobs(testDBName, ara.DeleteJobsExpired, &ara.DeleteJobsConfig{Stamp: &past}); err != nil {
+		t.Fatal(err)
+	}
+	if got := listDone(); got != fmt.Sprint([]string{jobs[1].ID(), jobs[2].ID()}) {
+		t.Errorf("jobs after deleting expired ones before %v = %s", past, got)
+	}
+	future := time.Now().Add(time.Hour)
+	if _, err := c.DeleteJobs(testDBName, ara.DeleteJobsExpired, &ara.DeleteJobsConfig{Stamp: &future}); err != nil {
+		t.Fatal(err)
+	}
+	if got := listDone(); got != "[]" {
+		t.Errorf("jobs after deleting expired ones = %s", got)
+	}
+
+	createUserAsync(t, c, ara.AsyncModeStore, &jobs[0], "user3")
+	if _, err := c.DeleteJobs(testDBName, ara.DeleteJobsAll, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := listDone(); got != "[]" {
+		t.Errorf("jobs after deleting all = %s", got)
+	}
+}
+
+// TestAsyncSharedJob sends requests concurrently with connections sharing a
+// job. It is for the race detector.
+func TestAsyncSharedJob(t *testing.T) {
+	_, c, _, _ := setupJobs(t)
+	var job ara.AsyncJob
+	ac := c.Async(ara.AsyncModeStore, &job)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, _, err := ac.CreateDocument(testDBName, "users", map[string]interface{}{"_key": fmt.Sprintf("user%d", i)}, nil, nil, nil); err != nil {
+				t.Error(err)
+			}
+			if job.ID() == "" {
+				t.Error("job id was not set")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestUnsupportedAsync(t *testing.T) {
+	_, _, jc, rec := setupJobs(t)
+	ac := jc.Async(ara.AsyncModeStore, nil)
+
+	tests := []struct {
+		method string
+		call   func() (rc int, err error)
+	}{
+		{"EnsureGraph", func() (int, error) {
+			_, rc, err := ac.EnsureGraph(testDBName, ara.CreateGraphConfig{Name: testGraphName}, nil)
+			return rc, err
+		}},
+		{"Traverse", func() (int, error) {
+			return ac.Traverse(testDBName, testGraphName, "persons/alice", ara.TraversalConfig{}, nil, nil)
+		}},
+		{"ShortestPath", func() (int, error) {
+			_, rc, err := ac.ShortestPath(testDBName, testGraphName, "persons/alice", "persons/bob", nil, nil)
+			return rc, err
+		}},
+		{"KShortestPaths", func() (int, error) {
+			_, rc, err := ac.KShortestPaths(testDBName, testGraphName, "persons/alice", "persons/bob", 2, nil, nil)
+			return rc, err
+		}},
+		{"UpdateWithRetry", func() (int, error) {
+			_, rc, err := ac.UpdateWithRetry(testDBName, "users/alice", new(user), func(interface{}) error { return nil }, nil)
+			return rc, err
+		}},
+		{"UpdateVertexWithRetry", func() (int, error) {
+			_, rc, err := ac.UpdateVertexWithRetry(testDBName, testGraphName, "persons", "alice", new(user), func(interface{}) error { return nil }, nil)
+			return rc, err
+		}},
+		{"ListAllDocuments", func() (int, error) {
+			_, rc, err := ac.ListAllDocuments(testDBName, ara.ListAllDocumentsConfig{Collection: "users"})
+			return rc, err
+		}},
+		{"ByExample", func() (int, error) {
+			_, rc, err := ac.ByExample(testDBName, ara.ByExampleConfig{Collection: "users"})
+			return rc, err
+		}},
+		{"Range", func() (int, error) {
+			_, rc, err := ac.Range(testDBName, ara.RangeConfig{Collection: "users"})
+			return rc, err
+		}},
+		{"Near", func() (int, error) {
+			_, rc, err := ac.Near(testDBName, ara.NearConfig{Collection: "users"})
+			return rc, err
+		}},
+		{"Within", func() (int, error) {
+			_, rc, err := ac.Within(testDBName, ara.WithinConfig{Collection: "users"})
+			return rc, err
+		}},
+		{"Fulltext", func() (int, error) {
+			_, rc, err := ac.Fulltext(testDBName, ara.FulltextConfig{Collection: "users"})
+			return rc, err
+		}},
+	}
+	for _, tt := range tests {
+		rc, err := tt.call()
+		e, ok := err.(*ara.ErrUnsupportedAsync)
+		if !ok || e.Feature != tt.method || rc != 0 {
+			t.Errorf("%s: rc = %d, err = %#v", tt.method, rc, err)
+		}
+	}
+	if got := rec.all(); len(got) != 0 {
+		t.Errorf("requests = %v, want none", got)
+	}
+}
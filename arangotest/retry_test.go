@@ -0,0 +1,185 @@
+package arangotest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+// conflictingWriter is a middleware which modifies the document with write
+// before the first conflicts replaces of handle, so that they fail with 412.
+// It also keeps the attempts of the requests.
+type conflictingWriter struct {
+	handle    string
+	conflicts int
+	write     func(n int)
+
+	mu       sync.Mutex
+	writes   int
+	attempts []string
+}
+
+func (w *conflictingWriter) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		if strings.HasSuffix(req.Path, "/"+w.handle) {
+			w.mu.Lock()
+			w.attempts = append(w.attempts, fmt.Sprintf("%s %d", req.Method, req.Attempt))
+			write := req.Method == http.MethodPut && w.writes < w.conflicts
+			if write {
+				w.writes++
+			}
+			n := w.writes
+			w.mu.Unlock()
+			if write {
+				w.write(n)
+			}
+		}
+		return next(req)
+	}
+}
+
+func (w *conflictingWriter) requests() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Join(w.attempts, ",")
+}
+
+// retryTarget runs UpdateWithRetry or UpdateVertexWithRetry on alice whose
+// age is set by the conflicting writes.
+type retryTarget struct {
+	name   string
+	setup  func(t *testing.T) (s *arangotest.Server, c *ara.Connection, handle string)
+	update func(c *ara.Connection, doc *user, mutate func(interface{}) error, config *ara.UpdateWithRetryConfig) (rc int, err error)
+}
+
+var retryTargets = []retryTarget{
+	{
+		name: "UpdateWithRetry",
+		setup: func(t *testing.T) (*arangotest.Server, *ara.Connection, string) {
+			s, c, _ := setup(t)
+			createUser(t, c, "alice", "Alice")
+			return s, c, "users/alice"
+		},
+		update: func(c *ara.Connection, doc *user, mutate func(interface{}) error, config *ara.UpdateWithRetryConfig) (int, error) {
+			_, rc, err := c.UpdateWithRetry(testDBName, "users/alice", doc, mutate, config)
+			return rc, err
+		},
+	},
+	{
+		name: "UpdateVertexWithRetry",
+		setup: func(t *testing.T) (*arangotest.Server, *ara.Connection, string) {
+			s, c, _ := setup(t)
+			_, _, err := c.CreateGraph(testDBName, ara.CreateGraphConfig{
+				Name:              testGraphName,
+				OrphanCollections: []string{"persons"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			createPerson(t, c, "alice", "Alice")
+			return s, c, "persons/alice"
+		},
+		update: func(c *ara.Connection, doc *user, mutate func(interface{}) error, config *ara.UpdateWithRetryConfig) (int, error) {
+			_, rc, err := c.UpdateVertexWithRetry(testDBName, testGraphName, "persons", "alice", doc, mutate, config)
+			return rc, err
+		},
+	},
+}
+
+// setupRetry returns a connection whose replaces of alice conflict with
+// writes setting the age to 10, 20, ... for the first conflicts times, and
+// a connection without conflicts.
+func setupRetry(t *testing.T, target retryTarget, conflicts int) (uc, c *ara.Connection, w *conflictingWriter) {
+	t.Helper()
+	s, c, handle := target.setup(t)
+	w = &conflictingWriter{handle: handle, conflicts: conflicts, write: func(n int) {
+		_, _, err := c.UpdateDocument(testDBName, handle, map[string]interface{}{"age": n * 10}, nil, nil, nil)
+		if err != nil {
+			t.Error(err)
+		}
+	}}
+	config := s.ConnectionConfig()
+	config.Middlewares = []ara.Middleware{w.middleware}
+	return newConnection(t, config), c, w
+}
+
+func TestUpdateWithRetryConflict(t *testing.T) {
+	for _, target := range retryTargets {
+		t.Run(target.name, func(t *testing.T) {
+			uc, c, w := setupRetry(t, target, 1)
+			var ages []int
+			mutate := func(docPtr interface{}) error {
+				doc := docPtr.(*user)
+				ages = append(ages, doc.Age)
+				doc.Age++
+				return nil
+			}
+
+			var doc user
+			status, err := target.update(uc, &doc, mutate, nil)
+			if err != nil || status != http.StatusAccepted && status != http.StatusCreated {
+				t.Fatalf("rc = %d, err = %v", status, err)
+			}
+			// mutate is applied again to the document read after the conflict.
+			if fmt.Sprint(ages) != "[0 10]" {
+				t.Errorf("mutate saw ages %v, want [0 10]", ages)
+			}
+			if got := w.requests(); got != "GET 0,PUT 0,GET 1,PUT 1" {
+				t.Errorf("requests = %s", got)
+			}
+			var u user
+			if _, err := c.ReadDocument(testDBName, w.handle, nil, &u); err != nil || u.Age != 11 || u.Name != "Alice" {
+				t.Errorf("document = %+v, err = %v", u, err)
+			}
+		})
+	}
+}
+
+func TestUpdateWithRetryMaxAttempts(t *testing.T) {
+	for _, target := range retryTargets {
+		t.Run(target.name, func(t *testing.T) {
+			uc, _, w := setupRetry(t, target, 3)
+			calls := 0
+			mutate := func(interface{}) error {
+				calls++
+				return nil
+			}
+
+			var doc user
+			status, err := target.update(uc, &doc, mutate, &ara.UpdateWithRetryConfig{MaxAttempts: 3})
+			var e *ara.ConflictError
+			if !errors.As(err, &e) || e.Handle != w.handle || e.Attempts != 3 {
+				t.Fatalf("err = %#v", err)
+			}
+			if status != http.StatusPreconditionFailed || calls != 3 {
+				t.Errorf("rc = %d, mutate calls = %d", status, calls)
+			}
+			if got := w.requests(); got != "GET 0,PUT 0,GET 1,PUT 1,GET 2,PUT 2" {
+				t.Errorf("requests = %s", got)
+			}
+		})
+	}
+}
+
+func TestUpdateWithRetryMutateError(t *testing.T) {
+	for _, target := range retryTargets {
+		t.Run(target.name, func(t *testing.T) {
+			uc, _, w := setupRetry(t, target, 0)
+			mutateErr := errors.New("invalid document")
+			var doc user
+			_, err := target.update(uc, &doc, func(interface{}) error { return mutateErr }, nil)
+			if err != mutateErr {
+				t.Errorf("err = %v, want %v", err, mutateErr)
+			}
+			if got := w.requests(); got != "GET 0" {
+				t.Errorf("requests = %s, want only the read", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+// Package arangotest provides an in-memory fake ArangoDB server for unit
+// tests of code using arangogo connections.
+//
+// The server implements the database, collection, document, gharial (graph),
+// batch, job and simple all-keys and any APIs with the keys, revisions,
+// preconditions and error responses of ArangoDB, so no real server is needed.
+// /_admin/statistics and /_admin/metrics report the number of requests.
+// It answers in VelocyPack when the request accepts it.
+//
+// Recorder and Replayer are middlewares which record the responses of a real
+// server to golden files and return them later for tests without a server.
+//
+//	s := arangotest.NewServer(nil)
+//	defer s.Close()
+//	c, err := ara.NewConnection(s.ConnectionConfig())
+package arangotest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/vpack"
+)
+
+const defaultVersion = "3.11.0"
+
+type Config struct {
+	// Username and Password enable the basic authentication when Username
+	// is not empty.
+	Username string
+	Password string
+	// Version is the server version returned from /_api/version.
+	// It defaults to "3.11.0".
+	Version string
+}
+
+// Server is a fake ArangoDB server. The embedded httptest.Server must be
+// closed with Close.
+type Server struct {
+	*httptest.Server
+	config Config
+
+	mu          sync.Mutex
+	databases   map[string]*database
+	cursors     map[string]*cursor
+	seq         int64
+	unavailable bool
+	jobs        []*job
+	queuedJobs  []*job
+	jobsPaused  bool
+	started     time.Time
+	requests    map[string]int64
+}
+
+func NewServer(config *Config) *Server {
+	s := &Server{
+		databases: make(map[string]*database),
+		cursors:   make(map[string]*cursor),
+		started:   time.Now(),
+	}
+	if config != nil {
+		s.config = *config
+	}
+	if s.config.Version == "" {
+		s.config.Version = defaultVersion
+	}
+	s.databases[ara.SystemDatabaseName] = s.newDatabase(ara.SystemDatabaseName)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ConnectionConfig returns a config for connections to the server.
+func (s *Server) ConnectionConfig() *ara.Config {
+	return &ara.Config{
+		URL:      s.URL,
+		Username: s.config.Username,
+		Password: s.config.Password,
+	}
+}
+
+// SetAvailable sets whether /_admin/server/availability reports the server
+// is ready, to test waiting for a server starting up.
+func (s *Server) SetAvailable(available bool) {
+	s.mu.Lock()
+	s.unavailable = !available
+	s.mu.Unlock()
+}
+
+func (s *Server) nextID() string {
+	s.seq++
+	return strconv.FormatInt(s.seq, 10)
+}
+
+func (s *Server) nextRev() string {
+	s.seq++
+	return "_" + strconv.FormatInt(s.seq, 36)
+}
+
+type request struct {
+	*http.Request
+	dbName string
+	db     *database
+	// segs are the unescaped path segments after /_api/ or /_admin/.
+	segs  []string
+	query url.Values
+	body  []byte
+}
+
+func (r *request) boolParam(name string, def bool) bool {
+	v := r.query.Get(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// decodeBody decodes the body keeping numbers as json.Number so that
+// documents are returned as they were stored. VelocyPack bodies are
+// converted to JSON first.
+func (r *request) decodeBody(v interface{}) error {
+	body := r.body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == ara.ContentTypeVelocyPack {
+		var err error
+		body, err = vpack.ToJSON(body)
+		if err != nil {
+			return newError(http.StatusBadRequest, errorNumBadParameter, "invalid VelocyPack: %v", err)
+		}
+	}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(v); err != nil {
+		return newError(http.StatusBadRequest, errorNumCorruptedJSON, "invalid JSON: %v", err)
+	}
+	return nil
+}
+
+type response struct {
+	code   int
+	header http.Header
+	// body is written as JSON unless it is nil or plainText.
+	body interface{}
+}
+
+// plainText is a response body written as is instead of JSON.
+type plainText string
+
+func newResponse(code int, body interface{}) *response {
+	return &response{code: code, body: body}
+}
+
+// writeCode returns 201 when waitForSync is set and 202 otherwise like
+// ArangoDB does for write operations.
+func writeCode(r *request) int {
+	if r.boolParam("waitForSync", false) {
+		return http.StatusCreated
+	}
+	return http.StatusAccepted
+}
+
+type handler func(r *request) (*response, error)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, hr *http.Request) {
+	s.countRequest(hr.Method)
+	if isBatchPath(hr.URL.Path) {
+		s.serveBatch(w, hr)
+		return
+	}
+	if hr.Header.Get("x-arango-async") != "" {
+		s.serveAsync(w, hr)
+		return
+	}
+	resp, err := s.handle(hr)
+	writeResponse(w, hr, resp, err)
+}
+
+func toAPIError(err error) *apiError {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = newError(http.StatusInternalServerError, errorNumInternal, "%v", err)
+	}
+	return apiErr
+}
+
+func writeResponse(w http.ResponseWriter, hr *http.Request, resp *response, err error) {
+	if err != nil {
+		resp = toAPIError(err).response()
+	}
+
+	for k, vv := range resp.header {
+		w.Header()[k] = vv
+	}
+	if resp.body == nil || hr.Method == http.MethodHead {
+		w.WriteHeader(resp.code)
+		return
+	}
+	if text, ok := resp.body.(plainText); ok {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(resp.code)
+		io.WriteString(w, string(text))
+		return
+	}
+	b, err := json.Marshal(resp.body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	contentType := "application/json; charset=utf-8"
+	if acceptsVelocyPack(hr) {
+		b, err = vpack.FromJSON(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		contentType = ara.ContentTypeVelocyPack
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(resp.code)
+	w.Write(b)
+}
+
+// acceptsVelocyPack reports whether the client asked for VelocyPack with the
+// Accept header like ArangoDB does.
+func acceptsVelocyPack(hr *http.Request) bool {
+	for _, v := range hr.Header.Values("Accept") {
+		for _, t := range strings.Split(v, ",") {
+			if mediaType, _, _ := mime.ParseMediaType(strings.TrimSpace(t)); mediaType == ara.ContentTypeVelocyPack {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) authenticate(hr *http.Request) error {
+	if s.config.Username != "" {
+		username, password, ok := hr.BasicAuth()
+		if !ok || username != s.config.Username || password != s.config.Password {
+			return newError(http.StatusUnauthorized, errorNumUnauthorized, "not authorized to execute this request")
+		}
+	}
+	return nil
+}
+
+func (s *Server) handle(hr *http.Request) (*response, error) {
+	if err := s.authenticate(hr); err != nil {
+		return nil, err
+	}
+
+	r := &request{
+		Request: hr,
+		dbName:  ara.SystemDatabaseName,
+		query:   hr.URL.Query(),
+	}
+	var segs []string
+	for _, seg := range strings.Split(strings.Trim(hr.URL.EscapedPath(), "/"), "/") {
+		s, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid path")
+		}
+		segs = append(segs, s)
+	}
+	if len(segs) >= 2 && segs[0] == "_db" {
+		r.dbName = segs[1]
+		segs = segs[2:]
+	}
+	if len(segs) < 2 || (segs[0] != "_api" && segs[0] != "_admin") {
+		return nil, errUnknownPath()
+	}
+	r.segs = segs[1:]
+
+	var err error
+	r.body, err = readBody(hr)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.db = s.databases[r.dbName]
+	if r.db == nil {
+		return nil, newError(http.StatusNotFound, errorNumDatabaseNotFound, "database not found")
+	}
+
+	var h handler
+	switch segs[0] + "/" + r.segs[0] {
+	case "_api/version":
+		h = s.version
+	case "_api/engine":
+		h = s.engine
+	case "_admin/server":
+		h = s.serverAPI
+	case "_admin/statistics":
+		h = s.statistics
+	case "_admin/metrics":
+		h = s.metrics
+	case "_api/database":
+		h = s.databaseAPI
+	case "_api/collection":
+		h = s.collectionAPI
+	case "_api/document":
+		h = s.documentAPI
+	case "_api/gharial":
+		h = s.gharialAPI
+	case "_api/simple":
+		h = s.simpleAPI
+	case "_api/cursor":
+		h = s.cursorAPI
+	case "_api/job":
+		h = s.jobAPI
+	default:
+		return nil, errUnknownPath()
+	}
+	return h(r)
+}
+
+// isBatchPath reports whether the path is /_api/batch of a database.
+func isBatchPath(path string) bool {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segs) >= 2 && segs[0] == "_db" {
+		segs = segs[2:]
+	}
+	return len(segs) == 2 && segs[0] == "_api" && segs[1] == "batch"
+}
+
+// serveBatch serves each part of a batch request like a separate request
+// and returns the responses as the parts of a multipart response.
+func (s *Server) serveBatch(w http.ResponseWriter, hr *http.Request) {
+	if err := s.authenticate(hr); err != nil {
+		writeResponse(w, hr, nil, err)
+		return
+	}
+	if hr.Method != http.MethodPost {
+		writeResponse(w, hr, nil, errMethodNotAllowed())
+		return
+	}
+	mediaType, params, err := mime.ParseMediaType(hr.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		writeResponse(w, hr, nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid multipart message received"))
+		return
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mr := multipart.NewReader(hr.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeResponse(w, hr, nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid multipart message received"))
+			return
+		}
+		preq, err := http.ReadRequest(bufio.NewReader(part))
+		if err != nil {
+			writeResponse(w, hr, nil, newError(http.StatusBadRequest, errorNumBadParameter, "invalid multipart request received"))
+			return
+		}
+		rec := httptest.NewRecorder()
+		s.serveHTTP(rec, preq)
+
+		header := textproto.MIMEHeader{"Content-Type": {"application/x-arango-batchpart"}}
+		if id := part.Header.Get("Content-Id"); id != "" {
+			header.Set("Content-Id", id)
+		}
+		pw, err := mw.CreatePart(header)
+		if err == nil {
+			err = rec.Result().Write(pw)
+		}
+		if err != nil {
+			writeResponse(w, hr, nil, err)
+			return
+		}
+	}
+	if err := mw.Close(); err != nil {
+		writeResponse(w, hr, nil, err)
+		return
+	}
+	w.Header().Set("Content-Type", "multipart/form-data; boundary="+mw.Boundary())
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func readBody(hr *http.Request) ([]byte, error) {
+	if hr.Body == nil {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(hr.Body)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, errorNumBadParameter, "failed to read body: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Server) version(r *request) (*response, error) {
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed()
+	}
+	return newResponse(http.StatusOK, map[string]interface{}{
+		"server":  "arango",
+		"version": s.config.Version,
+		"license": "community",
+	}), nil
+}
+
+func (s *Server) engine(r *request) (*response, error) {
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed()
+	}
+	return newResponse(http.StatusOK, map[string]interface{}{
+		"name":     "rocksdb",
+		"supports": map[string]interface{}{},
+	}), nil
+}
+
+func (s *Server) serverAPI(r *request) (*response, error) {
+	if len(r.segs) != 2 {
+		return nil, errUnknownPath()
+	}
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed()
+	}
+	switch r.segs[1] {
+	case "role":
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"error": false,
+			"code":  http.StatusOK,
+			"role":  "SINGLE",
+			"mode":  "default",
+		}), nil
+	case "availability":
+		if s.unavailable {
+			return nil, newError(http.StatusServiceUnavailable, errorNumShuttingDown, "service unavailable")
+		}
+		return newResponse(http.StatusOK, map[string]interface{}{
+			"mode":        "default",
+			"readOnly":    false,
+			"maintenance": false,
+		}), nil
+	}
+	return nil, errUnknownPath()
+}
@@ -0,0 +1,407 @@
+package arangotest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+const testDBName = "test"
+
+type user struct {
+	ara.Document
+	Name string `json:"name"`
+	Age  int    `json:"age,omitempty"`
+}
+
+// responseRecorder is a middleware which keeps the last response to check
+// what the server returned beyond the results of the methods.
+type responseRecorder struct {
+	mu   sync.Mutex
+	resp *ara.Response
+}
+
+func (r *responseRecorder) middleware(next ara.Handler) ara.Handler {
+	return func(req *ara.Request) (*ara.Response, error) {
+		resp, err := next(req)
+		r.mu.Lock()
+		r.resp = resp
+		r.mu.Unlock()
+		return resp, err
+	}
+}
+
+func (r *responseRecorder) last() *ara.Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resp
+}
+
+func newConnection(t *testing.T, config *ara.Config) *ara.Connection {
+	t.Helper()
+	c, err := ara.NewConnection(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// setup starts a server with the test database and the users collection.
+func setup(t *testing.T) (*arangotest.Server, *ara.Connection, *responseRecorder) {
+	t.Helper()
+	s := arangotest.NewServer(nil)
+	t.Cleanup(s.Close)
+
+	rec := new(responseRecorder)
+	config := s.ConnectionConfig()
+	config.Middlewares = []ara.Middleware{rec.middleware}
+	c := newConnection(t, config)
+	if err := c.CreateDatabase(ara.CreateDatabaseConfig{Name: testDBName}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.CreateCollection(testDBName, ara.CreateCollectionConfig{Name: "users"}); err != nil {
+		t.Fatal(err)
+	}
+	return s, c, rec
+}
+
+func createUser(t *testing.T, c *ara.Connection, key, name string) ara.Document {
+	t.Helper()
+	doc, _, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": key, "name": name}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func checkError(t *testing.T, err error, rc, wantRC, wantErrorNum int) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("got no error, want status %d", wantRC)
+	}
+	if rc != wantRC {
+		t.Errorf("rc = %d, want %d; err = %v", rc, wantRC, err)
+	}
+	if want := "errorNum=" + strconv.Itoa(wantErrorNum) + ","; !strings.Contains(err.Error(), want) {
+		t.Errorf("err = %v, want errorNum %d", err, wantErrorNum)
+	}
+}
+
+func errorBody(t *testing.T, resp *ara.Response) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		t.Fatalf("invalid error body %q: %v", resp.Body, err)
+	}
+	return body
+}
+
+func TestNotFound(t *testing.T) {
+	_, c, _ := setup(t)
+
+	rc, err := c.ReadDocument(testDBName, "users/missing", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+
+	rc, err = c.ReadDocument(testDBName, "missing/key", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1203)
+
+	rc, err = c.ReadDocument("missing", "users/key", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1228)
+
+	_, rc, err = c.RemoveDocument(testDBName, "users", "missing", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+}
+
+func TestCreateDocumentConflict(t *testing.T) {
+	_, c, _ := setup(t)
+	createUser(t, c, "alice", "Alice")
+
+	_, rc, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "alice"}, nil, nil, nil)
+	checkError(t, err, rc, http.StatusConflict, 1210)
+
+	_, rc, err = c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "bad/key"}, nil, nil, nil)
+	checkError(t, err, rc, http.StatusBadRequest, 1221)
+}
+
+func TestReadDocumentConditional(t *testing.T) {
+	_, c, rec := setup(t)
+	doc := createUser(t, c, "alice", "Alice")
+
+	rc, err := c.ReadDocument(testDBName, doc.ID, &ara.ReadDocumentConfig{IfNoneMatch: `"` + doc.Rev + `"`}, nil)
+	if err != nil || rc != http.StatusNotModified {
+		t.Fatalf("rc = %d, err = %v, want 304", rc, err)
+	}
+	if got := rec.last().Header.Get("ETag"); got != `"`+doc.Rev+`"` {
+		t.Errorf("ETag = %s, want %q", got, doc.Rev)
+	}
+
+	var u user
+	rc, err = c.ReadDocument(testDBName, doc.ID, &ara.ReadDocumentConfig{IfNoneMatch: `"_old"`}, &u)
+	if err != nil || rc != http.StatusOK || u.Name != "Alice" || u.Rev != doc.Rev {
+		t.Fatalf("rc = %d, err = %v, doc = %+v", rc, err, u)
+	}
+
+	rc, err = c.ReadDocument(testDBName, doc.ID, &ara.ReadDocumentConfig{IfMatch: `"_old"`}, nil)
+	checkError(t, err, rc, http.StatusPreconditionFailed, 1200)
+}
+
+func TestPreconditionFailed(t *testing.T) {
+	_, c, rec := setup(t)
+	doc := createUser(t, c, "alice", "Alice")
+	r, _, err := c.ReplaceDocument(testDBName, doc.ID, map[string]interface{}{"name": "Alice 2"}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OldRev != doc.Rev || r.Rev == doc.Rev {
+		t.Fatalf("result = %+v, old rev = %s", r, doc.Rev)
+	}
+
+	checkBody := func() {
+		t.Helper()
+		body := errorBody(t, rec.last())
+		if body["_rev"] != r.Rev || body["_key"] != "alice" || body["_id"] != doc.ID {
+			t.Errorf("body = %v, want the current revision %s", body, r.Rev)
+		}
+	}
+
+	_, rc, err := c.ReplaceDocument(testDBName, doc.ID, map[string]interface{}{"name": "x"},
+		&ara.ReplaceDocumentConfig{IfMatch: doc.Rev}, nil, nil)
+	checkError(t, err, rc, http.StatusPreconditionFailed, 1200)
+	checkBody()
+
+	_, rc, err = c.UpdateDocument(testDBName, doc.ID, map[string]interface{}{"_rev": doc.Rev, "name": "x"},
+		&ara.UpdateDocumentConfig{IgnoreRevs: ara.FalsePtr()}, nil, nil)
+	checkError(t, err, rc, http.StatusPreconditionFailed, 1200)
+	checkBody()
+
+	_, rc, err = c.RemoveDocument(testDBName, "users", "alice", &ara.RemoveDocumentConfig{IfMatch: doc.Rev}, nil)
+	checkError(t, err, rc, http.StatusPreconditionFailed, 1200)
+	checkBody()
+
+	var got user
+	if _, err := c.ReadDocument(testDBName, doc.ID, nil, &got); err != nil || got.Name != "Alice 2" {
+		t.Fatalf("document = %+v, err = %v, want it unchanged", got, err)
+	}
+}
+
+func TestReturnOldAndNew(t *testing.T) {
+	_, c, _ := setup(t)
+	doc := createUser(t, c, "alice", "Alice")
+
+	var oldDoc, newDoc user
+	r, rc, err := c.ReplaceDocument(testDBName, doc.ID, map[string]interface{}{"name": "Alice", "age": 30},
+		&ara.ReplaceDocumentConfig{ReturnOld: ara.TruePtr(), ReturnNew: ara.TruePtr()}, &oldDoc, &newDoc)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if oldDoc.Rev != doc.Rev || oldDoc.Age != 0 {
+		t.Errorf("old = %+v", oldDoc)
+	}
+	if newDoc.Rev != r.Rev || newDoc.Key != "alice" || newDoc.Age != 30 {
+		t.Errorf("new = %+v, result = %+v", newDoc, r)
+	}
+
+	var updated map[string]interface{}
+	u, rc, err := c.UpdateDocument(testDBName, doc.ID, map[string]interface{}{"age": 31, "city": "Tokyo"},
+		&ara.UpdateDocumentConfig{ReturnNew: ara.TruePtr(), WaitForSync: ara.TruePtr()}, nil, &updated)
+	if err != nil || rc != http.StatusCreated {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if u.OldRev != r.Rev || updated["name"] != "Alice" || updated["age"] != float64(31) || updated["city"] != "Tokyo" {
+		t.Errorf("new = %v, result = %+v", updated, u)
+	}
+
+	var removed user
+	_, rc, err = c.RemoveDocument(testDBName, "users", "alice", &ara.RemoveDocumentConfig{ReturnOld: ara.TruePtr()}, &removed)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if removed.Rev != u.Rev || removed.Age != 31 {
+		t.Errorf("old = %+v", removed)
+	}
+}
+
+func TestOverwriteModes(t *testing.T) {
+	tests := []struct {
+		mode      string
+		overwrite *bool
+		wantRC    int
+		want      map[string]interface{}
+		wantOld   bool
+	}{
+		{mode: ara.OverwriteModeIgnore, wantRC: http.StatusAccepted, want: map[string]interface{}{"name": "Alice", "age": float64(30)}},
+		{mode: ara.OverwriteModeReplace, wantRC: http.StatusAccepted, want: map[string]interface{}{"name": "Bob"}, wantOld: true},
+		{mode: ara.OverwriteModeUpdate, wantRC: http.StatusAccepted, want: map[string]interface{}{"name": "Bob", "age": float64(30)}, wantOld: true},
+		{mode: ara.OverwriteModeConflict, wantRC: http.StatusConflict, want: map[string]interface{}{"name": "Alice", "age": float64(30)}},
+		{overwrite: ara.TruePtr(), wantRC: http.StatusAccepted, want: map[string]interface{}{"name": "Bob"}, wantOld: true},
+		{overwrite: ara.FalsePtr(), wantRC: http.StatusConflict, want: map[string]interface{}{"name": "Alice", "age": float64(30)}},
+	}
+	for _, tt := range tests {
+		name := tt.mode
+		if tt.overwrite != nil {
+			name = "overwrite=" + strconv.FormatBool(*tt.overwrite)
+		}
+		t.Run(name, func(t *testing.T) {
+			_, c, _ := setup(t)
+			orig, _, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "alice", "name": "Alice", "age": 30}, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var oldDoc map[string]interface{}
+			doc, rc, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "alice", "name": "Bob"},
+				&ara.CreateDocumentConfig{OverwriteMode: tt.mode, Overwrite: tt.overwrite, ReturnOld: ara.TruePtr()}, &oldDoc, nil)
+			if tt.wantRC == http.StatusConflict {
+				checkError(t, err, rc, http.StatusConflict, 1210)
+			} else if err != nil || rc != tt.wantRC {
+				t.Fatalf("rc = %d, err = %v", rc, err)
+			}
+			if tt.wantOld != (oldDoc != nil) {
+				t.Errorf("old = %v, want old %v", oldDoc, tt.wantOld)
+			}
+			if oldDoc != nil && oldDoc["_rev"] != orig.Rev {
+				t.Errorf("old rev = %v, want %s", oldDoc["_rev"], orig.Rev)
+			}
+			if tt.wantRC != http.StatusConflict && doc.Key != "alice" {
+				t.Errorf("result = %+v", doc)
+			}
+
+			var got map[string]interface{}
+			if _, err := c.ReadDocument(testDBName, "users/alice", nil, &got); err != nil {
+				t.Fatal(err)
+			}
+			for _, k := range []string{"_key", "_id", "_rev"} {
+				delete(got, k)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("document = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("document = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCreateDocumentsErrorCodes(t *testing.T) {
+	_, c, rec := setup(t)
+	createUser(t, c, "alice", "Alice")
+
+	docs := []interface{}{
+		map[string]interface{}{"_key": "bob", "name": "Bob"},
+		map[string]interface{}{"_key": "alice", "name": "Alice 2"},
+		map[string]interface{}{"_key": "bad key"},
+		map[string]interface{}{"_key": "alice", "name": "Alice 3"},
+		"not a document",
+	}
+	var newDocs []user
+	r, rc, err := c.CreateDocuments(testDBName, "users", docs, &ara.CreateDocumentsConfig{ReturnNew: ara.TruePtr()}, nil, &newDocs)
+	if err != nil || rc != http.StatusAccepted {
+		t.Fatalf("rc = %d, err = %v", rc, err)
+	}
+	if got, want := rec.last().Header.Get("X-Arango-Error-Codes"), `{"1210":2,"1221":1,"1227":1}`; got != want {
+		t.Errorf("X-Arango-Error-Codes = %s, want %s", got, want)
+	}
+	wantCodes := map[int]int{1210: 2, 1221: 1, 1227: 1}
+	if r.ErrorCount != 4 || len(r.ErrorCodes) != len(wantCodes) {
+		t.Fatalf("result = %+v", r)
+	}
+	for errorNum, n := range wantCodes {
+		if r.ErrorCodes[errorNum] != n {
+			t.Errorf("ErrorCodes = %v, want %v", r.ErrorCodes, wantCodes)
+		}
+	}
+	if len(r.Documents) != len(docs) {
+		t.Fatalf("documents = %+v", r.Documents)
+	}
+	if d := r.Documents[0]; d.Error || d.Key != "bob" || d.Rev == "" {
+		t.Errorf("documents[0] = %+v", d)
+	}
+	for i, errorNum := range []int{0, 1210, 1221, 1210, 1227} {
+		if d := r.Documents[i]; d.Error != (errorNum != 0) || d.ErrorNum != errorNum {
+			t.Errorf("documents[%d] = %+v, want errorNum %d", i, d, errorNum)
+		}
+	}
+	if len(newDocs) != len(docs) || newDocs[0].Name != "Bob" || newDocs[1].Key != "" {
+		t.Errorf("new = %+v", newDocs)
+	}
+
+	r, _, err = c.CreateDocuments(testDBName, "users", []interface{}{map[string]interface{}{"name": "Carol"}},
+		&ara.CreateDocumentsConfig{Silent: ara.TruePtr()}, nil, nil)
+	if err != nil || r.Documents != nil || r.ErrorCount != 0 {
+		t.Errorf("result = %+v, err = %v", r, err)
+	}
+	if got := rec.last().Header.Get("X-Arango-Error-Codes"); got != "" {
+		t.Errorf("X-Arango-Error-Codes = %s, want none", got)
+	}
+}
+
+func TestListAllDocuments(t *testing.T) {
+	_, c, _ := setup(t)
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		createUser(t, c, key, key)
+	}
+
+	cur, _, err := c.ListAllDocuments(testDBName, ara.ListAllDocumentsConfig{Collection: "users", Type: "key", BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	if err := cur.All(&keys); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, ",") != "a,b,c,d,e" {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+func TestBasicAuthentication(t *testing.T) {
+	s := arangotest.NewServer(&arangotest.Config{Username: "root", Password: "secret"})
+	defer s.Close()
+
+	c := newConnection(t, s.ConnectionConfig())
+	if _, err := c.ListDatabases(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := s.ConnectionConfig()
+	config.Password = "wrong"
+	c = newConnection(t, config)
+	if _, err := c.ListDatabases(); err == nil || !strings.Contains(err.Error(), "status=401") {
+		t.Errorf("err = %v, want 401", err)
+	}
+}
+
+func TestVelocyPack(t *testing.T) {
+	s, _, _ := setup(t)
+	rec := new(responseRecorder)
+	config := s.ConnectionConfig()
+	config.Codec = ara.VelocyPackCodec{}
+	config.Middlewares = []ara.Middleware{rec.middleware}
+	c := newConnection(t, config)
+
+	var newDoc user
+	doc, _, err := c.CreateDocument(testDBName, "users", user{Document: ara.Document{Key: "alice"}, Name: "Alice", Age: 30},
+		&ara.CreateDocumentConfig{ReturnNew: ara.TruePtr()}, nil, &newDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.last().Header.Get("Content-Type"); got != ara.ContentTypeVelocyPack {
+		t.Errorf("Content-Type = %s, want %s", got, ara.ContentTypeVelocyPack)
+	}
+	if newDoc.Key != "alice" || newDoc.Rev != doc.Rev || newDoc.Age != 30 {
+		t.Errorf("new = %+v", newDoc)
+	}
+
+	rc, err := c.ReadDocument(testDBName, "users/missing", nil, nil)
+	checkError(t, err, rc, http.StatusNotFound, 1202)
+}
@@ -0,0 +1,185 @@
+package arangotest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ara "github.com/hnakamur/arangogo"
+	"github.com/hnakamur/arangogo/arangotest"
+)
+
+const versionPath = "GET /_api/version?details=true"
+
+// newVersionConnection returns a connection which detects the version of a
+// server with the test database and the users collection. middlewares are
+// after a recorder of the requests.
+func newVersionConnection(t *testing.T, version string, middlewares ...ara.Middleware) (*ara.Connection, *pathRecorder) {
+	t.Helper()
+	s := arangotest.NewServer(&arangotest.Config{Version: version})
+	t.Cleanup(s.Close)
+	c := newConnection(t, s.ConnectionConfig())
+	if err := c.CreateDatabase(ara.CreateDatabaseConfig{Name: testDBName}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.CreateCollection(testDBName, ara.CreateCollectionConfig{Name: "users"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := new(pathRecorder)
+	config := s.ConnectionConfig()
+	config.Middlewares = append([]ara.Middleware{rec.middleware}, middlewares...)
+	return newConnection(t, config), rec
+}
+
+func TestVersionGating(t *testing.T) {
+	overwrite := true
+	features := []struct {
+		name     string
+		required int
+		call     func(c *ara.Connection) error
+	}{
+		{"overwrite", 30400, func(c *ara.Connection) error {
+			_, _, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "alice"}, &ara.CreateDocumentConfig{Overwrite: &overwrite}, nil, nil)
+			return err
+		}},
+		{"K_SHORTEST_PATHS", 30500, func(c *ara.Connection) error {
+			_, _, err := c.KShortestPaths(testDBName, "social", "persons/alice", "persons/bob", 2, nil, nil)
+			return err
+		}},
+		{"/_admin/metrics", 30600, func(c *ara.Connection) error {
+			_, _, err := c.GetServerMetrics()
+			return err
+		}},
+		{"overwriteMode", 30700, func(c *ara.Connection) error {
+			_, _, err := c.CreateDocument(testDBName, "users", map[string]interface{}{"_key": "bob"}, &ara.CreateDocumentConfig{OverwriteMode: ara.OverwriteModeReplace}, nil, nil)
+			return err
+		}},
+	}
+	for _, server := range []struct {
+		version string
+		number  int
+	}{
+		{"3.3.9", 30309},
+		{"3.4.0", 30400},
+		{"3.5.0", 30500},
+		{"3.6.0", 30600},
+		{"3.7.0-devel", 30700},
+	} {
+		t.Run(server.version, func(t *testing.T) {
+			c, rec := newVersionConnection(t, server.version)
+			for _, f := range features {
+				err := f.call(c)
+				e, unsupported := err.(*ara.ErrUnsupportedByServer)
+				if want := server.number < f.required; unsupported != want {
+					t.Errorf("%s: err = %v, want unsupported %v", f.name, err, want)
+					continue
+				}
+				if unsupported && (e.Feature != f.name || e.RequiredVersion != f.required || e.ServerVersion != server.number) {
+					t.Errorf("%s: err = %+v", f.name, e)
+				}
+			}
+			if n := rec.count(versionPath); n != 1 {
+				t.Errorf("version requests = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestVersionNotDetectedWithoutOptions(t *testing.T) {
+	c, rec := newVersionConnection(t, "3.11.0")
+	createUser(t, c, "alice", "Alice")
+	if n := rec.count(versionPath); n != 0 {
+		t.Errorf("version requests = %d, want 0", n)
+	}
+}
+
+func TestVersionFromConfig(t *testing.T) {
+	s := arangotest.NewServer(&arangotest.Config{Version: "3.5.0"})
+	defer s.Close()
+	rec := new(pathRecorder)
+	config := s.ConnectionConfig()
+	config.ArangoVersion = 30600
+	config.Middlewares = []ara.Middleware{rec.middleware}
+	c := newConnection(t, config)
+
+	// The version of the config is used even if the server is older.
+	if _, _, err := c.GetServerMetrics(); err != nil {
+		t.Fatal(err)
+	}
+	if n := rec.count(versionPath); n != 0 {
+		t.Errorf("version requests = %d, want 0", n)
+	}
+}
+
+// slowMiddleware delays the responses of the requests with the method and
+// path.
+func slowMiddleware(path string, d time.Duration) ara.Middleware {
+	return func(next ara.Handler) ara.Handler {
+		return func(req *ara.Request) (*ara.Response, error) {
+			if req.Method+" "+req.Path == path {
+				time.Sleep(d)
+			}
+			return next(req)
+		}
+	}
+}
+
+func TestVersionDetectedOnce(t *testing.T) {
+	c, rec := newVersionConnection(t, "3.11.0", slowMiddleware(versionPath, 50*time.Millisecond))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, errs[i] = c.GetServerMetrics()
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+		}
+	}
+	if n := rec.count(versionPath); n != 1 {
+		t.Errorf("version requests = %d, want 1", n)
+	}
+}
+
+func TestVersionDetectionFailure(t *testing.T) {
+	versionErr := errors.New("version unavailable")
+	c, rec := newVersionConnection(t, "3.11.0", failingMiddleware(versionPath, versionErr))
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.GetServerMetrics(); err == nil || !strings.Contains(err.Error(), versionErr.Error()) {
+			t.Errorf("%d: err = %v, want %v", i, err, versionErr)
+		}
+	}
+	// The failure is kept for a while instead of sending a request for every
+	// call.
+	if n := rec.count(versionPath); n != 1 {
+		t.Errorf("version requests = %d, want 1", n)
+	}
+}
+
+func TestVersionDetectionCanceled(t *testing.T) {
+	c, rec := newVersionConnection(t, "3.11.0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := c.WithContext(ctx).GetServerMetrics(); err == nil {
+		t.Fatal("got no error with a canceled context")
+	}
+	// The error of the canceled request is not kept.
+	if _, _, err := c.GetServerMetrics(); err != nil {
+		t.Fatal(err)
+	}
+	if n := rec.count(versionPath); n != 2 {
+		t.Errorf("version requests = %d, want 2", n)
+	}
+}
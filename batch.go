@@ -0,0 +1,208 @@
+package arangogo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// batch is the Batch returned from Connection.NewBatch.
+type batch struct {
+	conn   *Connection
+	dbName string
+	ops    []func(c Client)
+}
+
+type batchPart struct {
+	used       bool
+	req        *Request
+	registered chan struct{}
+	once       sync.Once
+	done       chan struct{}
+	resp       *Response
+	err        error
+}
+
+func newBatchPart() *batchPart {
+	return &batchPart{
+		registered: make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+func (p *batchPart) register() {
+	p.once.Do(func() {
+		close(p.registered)
+	})
+}
+
+func (c *Connection) NewBatch(dbName string) Batch {
+	return &batch{
+		conn:   c,
+		dbName: dbName,
+	}
+}
+
+func (b *batch) Add(op func(c Client)) {
+	b.ops = append(b.ops, op)
+}
+
+// sendBatchPart passes the request through the middlewares like send does,
+// but puts it in the batch instead of sending it.
+func (c *Connection) sendBatchPart(p *batchPart, req *Request, respBody interface{}) (rc int, resp *http.Response, err error) {
+	h := c.chain(func(req *Request) (*Response, error) {
+		p.req = req
+		p.register()
+		<-p.done
+		return p.resp, p.err
+	})
+	r, err := h(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp = r.httpResponse()
+	return resp.StatusCode, resp, c.decodeResponse(req.Method, c.url+req.Path, resp, r.Body, respBody)
+}
+
+func (b *batch) Send() (rc int, err error) {
+	parts := make([]*batchPart, len(b.ops))
+	var wg sync.WaitGroup
+	for i, op := range b.ops {
+		p := newBatchPart()
+		parts[i] = p
+		conn := *b.conn
+		conn.batchPart = p
+		wg.Add(1)
+		go func(op func(c Client)) {
+			defer wg.Done()
+			defer p.register()
+			op(&conn)
+		}(op)
+	}
+	var sent []*batchPart
+	for _, p := range parts {
+		<-p.registered
+		if p.req != nil {
+			sent = append(sent, p)
+		}
+	}
+
+	rc, err = b.send(sent)
+	if err != nil {
+		for _, p := range sent {
+			if p.resp == nil && p.err == nil {
+				p.err = err
+			}
+		}
+	}
+	for _, p := range sent {
+		close(p.done)
+	}
+	wg.Wait()
+	if err != nil {
+		return rc, fmt.Errorf("failed to send batch: %v", err)
+	}
+	return rc, nil
+}
+
+func (b *batch) send(parts []*batchPart) (rc int, err error) {
+	if len(parts) == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	hreqs := make([]*http.Request, len(parts))
+	for i, p := range parts {
+		hreqs[i], err = b.conn.httpRequest(p.req)
+		if err != nil {
+			return 0, err
+		}
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {"application/x-arango-batchpart"},
+			"Content-Id":   {strconv.Itoa(i)},
+		})
+		if err != nil {
+			return 0, err
+		}
+		err = hreqs[i].Write(pw)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	path := buildPath(pathConfig{
+		dbName:     b.dbName,
+		pathFormat: "/_api/batch",
+	})
+	header := make(http.Header)
+	header.Set("Content-Type", "multipart/form-data; boundary="+w.Boundary())
+	req := &Request{
+		Context: b.conn.context(),
+		Method:  http.MethodPost,
+		Path:    path,
+		Header:  b.conn.requestHeader(header),
+		Payload: buf.Bytes(),
+	}
+	resp, err := b.conn.handler(req)
+	if err != nil {
+		return 0, err
+	}
+
+	rc = resp.StatusCode
+	if rc >= http.StatusBadRequest {
+		return rc, b.conn.decodeResponse(req.Method, b.conn.url+req.Path, resp.httpResponse(), resp.Body, nil)
+	}
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return rc, fmt.Errorf("failed to parse batch response content type: %v", err)
+	}
+	r := multipart.NewReader(bytes.NewReader(resp.Body), params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return rc, fmt.Errorf("failed to read batch response part: %v", err)
+		}
+		i, err := strconv.Atoi(part.Header.Get("Content-Id"))
+		if err != nil || i < 0 || i >= len(parts) {
+			return rc, errors.New("invalid content id in batch response part")
+		}
+		hresp, err := http.ReadResponse(bufio.NewReader(part), hreqs[i])
+		if err != nil {
+			return rc, fmt.Errorf("failed to read batch response part: %v", err)
+		}
+		body, err := ioutil.ReadAll(hresp.Body)
+		hresp.Body.Close()
+		if err != nil {
+			return rc, fmt.Errorf("failed to read batch response part body: %v", err)
+		}
+		parts[i].resp = &Response{
+			StatusCode: hresp.StatusCode,
+			Header:     hresp.Header,
+			Body:       body,
+		}
+	}
+	for _, p := range parts {
+		if p.resp == nil {
+			p.err = errors.New("no response in batch response")
+		}
+	}
+	return rc, nil
+}
@@ -9,3 +9,7 @@ func FalsePtr() *bool {
 	v := false
 	return &v
 }
+
+func IntPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,348 @@
+package arangogo
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type DocumentCacheConfig struct {
+	// TTL is the duration while a cached document is used without
+	// revalidation. After that, the document is revalidated with
+	// If-None-Match.
+	TTL time.Duration
+	// MaxEntries is the maximum number of cached documents. The least
+	// recently used documents are evicted when it is exceeded.
+	MaxEntries int
+}
+
+const defaultDocumentCacheMaxEntries = 1024
+
+type documentCacheEntry struct {
+	key         string
+	rev         string
+	body        json.RawMessage
+	validatedAt time.Time
+	// async is set when the document was written asynchronously and the
+	// entry has not been revalidated since.
+	async bool
+}
+
+type documentCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	ll         *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+	// gen is incremented when entries are invalidated, so a read which
+	// started before an invalidation does not put what it read.
+	gen uint64
+	// asyncKeys and asyncPrefixes are the keys and prefixes of documents
+	// written asynchronously. The server applies such writes later, so
+	// their entries are not fresh until they are revalidated, which
+	// removes the key from asyncKeys. allAsync is set instead when
+	// asyncKeys grows beyond maxEntries. allAsync and asyncPrefixes are
+	// cleared when all the asyncEntries have been revalidated.
+	asyncKeys     map[string]bool
+	asyncPrefixes map[string]bool
+	allAsync      bool
+	asyncEntries  int
+}
+
+func newDocumentCache(config *DocumentCacheConfig) *documentCache {
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultDocumentCacheMaxEntries
+	}
+	return &documentCache{
+		ttl:        config.TTL,
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+
+		asyncKeys:     make(map[string]bool),
+		asyncPrefixes: make(map[string]bool),
+	}
+}
+
+func documentCacheKey(dbName, documentHandle string) string {
+	if dbName == "" {
+		dbName = SystemDatabaseName
+	}
+	return dbName + "\x00" + documentHandle
+}
+
+func documentCachePrefix(dbName, collName string) string {
+	if dbName == "" {
+		dbName = SystemDatabaseName
+	}
+	if collName == "" {
+		return dbName + "\x00"
+	}
+	return dbName + "\x00" + collName + "/"
+}
+
+// get returns a copy of the cached entry and whether it is still fresh.
+func (c *documentCache) get(key string) (e documentCacheEntry, fresh, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return e, false, false
+	}
+	c.ll.MoveToFront(elem)
+	e = *elem.Value.(*documentCacheEntry)
+	return e, c.now().Sub(e.validatedAt) < c.ttl && !e.async, true
+}
+
+func (c *documentCache) writtenAsync(key string) bool {
+	if c.allAsync || c.asyncKeys[key] {
+		return true
+	}
+	for prefix := range c.asyncPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// generation returns the current generation to be passed to put.
+func (c *documentCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.gen
+}
+
+// put adds or updates the entry unless entries were invalidated after gen
+// was obtained. revalidated is true when the document was read with
+// If-None-Match for the cached revision.
+func (c *documentCache) put(key, rev string, body json.RawMessage, gen uint64, revalidated bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*documentCacheEntry)
+		e.rev = rev
+		e.body = body
+		e.validatedAt = c.now()
+		if revalidated {
+			c.revalidated(e)
+		}
+		c.ll.MoveToFront(elem)
+		return
+	}
+	e := &documentCacheEntry{key: key, rev: rev, body: body, validatedAt: c.now()}
+	if c.writtenAsync(key) {
+		e.async = true
+		c.asyncEntries++
+	}
+	c.entries[key] = c.ll.PushFront(e)
+	for c.ll.Len() > c.maxEntries {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// touch marks the entry as revalidated after the server answered that the
+// cached revision is current.
+func (c *documentCache) touch(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*documentCacheEntry)
+		e.validatedAt = c.now()
+		c.revalidated(e)
+	}
+}
+
+func (c *documentCache) revalidated(e *documentCacheEntry) {
+	delete(c.asyncKeys, e.key)
+	if !e.async {
+		return
+	}
+	e.async = false
+	c.asyncEntries--
+	if c.asyncEntries == 0 {
+		c.allAsync = false
+		c.asyncPrefixes = make(map[string]bool)
+	}
+}
+
+func (c *documentCache) removeElement(elem *list.Element) {
+	e := elem.Value.(*documentCacheEntry)
+	if e.async {
+		c.asyncEntries--
+	}
+	c.ll.Remove(elem)
+	delete(c.entries, e.key)
+}
+
+// setAllAsync treats every document as written asynchronously, for writes
+// whose keys are not tracked.
+func (c *documentCache) setAllAsync() {
+	c.allAsync = true
+	c.asyncKeys = make(map[string]bool)
+	c.asyncPrefixes = make(map[string]bool)
+	for _, elem := range c.entries {
+		e := elem.Value.(*documentCacheEntry)
+		if !e.async {
+			e.async = true
+			c.asyncEntries++
+		}
+	}
+}
+
+func (c *documentCache) remove(key string, async bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+	if async && !c.allAsync {
+		c.asyncKeys[key] = true
+		if len(c.asyncKeys) > c.maxEntries {
+			c.setAllAsync()
+		}
+	}
+}
+
+func (c *documentCache) removePrefix(prefix string, async bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(elem)
+		}
+	}
+	if async && !c.allAsync {
+		c.asyncPrefixes[prefix] = true
+		if prefix == "" || len(c.asyncPrefixes) > c.maxEntries {
+			c.setAllAsync()
+		}
+	}
+}
+
+func (c *Connection) invalidateDocument(dbName, documentHandle string) {
+	if c.cache != nil {
+		c.cache.remove(documentCacheKey(dbName, documentHandle), c.asyncMode != "")
+	}
+}
+
+func (c *Connection) invalidateCollection(dbName, collName string) {
+	if c.cache != nil {
+		c.cache.removePrefix(documentCachePrefix(dbName, collName), c.asyncMode != "")
+	}
+}
+
+func (c *Connection) invalidateDatabase(dbName string) {
+	if c.cache != nil {
+		c.cache.removePrefix(documentCachePrefix(dbName, ""), c.asyncMode != "")
+	}
+}
+
+// readGraphForCache reads the graph before an operation which can delete
+// documents of its collections. ok is false when c has no cache or the graph
+// cannot be read.
+func (c *Connection) readGraphForCache(dbName, graphName string) (g Graph, ok bool) {
+	if c.cache == nil {
+		return g, false
+	}
+	g, _, err := c.plain().GetGraph(dbName, graphName)
+	return g, err == nil
+}
+
+// invalidateGraphCollections invalidates the collections of a graph read by
+// readGraphForCache, or the whole database when the graph was not read.
+func (c *Connection) invalidateGraphCollections(dbName string, collNames []string, ok bool) {
+	if c.cache == nil {
+		return
+	}
+	if !ok {
+		c.invalidateDatabase(dbName)
+		return
+	}
+	for _, name := range collNames {
+		c.invalidateCollection(dbName, name)
+	}
+}
+
+// useCache returns whether reads with the header are served from the cache.
+// Conditional reads by the caller and asynchronous reads bypass the cache.
+func (c *Connection) useCache(header http.Header) bool {
+	return c.cache != nil && c.asyncMode == "" && header == nil
+}
+
+// readThroughCache reads the document at path using the cache. unwrap extracts
+// the document from the response body. rc is http.StatusOK when the document
+// is served from the cache.
+func (c *Connection) readThroughCache(key, path string, unwrap func(b json.RawMessage) (json.RawMessage, error), documentPtr interface{}) (rev string, rc int, err error) {
+	e, fresh, ok := c.cache.get(key)
+	if ok && fresh {
+		return e.rev, http.StatusOK, unmarshalCachedDocument(e.body, documentPtr)
+	}
+
+	gen := c.cache.generation()
+	var header http.Header
+	if ok {
+		header = make(http.Header)
+		header.Set("if-none-match", `"`+e.rev+`"`)
+	}
+	var raw json.RawMessage
+	rc, resp, err := c.send(http.MethodGet, path, header, nil, &raw)
+	if err != nil {
+		if ok {
+			c.cache.remove(key, false)
+		}
+		return "", rc, err
+	}
+	if ok && rc == http.StatusNotModified {
+		c.cache.touch(key)
+		return e.rev, http.StatusOK, unmarshalCachedDocument(e.body, documentPtr)
+	}
+
+	body, err := unwrap(raw)
+	if err != nil {
+		return "", rc, fmt.Errorf("failed to decode response body: %v", err)
+	}
+	rev = revFromETag(resp.Header.Get("ETag"))
+	c.cache.put(key, rev, body, gen, ok)
+	return rev, rc, unmarshalCachedDocument(body, documentPtr)
+}
+
+func unmarshalCachedDocument(body json.RawMessage, documentPtr interface{}) error {
+	if documentPtr == nil {
+		return nil
+	}
+	err := json.Unmarshal(body, documentPtr)
+	if err != nil {
+		return fmt.Errorf("failed to decode cached document: %v", err)
+	}
+	return nil
+}
+
+// PurgeDocumentCache removes all documents from the document cache.
+func (c *Connection) PurgeDocumentCache() {
+	if c.cache != nil {
+		c.cache.removePrefix("", false)
+	}
+}
+
+func revFromETag(etag string) string {
+	return strings.Trim(etag, `"`)
+}
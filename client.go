@@ -0,0 +1,155 @@
+package arangogo
+
+import "context"
+
+// The interfaces below group the methods of Connection by concern so that
+// code using a connection can depend on a part of it and be tested with the
+// mocks in the arangomock package. Run go generate in arangomock after
+// changing them.
+
+type DatabaseClient interface {
+	CreateDatabase(config CreateDatabaseConfig) error
+	DropDatabase(name string) error
+	ListDatabases() ([]string, error)
+	ListUserDatabases() ([]string, error)
+}
+
+type CollectionClient interface {
+	CreateCollection(dbName string, config CreateCollectionConfig) (r CreateCollectionResult, rc int, err error)
+	DropCollection(dbName, collectionName string) (r DropCollectionResult, rc int, err error)
+	ListCollections(dbName string) (r []Collection, rc int, err error)
+	TruncateCollection(dbName, collectionName string) (r Collection, rc int, err error)
+}
+
+type DocumentClient interface {
+	ReadDocument(dbName, documentHandle string, config *ReadDocumentConfig, documentPtr interface{}) (rc int, err error)
+	ReadDocumentHeader(dbName, documentHandle string, config *ReadDocumentHeaderConfig) (rev string, rc int, err error)
+	ListAllDocuments(dbName string, config ListAllDocumentsConfig) (cur *Cursor, rc int, err error)
+	CreateDocument(dbName, collName string, data interface{}, config *CreateDocumentConfig, oldDocPtr, newDocPtr interface{}) (doc Document, rc int, err error)
+	CreateDocuments(dbName, collName string, data interface{}, config *CreateDocumentsConfig, oldDocsPtr, newDocsPtr interface{}) (r CreateDocumentsResult, rc int, err error)
+	ReplaceDocument(dbName, docHandle string, data interface{}, config *ReplaceDocumentConfig, oldDocPtr, newDocPtr interface{}) (r ReplaceDocumentResult, rc int, err error)
+	UpdateDocument(dbName, docHandle string, data interface{}, config *UpdateDocumentConfig, oldDocPtr, newDocPtr interface{}) (r UpdateDocumentResult, rc int, err error)
+	RemoveDocument(dbName, collName, key string, config *RemoveDocumentConfig, docPtr interface{}) (doc Document, rc int, err error)
+	UpdateWithRetry(dbName, docHandle string, docPtr interface{}, mutate func(docPtr interface{}) error, config *UpdateWithRetryConfig) (r ReplaceDocumentResult, rc int, err error)
+}
+
+type GraphClient interface {
+	ListGraphs(dbName string, graphsPtr interface{}) (rc int, err error)
+	CreateGraph(dbName string, data interface{}) (g Graph, rc int, err error)
+	GetGraph(dbName, graphName string) (g Graph, rc int, err error)
+	DropGraph(dbName, graphName string, config *DropGraphConfig) (removed bool, rc int, err error)
+	EnsureGraph(dbName string, definition CreateGraphConfig, config *EnsureGraphConfig) (r EnsureGraphResult, rc int, err error)
+
+	ListVertexCollections(dbName, graphName string) (collections []string, rc int, err error)
+	AddVertexCollection(dbName, graphName, collectionName string, config *AddVertexCollectionConfig) (r AddVertexCollectionResult, rc int, err error)
+	RemoveVertexCollection(dbName, graphName, collectionName string, config *RemoveVertexCollectionConfig) (r RemoveVertexCollectionResult, rc int, err error)
+	ListEdgeDefinitions(dbName, graphName string) (collections []string, rc int, err error)
+	AddEdgeDefinition(dbName, graphName string, edgeDefinition interface{}) (r AddEdgeDefinitionResult, rc int, err error)
+	RemoveEdgeDefinition(dbName, graphName, definitionName string) (r RemoveEdgeDefinitionResult, rc int, err error)
+	ReplaceEdgeDefinition(dbName, graphName, definitionName string, edgeDefinition EdgeDefinition, config *ReplaceEdgeDefinitionConfig) (g Graph, rc int, err error)
+
+	CreateVertex(dbName, graphName, collName string, data interface{}, config *CreateVertexConfig, newVertexPtr interface{}) (r CreateVertexResult, rc int, err error)
+	GetVertex(dbName, graphName, collName, vertexKey string, config *GetVertexConfig, vertexPtr interface{}) (r GetVertexResult, rc int, err error)
+	ModifyVertex(dbName, graphName, collName, vertexKey string, data interface{}, config *ModifyVertexConfig, oldVertexPtr, newVertexPtr interface{}) (r ModifyVertexResult, rc int, err error)
+	ReplaceVertex(dbName, graphName, collName, vertexKey string, data interface{}, config *ReplaceVertexConfig, oldVertexPtr, newVertexPtr interface{}) (r ReplaceVertexResult, rc int, err error)
+	RemoveVertex(dbName, graphName, collName, vertexKey string, config *RemoveVertexConfig, oldVertexPtr interface{}) (removed bool, rc int, err error)
+	UpdateVertexWithRetry(dbName, graphName, collName, vertexKey string, vertexPtr interface{}, mutate func(vertexPtr interface{}) error, config *UpdateWithRetryConfig) (r ReplaceVertexResult, rc int, err error)
+
+	CreateEdge(dbName, graphName, collName string, data interface{}, config *CreateEdgeConfig, newEdgePtr interface{}) (r CreateEdgeResult, rc int, err error)
+	GetEdge(dbName, graphName, collName, edgeKey string, config *GetEdgeConfig, edgePtr interface{}) (r GetEdgeResult, rc int, err error)
+	ModifyEdge(dbName, graphName, collName, edgeKey string, data interface{}, config *ModifyEdgeConfig, oldEdgePtr, newEdgePtr interface{}) (edge ModifyEdgeResult, rc int, err error)
+	ReplaceEdge(dbName, graphName, collName, edgeKey string, data interface{}, config *ReplaceEdgeConfig, oldEdgePtr, newEdgePtr interface{}) (edge ReplaceEdgeResult, rc int, err error)
+	RemoveEdge(dbName, graphName, collName, edgeKey string, config *RemoveEdgeConfig, oldEdgePtr interface{}) (removed bool, rc int, err error)
+	Edges(dbName, collName, vertexHandle, direction string, edgesPtr interface{}) (stats ListAllDocumentsResultStats, rc int, err error)
+
+	Traverse(dbName, graphName, startVertex string, config TraversalConfig, verticesPtr, pathsPtr interface{}) (rc int, err error)
+	ShortestPath(dbName, graphName, from, to string, config *ShortestPathConfig, path *Path) (found bool, rc int, err error)
+	KShortestPaths(dbName, graphName, from, to string, k int, config *ShortestPathConfig, newPath func() Path) (paths []Path, rc int, err error)
+}
+
+type SimpleQueryClient interface {
+	ByExample(dbName string, config ByExampleConfig) (cur *Cursor, rc int, err error)
+	FirstExample(dbName string, config FirstExampleConfig, docPtr interface{}) (rc int, err error)
+	Any(dbName string, config AnyConfig, docPtr interface{}) (found bool, rc int, err error)
+	Range(dbName string, config RangeConfig) (cur *Cursor, rc int, err error)
+	Near(dbName string, config NearConfig) (cur *Cursor, rc int, err error)
+	Within(dbName string, config WithinConfig) (cur *Cursor, rc int, err error)
+	Fulltext(dbName string, config FulltextConfig) (cur *Cursor, rc int, err error)
+	LookupByKeys(dbName string, config LookupByKeysConfig, docsPtr interface{}) (rc int, err error)
+	RemoveByKeys(dbName string, config RemoveByKeysConfig, oldDocsPtr interface{}) (r RemoveByKeysResult, rc int, err error)
+	RemoveByExample(dbName string, config RemoveByExampleConfig) (deleted int, rc int, err error)
+	UpdateByExample(dbName string, config UpdateByExampleConfig) (updated int, rc int, err error)
+	ReplaceByExample(dbName string, config ReplaceByExampleConfig) (replaced int, rc int, err error)
+}
+
+type JobClient interface {
+	Async(mode string, job *AsyncJob) Client
+	GetJobResult(dbName, jobID string, respBody interface{}) (done bool, rc int, err error)
+	GetJobStatus(dbName, jobID string) (status string, rc int, err error)
+	ListJobs(dbName, status string, count int) (ids []string, rc int, err error)
+	CancelJob(dbName, jobID string) (rc int, err error)
+	DeleteJobs(dbName, target string, config *DeleteJobsConfig) (rc int, err error)
+}
+
+type BatchClient interface {
+	NewBatch(dbName string) Batch
+}
+
+// Batch sends the requests of multiple operations in one HTTP request to
+// /_api/batch.
+//
+// Each operation is a function which calls methods of the client passed to
+// it. The first request of each operation is put in the batch, and the
+// results and errors are returned from the methods as usual when the batch
+// response arrives. The later requests of an operation, if any, are sent
+// separately after the batch response arrives, and Send returns after them.
+// Operations which send no request are not in the batch, and no batch is
+// sent when no operation sends a request.
+//
+// The requests of the batch parts pass through the middlewares of the
+// connection like other requests, and so does the batch request itself.
+// A part is left out of the batch when a middleware returns an error for it,
+// and every part gets the error when the batch request fails.
+// The request detecting the server version is never put in the batch.
+type Batch interface {
+	// Add adds an operation to the batch. op is called when the batch is
+	// sent.
+	Add(op func(c Client))
+	// Send calls the operations, sends their requests in a batch and waits
+	// for the operations to finish. err is the error of the batch request
+	// itself.
+	Send() (rc int, err error)
+}
+
+type ServerClient interface {
+	Version() (v VersionInfo, rc int, err error)
+	ServerRole() (role string, rc int, err error)
+	Engine() (e EngineInfo, rc int, err error)
+	Ping(ctx context.Context) (rc int, err error)
+	CheckAvailability(ctx context.Context) (rc int, err error)
+	WaitForReady(ctx context.Context, config *WaitForReadyConfig) error
+}
+
+// Client has the methods of all the interfaces above except Batch.
+type Client interface {
+	DatabaseClient
+	CollectionClient
+	DocumentClient
+	GraphClient
+	SimpleQueryClient
+	JobClient
+	BatchClient
+	ServerClient
+}
+
+var (
+	_ Client            = (*Connection)(nil)
+	_ DatabaseClient    = (*Connection)(nil)
+	_ CollectionClient  = (*Connection)(nil)
+	_ DocumentClient    = (*Connection)(nil)
+	_ GraphClient       = (*Connection)(nil)
+	_ SimpleQueryClient = (*Connection)(nil)
+	_ JobClient         = (*Connection)(nil)
+	_ BatchClient       = (*Connection)(nil)
+	_ ServerClient      = (*Connection)(nil)
+)
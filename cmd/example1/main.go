@@ -92,7 +92,7 @@ func run(username, password string) (err error) {
 		Rev  string `json:"_rev"`
 		Name string `json:"name"`
 	}
-	doc, rc, err := c.CreateDocument(dbName, collName, data, &ara.CreateDocumentConfig{ReturnNew: ara.TruePtr()}, &docBody)
+	doc, rc, err := c.CreateDocument(dbName, collName, data, &ara.CreateDocumentConfig{ReturnNew: ara.TruePtr()}, nil, &docBody)
 	if err != nil {
 		return err
 	}
@@ -105,11 +105,12 @@ func run(username, password string) (err error) {
 	}
 	//data2 := `{1:"Foo"},{2:"Bad"}`
 	//data2 := `[{"name":"Foo"},{"name":"Bar"}]`
-	docs, rc, err := c.CreateDocuments(dbName, collName, data2, nil)
+	createDocumentsRes, rc, err := c.CreateDocuments(dbName, collName, data2, nil, nil, nil)
 	if err != nil {
 		log.Printf("err=%v", err)
 		return err
 	}
+	docs := createDocumentsRes.Documents
 	log.Printf("CreateDocuments. docs=%v, rc=%d", docs, rc)
 
 	var docBody2 interface{}
@@ -141,14 +142,20 @@ func run(username, password string) (err error) {
 	}
 	log.Printf("ReadDocumentHeader. rev=%s, rc=%d", rev, rc)
 
-	listAllDocumentsRes, rc, err := c.ListAllDocuments(dbName, ara.ListAllDocumentsConfig{Collection: collName})
+	listAllDocumentsCur, rc, err := c.ListAllDocuments(dbName, ara.ListAllDocumentsConfig{Collection: collName})
 	if err != nil {
 		log.Printf("err=%v", err)
 		return err
 	}
-	log.Printf("ListAllDocuments. res=%v, rc=%d", listAllDocumentsRes, rc)
+	var keys []string
+	err = listAllDocumentsCur.All(&keys)
+	if err != nil {
+		log.Printf("err=%v", err)
+		return err
+	}
+	log.Printf("ListAllDocuments. keys=%v, rc=%d", keys, rc)
 
-	listAllDocumentsRes, rc, err = c.ListAllDocuments(dbName, ara.ListAllDocumentsConfig{Collection: "non-existing-collectionn"})
+	_, rc, err = c.ListAllDocuments(dbName, ara.ListAllDocumentsConfig{Collection: "non-existing-collectionn"})
 	if err != nil {
 		log.Printf("err=%v", err)
 		// NOTE: This is an intentional error, so let's continue
@@ -204,7 +211,7 @@ func run(username, password string) (err error) {
 	waitForSync := true
 	edgeDocs, rc, err := c.CreateDocuments(dbName, edgeCollName, edges, &ara.CreateDocumentsConfig{
 		WaitForSync: &waitForSync,
-	})
+	}, nil, nil)
 	if err != nil {
 		log.Printf("err=%v", err)
 		return err
@@ -132,14 +132,14 @@ func run(username, password string) (err error) {
 	collName := "startVertices"
 	createVertexRes, rc, err := c.CreateVertex(dbName, graphName, collName,
 		map[string]string{"name": "Francis"},
-		&ara.CreateVertexConfig{WaitForSync: ara.TruePtr()})
+		&ara.CreateVertexConfig{WaitForSync: ara.TruePtr()}, nil)
 	if err != nil {
 		return err
 	}
 	log.Printf("CreateVertex. createVertexRes=%v, rc=%d", createVertexRes, rc)
 
 	modifyVertexRes, rc, err := c.ModifyVertex(dbName, graphName, collName, createVertexRes.Key,
-		map[string]interface{}{"age": 26}, &ara.ModifyVertexConfig{WaitForSync: ara.TruePtr()})
+		map[string]interface{}{"age": 26}, &ara.ModifyVertexConfig{WaitForSync: ara.TruePtr()}, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -147,14 +147,14 @@ func run(username, password string) (err error) {
 
 	replaceVertexRes, rc, err := c.ReplaceVertex(dbName, graphName, collName, createVertexRes.Key,
 		map[string]interface{}{"name": "Alice Cooper", "age": 26},
-		&ara.ReplaceVertexConfig{WaitForSync: ara.TruePtr()})
+		&ara.ReplaceVertexConfig{WaitForSync: ara.TruePtr()}, nil, nil)
 	if err != nil {
 		return err
 	}
 	log.Printf("ReplaceVertex. replaceVertexRes=%v, rc=%d", replaceVertexRes, rc)
 
 	var vertex interface{}
-	rc, err = c.GetVertex(dbName, graphName, collName, createVertexRes.Key, nil, &vertex)
+	_, rc, err = c.GetVertex(dbName, graphName, collName, createVertexRes.Key, nil, &vertex)
 	if err != nil {
 		return err
 	}
@@ -165,7 +165,7 @@ func run(username, password string) (err error) {
 			"type":  "friend",
 			"_from": "female/alice",
 			"_to":   "female/diana",
-		}, &ara.CreateEdgeConfig{WaitForSync: ara.TruePtr()})
+		}, &ara.CreateEdgeConfig{WaitForSync: ara.TruePtr()}, nil)
 	if err != nil {
 		return err
 	}
@@ -174,7 +174,7 @@ func run(username, password string) (err error) {
 	modifyEdgeRes, rc, err := c.ModifyEdge(dbName, graphName, collName, createEdgeRes.Key,
 		map[string]interface{}{
 			"since": "01.01.2001",
-		}, &ara.ModifyEdgeConfig{WaitForSync: ara.TruePtr()})
+		}, &ara.ModifyEdgeConfig{WaitForSync: ara.TruePtr()}, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -185,7 +185,7 @@ func run(username, password string) (err error) {
 			"type":  "divorced",
 			"_from": "female/alice",
 			"_to":   "male/bob",
-		}, &ara.ReplaceEdgeConfig{WaitForSync: ara.TruePtr(), IfMatch: modifyEdgeRes.Rev})
+		}, &ara.ReplaceEdgeConfig{WaitForSync: ara.TruePtr(), IfMatch: modifyEdgeRes.Rev}, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -198,21 +198,21 @@ func run(username, password string) (err error) {
 	log.Printf("GetGraph. graph=%v, rc=%d", graph, rc)
 
 	var edge interface{}
-	rc, err = c.GetEdge(dbName, graphName, collName, createEdgeRes.Key, nil, &edge)
+	_, rc, err = c.GetEdge(dbName, graphName, collName, createEdgeRes.Key, nil, &edge)
 	if err != nil {
 		return err
 	}
 	log.Printf("GetEdge. edge=%v, rc=%d", edge, rc)
 
 	removed, rc, err := c.RemoveEdge(dbName, graphName, collName, createEdgeRes.Key,
-		&ara.RemoveEdgeConfig{WaitForSync: ara.FalsePtr()})
+		&ara.RemoveEdgeConfig{WaitForSync: ara.FalsePtr()}, nil)
 	if err != nil {
 		return err
 	}
 	log.Printf("RemoveEdge. removed=%v, rc=%d", removed, rc)
 
 	removed, rc, err = c.RemoveVertex(dbName, graphName, collName, createVertexRes.Key,
-		&ara.RemoveVertexConfig{WaitForSync: ara.FalsePtr()})
+		&ara.RemoveVertexConfig{WaitForSync: ara.FalsePtr()}, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,76 @@
+package arangogo
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/hnakamur/arangogo/vpack"
+)
+
+// Codec encodes request payloads and decodes response bodies. Its content
+// type is sent in the Content-Type header of requests with a payload and in
+// the Accept header.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+const (
+	ContentTypeJSON       = "application/json"
+	ContentTypeVelocyPack = vpack.ContentType
+)
+
+// JSONCodec is the default codec.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// VelocyPackCodec uses VelocyPack, the binary format ArangoDB stores
+// documents in, which saves both sides from converting large documents to
+// and from JSON. Structs are encoded with their json tags.
+type VelocyPackCodec struct{}
+
+func (VelocyPackCodec) ContentType() string { return ContentTypeVelocyPack }
+
+func (VelocyPackCodec) Marshal(v interface{}) ([]byte, error) { return vpack.Marshal(v) }
+
+func (VelocyPackCodec) Unmarshal(data []byte, v interface{}) error { return vpack.Unmarshal(data, v) }
+
+// responseCodec returns the codec for the Content-Type of a response. The
+// server answers in JSON for some endpoints even if another content type is
+// accepted.
+func (c *Connection) responseCodec(header http.Header) Codec {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == c.codec.ContentType() {
+		return c.codec
+	}
+	return BodyCodec(header)
+}
+
+// BodyCodec returns the codec for the Content-Type of a request or response
+// body in middlewares. Bodies which are not VelocyPack are JSON.
+func BodyCodec(header http.Header) Codec {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == ContentTypeVelocyPack {
+		return VelocyPackCodec{}
+	}
+	return JSONCodec{}
+}
+
+// bodyString returns the response body for messages. VelocyPack bodies are
+// converted to JSON.
+func bodyString(header http.Header, b []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == ContentTypeVelocyPack {
+		if j, err := vpack.ToJSON(b); err == nil {
+			return string(j)
+		}
+	}
+	return string(b)
+}
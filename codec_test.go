@@ -0,0 +1,70 @@
+package arangogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseCodec(t *testing.T) {
+	tests := []struct {
+		codec       Codec
+		contentType string
+		want        Codec
+	}{
+		{JSONCodec{}, "application/json; charset=utf-8", JSONCodec{}},
+		{JSONCodec{}, ContentTypeVelocyPack, VelocyPackCodec{}},
+		{VelocyPackCodec{}, ContentTypeVelocyPack, VelocyPackCodec{}},
+		{VelocyPackCodec{}, "application/json; charset=utf-8", JSONCodec{}},
+		{VelocyPackCodec{}, "text/plain", JSONCodec{}},
+		{VelocyPackCodec{}, "", JSONCodec{}},
+	}
+	for _, tt := range tests {
+		c := &Connection{codec: tt.codec}
+		header := make(http.Header)
+		if tt.contentType != "" {
+			header.Set("Content-Type", tt.contentType)
+		}
+		if got := c.responseCodec(header); got != tt.want {
+			t.Errorf("%T with %q: got %T, want %T", tt.codec, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+// TestVelocyPackConnectionWithJSONResponses checks that a VelocyPack
+// connection decodes the responses of endpoints answering in JSON.
+func TestVelocyPackConnectionWithJSONResponses(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != ContentTypeVelocyPack {
+			t.Errorf("Accept = %q, want %q", got, ContentTypeVelocyPack)
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":true,"code":404,"errorNum":1202,"errorMessage":"document not found"}`))
+			return
+		}
+		w.Header().Set("ETag", `"_rev1"`)
+		w.Write([]byte(`{"_key":"alice","_id":"users/alice","_rev":"_rev1","name":"Alice"}`))
+	}))
+	defer s.Close()
+
+	c, err := NewConnection(&Config{URL: s.URL, Codec: VelocyPackCodec{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Key  string `json:"_key"`
+		Name string `json:"name"`
+	}
+	rc, err := c.ReadDocument("test", "users/alice", nil, &doc)
+	if err != nil || rc != http.StatusOK || doc.Key != "alice" || doc.Name != "Alice" {
+		t.Errorf("rc = %d, err = %v, doc = %+v", rc, err, doc)
+	}
+
+	rc, err = c.ReadDocument("test", "users/missing", nil, &doc)
+	if err == nil || rc != http.StatusNotFound || !strings.Contains(err.Error(), "errorNum=1202,") {
+		t.Errorf("rc = %d, err = %v", rc, err)
+	}
+}
@@ -62,6 +62,7 @@ func (c *Connection) DropCollection(dbName, collectionName string) (r DropCollec
 	})
 
 	rc, _, err = c.send(http.MethodDelete, path, nil, nil, &r)
+	c.invalidateCollection(dbName, collectionName)
 	if err != nil {
 		return r, rc, fmt.Errorf("failed to drop collection: %v", err)
 	}
@@ -92,6 +93,7 @@ func (c *Connection) TruncateCollection(dbName, collectionName string) (r Collec
 	})
 
 	rc, _, err = c.send(http.MethodPut, path, nil, nil, &r)
+	c.invalidateCollection(dbName, collectionName)
 	if err != nil {
 		return r, rc, fmt.Errorf("failed to truncate collection: %v", err)
 	}
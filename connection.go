@@ -1,47 +1,64 @@
 package arangogo
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 )
 
 type Config struct {
-	URL           string
+	URL string
+	// ArangoVersion is the server version like 30500 for 3.5.0. It is used to
+	// check options which require newer servers. When it is zero, the version
+	// is detected with Version the first time such an option is used, which
+	// is one more request. Zero used to mean 30000 (3.0.0), so set it to skip
+	// the detection.
 	ArangoVersion int
 	Username      string
 	Password      string
 	Header        http.Header
 	Logger        Logger
+	DocumentCache *DocumentCacheConfig
+	// Middlewares are applied to every request in order, so the first one
+	// sees the request first.
+	Middlewares []Middleware
+	// Codec encodes payloads and decodes responses. It defaults to
+	// JSONCodec. Responses are decoded with JSONCodec when the server
+	// answers in JSON.
+	Codec Codec
 }
 
 type Connection struct {
-	client        *http.Client
-	url           string
-	arangoVersion int
-	username      string
-	password      string
-	header        http.Header
-	logger        Logger
-}
-
-const (
-	defaultURL          = "http://localhost:8529"
-	defaultArangoVesion = 30000
-)
+	client      *http.Client
+	url         string
+	version     *serverVersion
+	username    string
+	password    string
+	header      http.Header
+	logger      Logger
+	cache       *documentCache
+	asyncMode   string
+	asyncJob    *AsyncJob
+	batchPart   *batchPart
+	middlewares []Middleware
+	codec       Codec
+	handler     Handler
+	ctx         context.Context
+}
+
+const defaultURL = "http://localhost:8529"
 
 const SystemDatabaseName = "_system"
 
 func NewConnection(config *Config) (*Connection, error) {
 	c := &Connection{
-		client:        new(http.Client),
-		url:           defaultURL,
-		arangoVersion: defaultArangoVesion,
+		client:  new(http.Client),
+		url:     defaultURL,
+		version: new(serverVersion),
+		codec:   JSONCodec{},
 	}
 	if config != nil {
 		if config.URL != "" {
@@ -52,7 +69,7 @@ func NewConnection(config *Config) (*Connection, error) {
 			c.url = config.URL
 		}
 		if config.ArangoVersion != 0 {
-			c.arangoVersion = config.ArangoVersion
+			c.version.version = config.ArangoVersion
 		}
 		if config.Username != "" {
 			c.username = config.Username
@@ -64,93 +81,161 @@ func NewConnection(config *Config) (*Connection, error) {
 			c.header = config.Header
 		}
 		c.logger = config.Logger
+		if config.DocumentCache != nil {
+			c.cache = newDocumentCache(config.DocumentCache)
+		}
+		c.middlewares = append([]Middleware(nil), config.Middlewares...)
+		if config.Codec != nil {
+			c.codec = config.Codec
+		}
 	}
+	c.handler = c.buildHandler()
 	return c, nil
 }
 
+// buildHandler chains the middlewares to the methods of c. It must be called
+// again for a copy of c with a different URL.
+func (c *Connection) buildHandler() Handler {
+	return c.chain(c.roundTrip)
+}
+
+// plain returns a copy of c whose requests are neither put in a batch nor
+// sent asynchronously.
+func (c *Connection) plain() *Connection {
+	pc := *c
+	pc.asyncMode = ""
+	pc.asyncJob = nil
+	pc.batchPart = nil
+	return &pc
+}
+
+// chain wraps h with the middlewares of c.
+func (c *Connection) chain(h Handler) Handler {
+	middlewares := append([]Middleware(nil), c.middlewares...)
+	if c.logger != nil {
+		middlewares = append(middlewares, c.loggingMiddleware)
+	}
+	return chainMiddlewares(h, middlewares...)
+}
+
 type HTTPError struct {
 	error
 	StatusCode int
 }
 
 func (c *Connection) send(method, path string, header http.Header, payload, respBody interface{}) (rc int, resp *http.Response, err error) {
-	var reader io.Reader
 	var payloadBytes []byte
 	if payload != nil {
 		var err error
-		payloadBytes, err = json.Marshal(payload)
+		payloadBytes, err = c.codec.Marshal(payload)
 		if err != nil {
 			return 0, nil, fmt.Errorf("failed to encode request payload: %v", err)
 		}
-		reader = bytes.NewBuffer(payloadBytes)
 	}
-	url := c.url + path
-	req, err := http.NewRequest(method, url, reader)
-	if err != nil {
-		return 0, nil, fmt.Errorf("failed to create request: %v", err)
-	}
-	if c.header != nil {
-		req.Header = c.header
-	}
-	if header != nil {
-		if req.Header == nil {
-			req.Header = header
-		} else {
-			for k, vv := range header {
-				for _, v := range vv {
-					req.Header.Add(k, v)
-				}
-			}
-		}
+	req := c.newRequest(method, path, header, payloadBytes)
+	if payload != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", c.codec.ContentType())
 	}
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", c.codec.ContentType())
+	}
+	if p := c.batchPart; p != nil && !p.used {
+		p.used = true
+		return c.sendBatchPart(p, req, respBody)
 	}
 
-	resp, err = c.client.Do(req)
+	r, err := c.handler(req)
 	if err != nil {
-		return 0, resp, fmt.Errorf("failed to send request: %v", err)
+		return 0, nil, err
 	}
-	defer resp.Body.Close()
-
+	resp = r.httpResponse()
 	rc = resp.StatusCode
-	b, err := ioutil.ReadAll(resp.Body)
+	if c.asyncJob != nil {
+		c.asyncJob.setID(resp.Header.Get("x-arango-async-id"))
+	}
+	return rc, resp, c.decodeResponse(req.Method, c.url+req.Path, resp, r.Body, respBody)
+}
+
+// sendRaw sends a request without a payload and returns the response body
+// without decoding it. It is for responses which are not JSON.
+func (c *Connection) sendRaw(method, path string, header http.Header) (rc int, body []byte, err error) {
+	req := c.newRequest(method, path, header, nil)
+	r, err := c.handler(req)
 	if err != nil {
-		return rc, resp, fmt.Errorf("failed to read response body: %v", err)
+		return 0, nil, err
+	}
+	if r.StatusCode >= http.StatusBadRequest {
+		return r.StatusCode, nil, c.decodeResponse(req.Method, c.url+req.Path, r.httpResponse(), r.Body, nil)
 	}
+	return r.StatusCode, r.Body, nil
+}
 
-	if c.logger != nil {
-		var reqH []byte
-		for k, vv := range req.Header {
-			for _, v := range vv {
-				reqH = append(reqH, ", "+k+"="+v...)
-			}
-		}
-		var payloadStr string
-		if len(payloadBytes) > 0 {
-			payloadStr = string(payloadBytes)
-		}
+func (c *Connection) newRequest(method, path string, header http.Header, payload []byte) *Request {
+	ctx := c.context()
+	return &Request{
+		Context: ctx,
+		Method:  method,
+		Path:    path,
+		Header:  c.requestHeader(header),
+		Payload: payload,
+		Attempt: attemptFromContext(ctx),
+	}
+}
 
-		var respH []byte
-		for k, vv := range resp.Header {
-			for _, v := range vv {
-				respH = append(respH, ", "+k+"="+v...)
-			}
-		}
-		var bodyStr string
-		if len(b) > 0 {
-			bodyStr = string(b)
+// WithContext returns a connection which sends requests with ctx.
+// The requests are canceled when ctx is done, and middlewares can get
+// values like the trace context from ctx.
+func (c *Connection) WithContext(ctx context.Context) *Connection {
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
+
+// WithoutMiddlewares returns a connection which sends requests without the
+// middlewares of Config. It is for requests which middlewares should not see,
+// like the scrapes of a metrics collector whose middleware is on c.
+func (c *Connection) WithoutMiddlewares() *Connection {
+	cc := *c
+	cc.middlewares = nil
+	cc.handler = cc.buildHandler()
+	return &cc
+}
+
+func (c *Connection) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *Connection) requestHeader(header http.Header) http.Header {
+	h := make(http.Header)
+	for k, vv := range c.header {
+		h[k] = append([]string(nil), vv...)
+	}
+	for k, vv := range header {
+		for _, v := range vv {
+			h.Add(k, v)
 		}
-		c.logger.Log(fmt.Sprintf("Connection send. method=%s, url=%s%s, payload=%s, status=%s%s, resBody=%s", method, url, string(reqH), payloadStr, resp.Status, respH, bodyStr))
 	}
+	if c.username != "" && c.password != "" {
+		h.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password)))
+	}
+	if c.asyncMode != "" {
+		h.Set("x-arango-async", c.asyncMode)
+	}
+	return h
+}
 
+func (c *Connection) decodeResponse(method, url string, resp *http.Response, b []byte, respBody interface{}) error {
 	if len(b) > 0 {
+		codec := c.responseCodec(resp.Header)
 		errBody := new(struct {
 			Error        bool   `json:"error"`
 			ErrorNum     int    `json:"errorNum"`
 			ErrorMessage string `json:"errorMessage"`
 		})
-		err2 := json.Unmarshal(b, errBody)
+		err2 := codec.Unmarshal(b, errBody)
 		if err2 == nil && errBody.Error {
 			msg := fmt.Sprintf("error from ArangoDB. status=%d", resp.StatusCode)
 			if errBody.ErrorNum != 0 {
@@ -160,13 +245,13 @@ func (c *Connection) send(method, path string, header http.Header, payload, resp
 				msg += fmt.Sprintf(", errorMessage=%s", errBody.ErrorMessage)
 			}
 			msg += fmt.Sprintf(", method=%s, url=%s", method, url)
-			return rc, resp, errors.New(msg)
+			return errors.New(msg)
 		}
 
 		if respBody != nil {
-			err = json.Unmarshal(b, respBody)
+			err := codec.Unmarshal(b, respBody)
 			if err != nil {
-				return rc, resp, fmt.Errorf("failed to decode response body: %v", err)
+				return fmt.Errorf("failed to decode response body: %v", err)
 			}
 		}
 	}
@@ -174,15 +259,14 @@ func (c *Connection) send(method, path string, header http.Header, payload, resp
 	if s >= http.StatusBadRequest {
 		var bodyStr string
 		if len(b) > 0 {
-			bodyStr = string(b)
+			bodyStr = bodyString(resp.Header, b)
 		}
-		return rc, resp, HTTPError{
+		return HTTPError{
 			error:      fmt.Errorf("http status error:%s, body:%s", resp.Status, bodyStr),
 			StatusCode: s,
 		}
 	}
-
-	return rc, resp, nil
+	return nil
 }
 
 type pathConfig struct {
@@ -0,0 +1,291 @@
+package arangogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/hnakamur/arangogo/vpack"
+)
+
+type createCursorConfig struct {
+	Query     string                 `json:"query"`
+	BindVars  map[string]interface{} `json:"bindVars,omitempty"`
+	Count     bool                   `json:"count,omitempty"`
+	BatchSize int                    `json:"batchSize,omitempty"`
+}
+
+type cursorBody struct {
+	ID      string     `json:"id"`
+	Result  []rawValue `json:"result"`
+	HasMore bool       `json:"hasMore"`
+	Count   int        `json:"count"`
+	Cached  bool       `json:"cached"`
+	Extra   struct {
+		Stats ListAllDocumentsResultStats `json:"stats"`
+	} `json:"extra"`
+}
+
+func (c *Connection) createCursor(dbName string, config createCursorConfig) (body cursorBody, rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/cursor",
+	})
+
+	rc, _, err = c.send(http.MethodPost, path, nil, config, &body)
+	if err != nil {
+		return body, rc, fmt.Errorf("failed to create cursor: %v", err)
+	}
+	return body, rc, nil
+}
+
+func (c *Connection) readNextBatch(dbName, cursorID string) (body cursorBody, rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/cursor/%s",
+		pathParams: []interface{}{cursorID},
+	})
+
+	rc, _, err = c.send(http.MethodPut, path, nil, nil, &body)
+	if err != nil {
+		return body, rc, fmt.Errorf("failed to read next batch from cursor: %v", err)
+	}
+	return body, rc, nil
+}
+
+func (c *Connection) deleteCursor(dbName, cursorID string) (rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/cursor/%s",
+		pathParams: []interface{}{cursorID},
+	})
+
+	rc, _, err = c.send(http.MethodDelete, path, nil, nil, nil)
+	if err != nil {
+		return rc, fmt.Errorf("failed to delete cursor: %v", err)
+	}
+	return rc, nil
+}
+
+func (c *Connection) readAllResults(dbName string, body cursorBody) (results []rawValue, rc int, err error) {
+	results = body.Result
+	for body.HasMore {
+		id := body.ID
+		body, rc, err = c.readNextBatch(dbName, id)
+		if err != nil {
+			// The cursor is kept on the server until it times out unless
+			// it is deleted.
+			c.deleteCursor(dbName, id)
+			return nil, rc, err
+		}
+		results = append(results, body.Result...)
+	}
+	return results, rc, nil
+}
+
+func (c *Connection) query(dbName string, config createCursorConfig) (results []rawValue, rc int, err error) {
+	body, rc, err := c.createCursor(dbName, config)
+	if err != nil {
+		return nil, rc, err
+	}
+	// rc is the status of the cursor creation unless reading the next
+	// batches fails.
+	results, nextRC, err := c.readAllResults(dbName, body)
+	if err != nil {
+		return nil, nextRC, err
+	}
+	return results, rc, nil
+}
+
+func unmarshalRawMessages(msgs []json.RawMessage, v interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, msg := range msgs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(msg)
+	}
+	buf.WriteByte(']')
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+// rawValue is a result kept in the format of the response so that it is
+// decoded once into the type of the caller.
+type rawValue struct {
+	data  []byte
+	vpack bool
+}
+
+func (r *rawValue) UnmarshalJSON(b []byte) error {
+	r.data = append([]byte(nil), b...)
+	r.vpack = false
+	return nil
+}
+
+func (r *rawValue) UnmarshalVPack(b []byte) error {
+	r.data = append([]byte(nil), b...)
+	r.vpack = true
+	return nil
+}
+
+func (r rawValue) unmarshal(v interface{}) error {
+	if len(r.data) == 0 {
+		return nil
+	}
+	if r.vpack {
+		return vpack.Unmarshal(r.data, v)
+	}
+	return json.Unmarshal(r.data, v)
+}
+
+// unmarshalRawValues decodes values into v which must be a pointer to a
+// slice or an empty interface.
+func unmarshalRawValues(values []rawValue, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("non-pointer %T", v)
+	}
+	dst := rv.Elem()
+	t := dst.Type()
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		t = reflect.TypeOf([]interface{}(nil))
+	} else if t.Kind() != reflect.Slice {
+		return fmt.Errorf("not a pointer to a slice %T", v)
+	}
+	s := reflect.MakeSlice(t, len(values), len(values))
+	for i := range values {
+		err := values[i].unmarshal(s.Index(i).Addr().Interface())
+		if err != nil {
+			return err
+		}
+	}
+	dst.Set(s)
+	return nil
+}
+
+func nullIfEmpty(msg json.RawMessage) json.RawMessage {
+	if len(msg) == 0 {
+		return json.RawMessage("null")
+	}
+	return msg
+}
+
+// Cursor iterates the results of a query. Close must be called when the
+// iteration is stopped before all results are read.
+type Cursor struct {
+	conn   *Connection
+	dbName string
+	body   cursorBody
+	pos    int
+	err    error
+}
+
+func newCursor(conn *Connection, dbName string, body cursorBody) *Cursor {
+	return &Cursor{
+		conn:   conn,
+		dbName: dbName,
+		body:   body,
+	}
+}
+
+// NewStaticCursor returns a cursor which iterates the elements of docs, which
+// must be a slice, without a server. It is for mocks of the methods which
+// return cursors.
+func NewStaticCursor(docs interface{}) (*Cursor, error) {
+	b, err := json.Marshal(docs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cursor results: %v", err)
+	}
+	var body cursorBody
+	err = json.Unmarshal(b, &body.Result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cursor results: %v", err)
+	}
+	body.Count = len(body.Result)
+	return newCursor(nil, "", body), nil
+}
+
+// Next decodes the next result into docPtr. It returns false when there are
+// no more results or an error occurred. Use Err to distinguish the two cases.
+func (c *Cursor) Next(docPtr interface{}) bool {
+	if c.err != nil {
+		return false
+	}
+	for c.pos >= len(c.body.Result) {
+		if !c.body.HasMore {
+			return false
+		}
+		body, _, err := c.conn.readNextBatch(c.dbName, c.body.ID)
+		if err != nil {
+			c.err = err
+			return false
+		}
+		c.body = body
+		c.pos = 0
+	}
+	result := c.body.Result[c.pos]
+	c.pos++
+	if docPtr != nil {
+		err := result.unmarshal(docPtr)
+		if err != nil {
+			c.err = fmt.Errorf("failed to decode cursor result: %v", err)
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns the error occurred in Next.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Count returns the total number of results. It is available only when the
+// count was requested.
+func (c *Cursor) Count() int {
+	return c.body.Count
+}
+
+func (c *Cursor) Stats() ListAllDocumentsResultStats {
+	return c.body.Extra.Stats
+}
+
+// Close deletes the cursor on the server if there are unread batches.
+func (c *Cursor) Close() error {
+	if !c.body.HasMore || c.body.ID == "" {
+		return nil
+	}
+	_, err := c.conn.deleteCursor(c.dbName, c.body.ID)
+	c.body.HasMore = false
+	return err
+}
+
+// All decodes all the remaining results into docsPtr which must be a pointer
+// to a slice.
+func (c *Cursor) All(docsPtr interface{}) error {
+	results := c.body.Result[c.pos:]
+	if c.body.HasMore {
+		var err error
+		results, _, err = c.conn.readAllResults(c.dbName, cursorBody{
+			ID:      c.body.ID,
+			Result:  append([]rawValue(nil), results...),
+			HasMore: true,
+		})
+		if err != nil {
+			c.err = err
+			return err
+		}
+	}
+	c.body.Result = nil
+	c.body.HasMore = false
+	c.pos = 0
+	err := unmarshalRawValues(results, docsPtr)
+	if err != nil {
+		c.err = fmt.Errorf("failed to decode cursor results: %v", err)
+		return c.err
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ func (c *Connection) CreateDatabase(config CreateDatabaseConfig) error {
 
 func (c *Connection) DropDatabase(name string) error {
 	_, _, err := c.send(http.MethodDelete, "/_api/database/"+name, nil, nil, nil)
+	c.invalidateDatabase(name)
 	if err != nil {
 		return fmt.Errorf("failed to delete database: %v", err)
 	}
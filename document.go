@@ -1,6 +1,7 @@
 package arangogo
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -36,6 +37,17 @@ func (c *Connection) ReadDocument(dbName, documentHandle string, config *ReadDoc
 		pathParams: []interface{}{documentHandle},
 	})
 
+	if c.useCache(config.header()) {
+		_, rc, err = c.readThroughCache(documentCacheKey(dbName, documentHandle), path,
+			func(b json.RawMessage) (json.RawMessage, error) {
+				return b, nil
+			}, documentPtr)
+		if err != nil {
+			return rc, fmt.Errorf("failed to read document: %v", err)
+		}
+		return rc, nil
+	}
+
 	rc, _, err = c.send(http.MethodGet, path, config.header(), nil, &documentPtr)
 	if err != nil {
 		return rc, fmt.Errorf("failed to read document: %v", err)
@@ -89,6 +101,7 @@ const (
 type ListAllDocumentsConfig struct {
 	Type       string `json:"type,omitempty"`
 	Collection string `json:"collection"`
+	BatchSize  int    `json:"batchSize,omitempty"`
 }
 
 type ListAllDocumentsResultStats struct {
@@ -100,26 +113,19 @@ type ListAllDocumentsResultStats struct {
 	ExecutionTime  float64 `json:"executionTime"`
 }
 
-type ListAllDocumentsResult struct {
-	Result  []string `json:"result"`
-	HasMore bool     `json:"hasMore"`
-	Cached  bool     `json:"cached"`
-	Extra   struct {
-		Stats ListAllDocumentsResultStats `json:"stats"`
-	} `json:"extra"`
-}
-
-func (c *Connection) ListAllDocuments(dbName string, config ListAllDocumentsConfig) (r ListAllDocumentsResult, rc int, err error) {
-	path := buildPath(pathConfig{
-		dbName:     dbName,
-		pathFormat: "/_api/simple/all-keys",
-	})
-
-	rc, _, err = c.send(http.MethodPut, path, nil, config, &r)
+// ListAllDocuments returns a cursor which iterates the ids, keys or paths of
+// all documents in the collection as strings. The cursor fetches the next
+// batch from the server as needed. Close the cursor when you stop the
+// iteration early.
+func (c *Connection) ListAllDocuments(dbName string, config ListAllDocumentsConfig) (cur *Cursor, rc int, err error) {
+	if err := c.requireSync("ListAllDocuments"); err != nil {
+		return nil, 0, err
+	}
+	cur, rc, err = c.simpleQueryCursor(dbName, "all-keys", config)
 	if err != nil {
-		err = fmt.Errorf("failed to list all documents: %v", err)
+		return nil, rc, fmt.Errorf("failed to list all documents: %v", err)
 	}
-	return
+	return cur, rc, nil
 }
 
 type Document struct {
@@ -128,9 +134,22 @@ type Document struct {
 	Rev string `json:"_rev"`
 }
 
+const (
+	OverwriteModeIgnore   = "ignore"
+	OverwriteModeUpdate   = "update"
+	OverwriteModeReplace  = "replace"
+	OverwriteModeConflict = "conflict"
+)
+
 type CreateDocumentConfig struct {
-	WaitForSync *bool
-	ReturnNew   *bool
+	WaitForSync   *bool
+	ReturnNew     *bool
+	ReturnOld     *bool
+	Silent        *bool
+	Overwrite     *bool
+	OverwriteMode string
+	KeepNull      *bool
+	MergeObjects  *bool
 }
 
 func (c *CreateDocumentConfig) queryParams() url.Values {
@@ -139,7 +158,8 @@ func (c *CreateDocumentConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil || c.ReturnNew != nil {
+	if c.WaitForSync != nil || c.ReturnNew != nil || c.ReturnOld != nil || c.Silent != nil ||
+		c.Overwrite != nil || c.OverwriteMode != "" || c.KeepNull != nil || c.MergeObjects != nil {
 		params = make(url.Values)
 	}
 	if c.WaitForSync != nil {
@@ -148,10 +168,47 @@ func (c *CreateDocumentConfig) queryParams() url.Values {
 	if c.ReturnNew != nil {
 		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
+	if c.Silent != nil {
+		params.Set("silent", strconv.FormatBool(*c.Silent))
+	}
+	if c.Overwrite != nil {
+		params.Set("overwrite", strconv.FormatBool(*c.Overwrite))
+	}
+	if c.OverwriteMode != "" {
+		params.Set("overwriteMode", c.OverwriteMode)
+	}
+	if c.KeepNull != nil {
+		params.Set("keepNull", strconv.FormatBool(*c.KeepNull))
+	}
+	if c.MergeObjects != nil {
+		params.Set("mergeObjects", strconv.FormatBool(*c.MergeObjects))
+	}
 	return params
 }
 
-func (c *Connection) CreateDocument(dbName, collName string, data interface{}, config *CreateDocumentConfig, docPtr interface{}) (doc Document, rc int, err error) {
+func (c *CreateDocumentConfig) overwrites() bool {
+	return c != nil && ((c.Overwrite != nil && *c.Overwrite) || c.OverwriteMode != "")
+}
+
+func (c *Connection) checkOverwriteOptions(overwrite *bool, overwriteMode string) error {
+	if overwriteMode != "" {
+		return c.requireVersion("overwriteMode", 30700)
+	}
+	if overwrite != nil {
+		return c.requireVersion("overwrite", 30400)
+	}
+	return nil
+}
+
+func (c *Connection) CreateDocument(dbName, collName string, data interface{}, config *CreateDocumentConfig, oldDocPtr, newDocPtr interface{}) (doc Document, rc int, err error) {
+	if config != nil {
+		if err := c.checkOverwriteOptions(config.Overwrite, config.OverwriteMode); err != nil {
+			return doc, 0, err
+		}
+	}
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/document/%s",
@@ -163,12 +220,23 @@ func (c *Connection) CreateDocument(dbName, collName string, data interface{}, c
 		ID  string      `json:"_id"`
 		Key string      `json:"_key"`
 		Rev string      `json:"_rev"`
+		Old interface{} `json:"old"`
 		New interface{} `json:"new"`
 	}
-	if docPtr != nil {
-		body.New = docPtr
+	if oldDocPtr != nil {
+		body.Old = oldDocPtr
+	}
+	if newDocPtr != nil {
+		body.New = newDocPtr
 	}
 	rc, _, err = c.send(http.MethodPost, path, nil, data, &body)
+	if config.overwrites() {
+		if body.ID != "" {
+			c.invalidateDocument(dbName, body.ID)
+		} else {
+			c.invalidateCollection(dbName, collName)
+		}
+	}
 	if err != nil {
 		return doc, rc, fmt.Errorf("failed to create document: %v", err)
 	}
@@ -181,7 +249,14 @@ func (c *Connection) CreateDocument(dbName, collName string, data interface{}, c
 }
 
 type CreateDocumentsConfig struct {
-	WaitForSync *bool
+	WaitForSync   *bool
+	ReturnNew     *bool
+	ReturnOld     *bool
+	Silent        *bool
+	Overwrite     *bool
+	OverwriteMode string
+	KeepNull      *bool
+	MergeObjects  *bool
 }
 
 func (c *CreateDocumentsConfig) queryParams() url.Values {
@@ -190,14 +265,91 @@ func (c *CreateDocumentsConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnNew != nil || c.ReturnOld != nil || c.Silent != nil ||
+		c.Overwrite != nil || c.OverwriteMode != "" || c.KeepNull != nil || c.MergeObjects != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
+	if c.Silent != nil {
+		params.Set("silent", strconv.FormatBool(*c.Silent))
+	}
+	if c.Overwrite != nil {
+		params.Set("overwrite", strconv.FormatBool(*c.Overwrite))
+	}
+	if c.OverwriteMode != "" {
+		params.Set("overwriteMode", c.OverwriteMode)
+	}
+	if c.KeepNull != nil {
+		params.Set("keepNull", strconv.FormatBool(*c.KeepNull))
+	}
+	if c.MergeObjects != nil {
+		params.Set("mergeObjects", strconv.FormatBool(*c.MergeObjects))
+	}
 	return params
 }
 
-func (c *Connection) CreateDocuments(dbName, collName string, data interface{}, config *CreateDocumentsConfig) (docs []Document, rc int, err error) {
+func (c *CreateDocumentsConfig) overwrites() bool {
+	return c != nil && ((c.Overwrite != nil && *c.Overwrite) || c.OverwriteMode != "")
+}
+
+type DocumentResult struct {
+	ID           string `json:"_id"`
+	Key          string `json:"_key"`
+	Rev          string `json:"_rev"`
+	Error        bool   `json:"error"`
+	ErrorNum     int    `json:"errorNum"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type CreateDocumentsResult struct {
+	// Documents is the result for each document in the order of data.
+	// It is nil when Silent is set.
+	Documents []DocumentResult
+	// ErrorCodes is the number of failed documents for each errorNum.
+	ErrorCodes map[int]int
+	ErrorCount int
+}
+
+func parseErrorCodes(header http.Header) (codes map[int]int, count int, err error) {
+	v := header.Get("X-Arango-Error-Codes")
+	if v == "" {
+		return nil, 0, nil
+	}
+	var raw map[string]int
+	err = json.Unmarshal([]byte(v), &raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	codes = make(map[int]int, len(raw))
+	for k, n := range raw {
+		errorNum, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, 0, err
+		}
+		codes[errorNum] = n
+		count += n
+	}
+	return codes, count, nil
+}
+
+// CreateDocuments creates the documents in data. Documents which failed to be
+// created are reported in the result instead of err. oldDocsPtr and newDocsPtr
+// must be pointers to slices or nil. They receive the old and new documents
+// in the order of data when ReturnOld and ReturnNew are set.
+func (c *Connection) CreateDocuments(dbName, collName string, data interface{}, config *CreateDocumentsConfig, oldDocsPtr, newDocsPtr interface{}) (r CreateDocumentsResult, rc int, err error) {
+	if config != nil {
+		if err := c.checkOverwriteOptions(config.Overwrite, config.OverwriteMode); err != nil {
+			return r, 0, err
+		}
+	}
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/document/%s",
@@ -205,11 +357,51 @@ func (c *Connection) CreateDocuments(dbName, collName string, data interface{},
 		queryParams: config.queryParams(),
 	})
 
-	rc, _, err = c.send(http.MethodPost, path, nil, data, &docs)
+	var body []struct {
+		DocumentResult
+		Old json.RawMessage `json:"old"`
+		New json.RawMessage `json:"new"`
+	}
+	var respBody interface{}
+	if config == nil || config.Silent == nil || !*config.Silent {
+		respBody = &body
+	}
+	rc, resp, err := c.send(http.MethodPost, path, nil, data, respBody)
+	if config.overwrites() {
+		c.invalidateCollection(dbName, collName)
+	}
 	if err != nil {
-		return nil, rc, fmt.Errorf("failed to create documents: %v", err)
+		return r, rc, fmt.Errorf("failed to create documents: %v", err)
+	}
+	r.ErrorCodes, r.ErrorCount, err = parseErrorCodes(resp.Header)
+	if err != nil {
+		return r, rc, fmt.Errorf("failed to parse error codes: %v", err)
+	}
+	if respBody == nil {
+		return r, rc, nil
 	}
-	return docs, rc, nil
+
+	r.Documents = make([]DocumentResult, len(body))
+	olds := make([]json.RawMessage, len(body))
+	news := make([]json.RawMessage, len(body))
+	for i, item := range body {
+		r.Documents[i] = item.DocumentResult
+		olds[i] = nullIfEmpty(item.Old)
+		news[i] = nullIfEmpty(item.New)
+	}
+	if oldDocsPtr != nil {
+		err = unmarshalRawMessages(olds, oldDocsPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to decode old documents: %v", err)
+		}
+	}
+	if newDocsPtr != nil {
+		err = unmarshalRawMessages(news, newDocsPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to decode new documents: %v", err)
+		}
+	}
+	return r, rc, nil
 }
 
 type ReplaceDocumentConfig struct {
@@ -286,6 +478,7 @@ func (c *Connection) ReplaceDocument(dbName, docHandle string, data interface{},
 		body.New = newDocPtr
 	}
 	rc, _, err = c.send(http.MethodPut, path, config.header(), data, &body)
+	c.invalidateDocument(dbName, docHandle)
 	if err != nil {
 		return r, rc, fmt.Errorf("failed to replace document: %v", err)
 	}
@@ -381,6 +574,7 @@ func (c *Connection) UpdateDocument(dbName, docHandle string, data interface{},
 		body.New = newDocPtr
 	}
 	rc, _, err = c.send(http.MethodPatch, path, config.header(), data, &body)
+	c.invalidateDocument(dbName, docHandle)
 	if err != nil {
 		return r, rc, fmt.Errorf("failed to update document: %v", err)
 	}
@@ -449,6 +643,7 @@ func (c *Connection) RemoveDocument(dbName, collName, key string, config *Remove
 		body.Old = docPtr
 	}
 	rc, _, err = c.send(http.MethodDelete, path, config.header(), nil, &body)
+	c.invalidateDocument(dbName, collName+"/"+key)
 	if err != nil {
 		return doc, rc, fmt.Errorf("failed to remove document: %v", err)
 	}
@@ -15,6 +15,7 @@ type CreateEdgeResult struct {
 
 type CreateEdgeConfig struct {
 	WaitForSync *bool
+	ReturnNew   *bool
 }
 
 func (c *CreateEdgeConfig) urlValues() url.Values {
@@ -23,14 +24,19 @@ func (c *CreateEdgeConfig) urlValues() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnNew != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
 	return params
 }
 
-func (c *Connection) CreateEdge(dbName, graphName, collName string, data interface{}, config *CreateEdgeConfig) (r CreateEdgeResult, rc int, err error) {
+func (c *Connection) CreateEdge(dbName, graphName, collName string, data interface{}, config *CreateEdgeConfig, newEdgePtr interface{}) (r CreateEdgeResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/edge/%s",
@@ -40,6 +46,10 @@ func (c *Connection) CreateEdge(dbName, graphName, collName string, data interfa
 
 	var body struct {
 		Edge CreateEdgeResult `json:"edge"`
+		New  interface{}      `json:"new"`
+	}
+	if newEdgePtr != nil {
+		body.New = newEdgePtr
 	}
 	rc, _, err = c.send(http.MethodPost, path, nil, data, &body)
 	if err != nil {
@@ -49,7 +59,8 @@ func (c *Connection) CreateEdge(dbName, graphName, collName string, data interfa
 }
 
 type GetEdgeConfig struct {
-	IfMatch string
+	IfNoneMatch string
+	IfMatch     string
 }
 
 func (c *GetEdgeConfig) header() http.Header {
@@ -57,16 +68,28 @@ func (c *GetEdgeConfig) header() http.Header {
 		return nil
 	}
 	var header http.Header
-	if c.IfMatch != "" {
+	if c.IfNoneMatch != "" || c.IfMatch != "" {
 		header = make(http.Header)
+	}
+	if c.IfNoneMatch != "" {
+		header.Set("if-none-match", c.IfNoneMatch)
+	}
+	if c.IfMatch != "" {
 		header.Set("if-match", c.IfMatch)
-		return header
 	}
+	return header
+}
 
-	return nil
+type GetEdgeResult struct {
+	Rev string
+	// NotModified is true when the edge revision matched IfNoneMatch.
+	// The edge is not decoded in this case.
+	NotModified bool
+	// PreconditionFailed is true when the edge revision did not match IfMatch.
+	PreconditionFailed bool
 }
 
-func (c *Connection) GetEdge(dbName, graphName, collName, edgeKey string, config *GetEdgeConfig, edgePtr interface{}) (rc int, err error) {
+func (c *Connection) GetEdge(dbName, graphName, collName, edgeKey string, config *GetEdgeConfig, edgePtr interface{}) (r GetEdgeResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:     dbName,
 		pathFormat: "/_api/gharial/%s/edge/%s/%s",
@@ -79,11 +102,22 @@ func (c *Connection) GetEdge(dbName, graphName, collName, edgeKey string, config
 	if edgePtr != nil {
 		body.Edge = edgePtr
 	}
-	rc, _, err = c.send(http.MethodGet, path, config.header(), nil, &body)
+	rc, resp, err := c.send(http.MethodGet, path, config.header(), nil, &body)
+	if resp != nil {
+		r.Rev = revFromETag(resp.Header.Get("ETag"))
+	}
+	switch rc {
+	case http.StatusNotModified:
+		r.NotModified = true
+		return r, rc, nil
+	case http.StatusPreconditionFailed:
+		r.PreconditionFailed = true
+		return r, rc, nil
+	}
 	if err != nil {
-		return rc, fmt.Errorf("failed to get edge: %v", err)
+		return r, rc, fmt.Errorf("failed to get edge: %v", err)
 	}
-	return rc, nil
+	return r, rc, nil
 }
 
 type ModifyEdgeResult struct {
@@ -97,6 +131,8 @@ type ModifyEdgeConfig struct {
 	// NOTE: IfMwatch is not really supported?
 	WaitForSync *bool
 	KeepNull    *bool
+	ReturnOld   *bool
+	ReturnNew   *bool
 }
 
 func (c *ModifyEdgeConfig) queryParams() url.Values {
@@ -105,7 +141,7 @@ func (c *ModifyEdgeConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil || c.KeepNull != nil {
+	if c.WaitForSync != nil || c.KeepNull != nil || c.ReturnOld != nil || c.ReturnNew != nil {
 		params = make(url.Values)
 	}
 	if c.WaitForSync != nil {
@@ -114,10 +150,16 @@ func (c *ModifyEdgeConfig) queryParams() url.Values {
 	if c.KeepNull != nil {
 		params.Set("keepNull", strconv.FormatBool(*c.KeepNull))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
 	return params
 }
 
-func (c *Connection) ModifyEdge(dbName, graphName, collName, edgeKey string, data interface{}, config *ModifyEdgeConfig) (edge ModifyEdgeResult, rc int, err error) {
+func (c *Connection) ModifyEdge(dbName, graphName, collName, edgeKey string, data interface{}, config *ModifyEdgeConfig, oldEdgePtr, newEdgePtr interface{}) (edge ModifyEdgeResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/edge/%s/%s",
@@ -127,8 +169,17 @@ func (c *Connection) ModifyEdge(dbName, graphName, collName, edgeKey string, dat
 
 	var body struct {
 		Edge ModifyEdgeResult `json:"edge"`
+		Old  interface{}      `json:"old"`
+		New  interface{}      `json:"new"`
+	}
+	if oldEdgePtr != nil {
+		body.Old = oldEdgePtr
+	}
+	if newEdgePtr != nil {
+		body.New = newEdgePtr
 	}
 	rc, _, err = c.send(http.MethodPatch, path, nil, data, &body)
+	c.invalidateDocument(dbName, collName+"/"+edgeKey)
 	if err != nil {
 		return body.Edge, rc, fmt.Errorf("failed to modify edge: %v", err)
 	}
@@ -144,6 +195,8 @@ type ReplaceEdgeResult struct {
 
 type ReplaceEdgeConfig struct {
 	WaitForSync *bool
+	ReturnOld   *bool
+	ReturnNew   *bool
 	IfMatch     string
 }
 
@@ -167,14 +220,22 @@ func (c *ReplaceEdgeConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnOld != nil || c.ReturnNew != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
 	return params
 }
 
-func (c *Connection) ReplaceEdge(dbName, graphName, collName, edgeKey string, data interface{}, config *ReplaceEdgeConfig) (edge ReplaceEdgeResult, rc int, err error) {
+func (c *Connection) ReplaceEdge(dbName, graphName, collName, edgeKey string, data interface{}, config *ReplaceEdgeConfig, oldEdgePtr, newEdgePtr interface{}) (edge ReplaceEdgeResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/edge/%s/%s",
@@ -184,8 +245,17 @@ func (c *Connection) ReplaceEdge(dbName, graphName, collName, edgeKey string, da
 
 	var body struct {
 		Edge ReplaceEdgeResult `json:"edge"`
+		Old  interface{}       `json:"old"`
+		New  interface{}       `json:"new"`
+	}
+	if oldEdgePtr != nil {
+		body.Old = oldEdgePtr
+	}
+	if newEdgePtr != nil {
+		body.New = newEdgePtr
 	}
 	rc, _, err = c.send(http.MethodPut, path, config.header(), data, &body)
+	c.invalidateDocument(dbName, collName+"/"+edgeKey)
 	if err != nil {
 		return body.Edge, rc, fmt.Errorf("failed to replace edge: %v", err)
 	}
@@ -194,6 +264,7 @@ func (c *Connection) ReplaceEdge(dbName, graphName, collName, edgeKey string, da
 
 type RemoveEdgeConfig struct {
 	WaitForSync *bool
+	ReturnOld   *bool
 	IfMatch     string
 }
 
@@ -217,14 +288,19 @@ func (c *RemoveEdgeConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnOld != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
 	return params
 }
 
-func (c *Connection) RemoveEdge(dbName, graphName, collName, edgeKey string, config *RemoveEdgeConfig) (removed bool, rc int, err error) {
+func (c *Connection) RemoveEdge(dbName, graphName, collName, edgeKey string, config *RemoveEdgeConfig, oldEdgePtr interface{}) (removed bool, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/edge/%s/%s",
@@ -233,11 +309,49 @@ func (c *Connection) RemoveEdge(dbName, graphName, collName, edgeKey string, con
 	})
 
 	var body struct {
-		Removed bool `json:"removed"`
+		Removed bool        `json:"removed"`
+		Old     interface{} `json:"old"`
+	}
+	if oldEdgePtr != nil {
+		body.Old = oldEdgePtr
 	}
 	rc, _, err = c.send(http.MethodDelete, path, config.header(), nil, &body)
+	c.invalidateDocument(dbName, collName+"/"+edgeKey)
 	if err != nil {
 		return body.Removed, rc, fmt.Errorf("failed to remove edge: %v", err)
 	}
 	return body.Removed, rc, nil
 }
+
+const (
+	EdgesDirectionAny = ""
+	EdgesDirectionIn  = "in"
+	EdgesDirectionOut = "out"
+)
+
+func (c *Connection) Edges(dbName, collName, vertexHandle, direction string, edgesPtr interface{}) (stats ListAllDocumentsResultStats, rc int, err error) {
+	params := make(url.Values)
+	params.Set("vertex", vertexHandle)
+	if direction != EdgesDirectionAny {
+		params.Set("direction", direction)
+	}
+	path := buildPath(pathConfig{
+		dbName:      dbName,
+		pathFormat:  "/_api/edges/%s",
+		pathParams:  []interface{}{collName},
+		queryParams: params,
+	})
+
+	var body struct {
+		Edges interface{}                 `json:"edges"`
+		Stats ListAllDocumentsResultStats `json:"stats"`
+	}
+	if edgesPtr != nil {
+		body.Edges = edgesPtr
+	}
+	rc, _, err = c.send(http.MethodGet, path, nil, nil, &body)
+	if err != nil {
+		return body.Stats, rc, fmt.Errorf("failed to get edges: %v", err)
+	}
+	return body.Stats, rc, nil
+}
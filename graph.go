@@ -34,18 +34,49 @@ type EdgeDefinition struct {
 
 type CreateGraphConfig struct {
 	Name              string           `json:"name"`
-	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions",omitempty`
-	OrphanCollections []string         `json:"orphanCollections",omitempty`
+	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	OrphanCollections []string         `json:"orphanCollections,omitempty"`
 }
 
 type Graph struct {
 	Name              string           `json:"name"`
-	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions",omitempty`
-	OrphanCollections []string         `json:"orphanCollections",omitempty`
+	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	OrphanCollections []string         `json:"orphanCollections,omitempty"`
 	ID                string           `json:"_id"`
 	Rev               string           `json:"_rev"`
 }
 
+// collections returns the edge and vertex collections of the graph.
+func (g Graph) collections() []string {
+	var names []string
+	for _, d := range g.EdgeDefinitions {
+		names = append(names, d.Collection)
+		names = append(append(names, d.From...), d.To...)
+	}
+	return append(names, g.OrphanCollections...)
+}
+
+// edgeCollectionsOf returns the edge collections which can hold edges from
+// or to the vertices of collName.
+func (g Graph) edgeCollectionsOf(collName string) []string {
+	var names []string
+	for _, d := range g.EdgeDefinitions {
+		if contains(d.From, collName) || contains(d.To, collName) {
+			names = append(names, d.Collection)
+		}
+	}
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Connection) CreateGraph(dbName string, data interface{}) (g Graph, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:     dbName,
@@ -112,10 +143,20 @@ func (c *Connection) DropGraph(dbName, graphName string, config *DropGraphConfig
 		queryParams: config.queryParams(),
 	})
 
+	dropCollections := config != nil && config.DropCollections != nil && *config.DropCollections
+	var g Graph
+	var graphRead bool
+	if dropCollections {
+		g, graphRead = c.readGraphForCache(dbName, graphName)
+	}
+
 	var body struct {
 		Removed bool `json:"removed"`
 	}
 	rc, _, err = c.send(http.MethodDelete, path, nil, nil, &body)
+	if dropCollections {
+		c.invalidateGraphCollections(dbName, g.collections(), graphRead)
+	}
 	if err != nil {
 		return body.Removed, rc, fmt.Errorf("failed to drop edge: %v", err)
 	}
@@ -141,8 +182,8 @@ func (c *Connection) ListVertexCollections(dbName, graphName string) (collection
 
 type AddVertexCollectionResult struct {
 	Name              string           `json:"name"`
-	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions",omitempty`
-	OrphanCollections []string         `json:"orphanCollections",omitempty`
+	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	OrphanCollections []string         `json:"orphanCollections,omitempty"`
 	ID                string           `json:"_id"`
 	Rev               string           `json:"_rev"`
 }
@@ -189,8 +230,8 @@ func (c *Connection) AddVertexCollection(dbName, graphName, collectionName strin
 
 type RemoveVertexCollectionResult struct {
 	Name              string           `json:"name"`
-	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions",omitempty`
-	OrphanCollections []string         `json:"orphanCollections",omitempty`
+	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	OrphanCollections []string         `json:"orphanCollections,omitempty"`
 	ID                string           `json:"_id"`
 	Rev               string           `json:"_rev"`
 }
@@ -249,8 +290,8 @@ func (c *Connection) ListEdgeDefinitions(dbName, graphName string) (collections
 
 type AddEdgeDefinitionResult struct {
 	Name              string           `json:"name"`
-	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions",omitempty`
-	OrphanCollections []string         `json:"orphanCollections",omitempty`
+	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	OrphanCollections []string         `json:"orphanCollections,omitempty"`
 	ID                string           `json:"_id"`
 	Rev               string           `json:"_rev"`
 }
@@ -275,17 +316,28 @@ func (c *Connection) AddEdgeDefinition(dbName, graphName string, edgeDefinition
 
 type RemoveEdgeDefinitionResult struct {
 	Name              string           `json:"name"`
-	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions",omitempty`
-	OrphanCollections []string         `json:"orphanCollections",omitempty`
+	EdgeDefinitions   []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	OrphanCollections []string         `json:"orphanCollections,omitempty"`
 	ID                string           `json:"_id"`
 	Rev               string           `json:"_rev"`
 }
 
 func (c *Connection) RemoveEdgeDefinition(dbName, graphName, definitionName string) (r RemoveEdgeDefinitionResult, rc int, err error) {
+	return c.removeEdgeDefinition(dbName, graphName, definitionName, nil)
+}
+
+// removeEdgeDefinition removes the edge definition passing waitForSync for
+// EnsureGraph.
+func (c *Connection) removeEdgeDefinition(dbName, graphName, definitionName string, waitForSync *bool) (r RemoveEdgeDefinitionResult, rc int, err error) {
+	var params url.Values
+	if waitForSync != nil {
+		params = url.Values{"waitForSync": {strconv.FormatBool(*waitForSync)}}
+	}
 	path := buildPath(pathConfig{
-		dbName:     dbName,
-		pathFormat: "/_api/gharial/%s/edge/%s",
-		pathParams: []interface{}{graphName, definitionName},
+		dbName:      dbName,
+		pathFormat:  "/_api/gharial/%s/edge/%s",
+		pathParams:  []interface{}{graphName, definitionName},
+		queryParams: params,
 	})
 
 	var body struct {
@@ -298,3 +350,60 @@ func (c *Connection) RemoveEdgeDefinition(dbName, graphName, definitionName stri
 	}
 	return body.Graph, rc, nil
 }
+
+type ReplaceEdgeDefinitionConfig struct {
+	WaitForSync     *bool
+	DropCollections *bool
+}
+
+func (c *ReplaceEdgeDefinitionConfig) queryParams() url.Values {
+	if c == nil {
+		return nil
+	}
+
+	var params url.Values
+	if c.WaitForSync != nil || c.DropCollections != nil {
+		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
+		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
+	}
+	if c.DropCollections != nil {
+		params.Set("dropCollections", strconv.FormatBool(*c.DropCollections))
+	}
+	return params
+}
+
+func (c *Connection) ReplaceEdgeDefinition(dbName, graphName, definitionName string, edgeDefinition EdgeDefinition, config *ReplaceEdgeDefinitionConfig) (g Graph, rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:      dbName,
+		pathFormat:  "/_api/gharial/%s/edge/%s",
+		pathParams:  []interface{}{graphName, definitionName},
+		queryParams: config.queryParams(),
+	})
+
+	dropCollections := config != nil && config.DropCollections != nil && *config.DropCollections
+	var old Graph
+	var graphRead bool
+	if dropCollections {
+		old, graphRead = c.readGraphForCache(dbName, graphName)
+	}
+
+	var body struct {
+		Graph Graph `json:"graph"`
+	}
+	rc, _, err = c.send(http.MethodPut, path, nil, edgeDefinition, &body)
+	if dropCollections {
+		var collNames []string
+		for _, d := range old.EdgeDefinitions {
+			if d.Collection == definitionName {
+				collNames = append(append(collNames, d.From...), d.To...)
+			}
+		}
+		c.invalidateGraphCollections(dbName, collNames, graphRead)
+	}
+	if err != nil {
+		return body.Graph, rc, fmt.Errorf("failed to replace edge definition: %v", err)
+	}
+	return body.Graph, rc, nil
+}
@@ -0,0 +1,207 @@
+package arangogo
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+const (
+	GraphChangeCreateGraph            = "createGraph"
+	GraphChangeAddEdgeDefinition      = "addEdgeDefinition"
+	GraphChangeReplaceEdgeDefinition  = "replaceEdgeDefinition"
+	GraphChangeRemoveEdgeDefinition   = "removeEdgeDefinition"
+	GraphChangeAddVertexCollection    = "addVertexCollection"
+	GraphChangeRemoveVertexCollection = "removeVertexCollection"
+)
+
+type GraphChange struct {
+	Action string
+	// Collection is the edge collection name for edge definition changes and
+	// the vertex collection name for vertex collection changes. It is empty
+	// for GraphChangeCreateGraph.
+	Collection     string
+	EdgeDefinition *EdgeDefinition
+}
+
+type EnsureGraphConfig struct {
+	// DryRun reports the changes without applying them.
+	DryRun bool
+	// Prune removes edge definitions and orphan collections which are not
+	// in the definition.
+	Prune       bool
+	WaitForSync *bool
+}
+
+type EnsureGraphResult struct {
+	Graph   Graph
+	Changes []GraphChange
+}
+
+// EnsureGraph makes the graph match the definition by creating the graph or
+// applying the differences to the existing graph.
+func (c *Connection) EnsureGraph(dbName string, definition CreateGraphConfig, config *EnsureGraphConfig) (r EnsureGraphResult, rc int, err error) {
+	if err := c.requireSync("EnsureGraph"); err != nil {
+		return r, 0, err
+	}
+	if config == nil {
+		config = &EnsureGraphConfig{}
+	}
+
+	current, rc, err := c.GetGraph(dbName, definition.Name)
+	if err != nil {
+		if rc != http.StatusNotFound {
+			return r, rc, fmt.Errorf("failed to ensure graph: %v", err)
+		}
+		r.Changes = []GraphChange{{Action: GraphChangeCreateGraph}}
+		if config.DryRun {
+			return r, 0, nil
+		}
+		r.Graph, rc, err = c.CreateGraph(dbName, definition)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to ensure graph: %v", err)
+		}
+		return r, rc, nil
+	}
+
+	r.Graph = current
+	r.Changes = diffGraph(current, definition, config.Prune)
+	if config.DryRun || len(r.Changes) == 0 {
+		return r, rc, nil
+	}
+
+	for _, change := range r.Changes {
+		rc, err = c.applyGraphChange(dbName, definition.Name, change, config)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to ensure graph: %v", err)
+		}
+	}
+	r.Graph, rc, err = c.GetGraph(dbName, definition.Name)
+	if err != nil {
+		return r, rc, fmt.Errorf("failed to ensure graph: %v", err)
+	}
+	return r, rc, nil
+}
+
+func (c *Connection) applyGraphChange(dbName, graphName string, change GraphChange, config *EnsureGraphConfig) (rc int, err error) {
+	switch change.Action {
+	case GraphChangeAddEdgeDefinition:
+		_, rc, err = c.AddEdgeDefinition(dbName, graphName, change.EdgeDefinition)
+	case GraphChangeReplaceEdgeDefinition:
+		_, rc, err = c.ReplaceEdgeDefinition(dbName, graphName, change.Collection, *change.EdgeDefinition,
+			&ReplaceEdgeDefinitionConfig{WaitForSync: config.WaitForSync})
+	case GraphChangeRemoveEdgeDefinition:
+		_, rc, err = c.removeEdgeDefinition(dbName, graphName, change.Collection, config.WaitForSync)
+	case GraphChangeAddVertexCollection:
+		_, rc, err = c.AddVertexCollection(dbName, graphName, change.Collection,
+			&AddVertexCollectionConfig{WaitForSync: config.WaitForSync})
+	case GraphChangeRemoveVertexCollection:
+		_, rc, err = c.RemoveVertexCollection(dbName, graphName, change.Collection,
+			&RemoveVertexCollectionConfig{WaitForSync: config.WaitForSync})
+	default:
+		err = fmt.Errorf("unknown graph change: %s", change.Action)
+	}
+	return rc, err
+}
+
+func diffGraph(current Graph, definition CreateGraphConfig, prune bool) []GraphChange {
+	var changes []GraphChange
+
+	currentDefs := make(map[string]EdgeDefinition)
+	for _, d := range current.EdgeDefinitions {
+		currentDefs[d.Collection] = d
+	}
+	wantDefs := make(map[string]bool)
+	finalDefs := make([]EdgeDefinition, 0, len(definition.EdgeDefinitions))
+	for i := range definition.EdgeDefinitions {
+		d := &definition.EdgeDefinitions[i]
+		wantDefs[d.Collection] = true
+		finalDefs = append(finalDefs, *d)
+		cur, ok := currentDefs[d.Collection]
+		if !ok {
+			changes = append(changes, GraphChange{Action: GraphChangeAddEdgeDefinition, Collection: d.Collection, EdgeDefinition: d})
+		} else if !sameStringSet(cur.From, d.From) || !sameStringSet(cur.To, d.To) {
+			changes = append(changes, GraphChange{Action: GraphChangeReplaceEdgeDefinition, Collection: d.Collection, EdgeDefinition: d})
+		}
+	}
+	for i := range current.EdgeDefinitions {
+		d := &current.EdgeDefinitions[i]
+		if wantDefs[d.Collection] {
+			continue
+		}
+		if prune {
+			changes = append(changes, GraphChange{Action: GraphChangeRemoveEdgeDefinition, Collection: d.Collection, EdgeDefinition: d})
+		} else {
+			finalDefs = append(finalDefs, *d)
+		}
+	}
+
+	// Vertex collections which are no longer used by any edge definition
+	// become orphans when the edge definitions are changed.
+	usedBefore := vertexCollectionSet(current.EdgeDefinitions)
+	usedAfter := vertexCollectionSet(finalDefs)
+	orphans := make(map[string]bool)
+	for _, name := range current.OrphanCollections {
+		orphans[name] = true
+	}
+	for name := range usedBefore {
+		orphans[name] = true
+	}
+	for name := range usedAfter {
+		delete(orphans, name)
+	}
+
+	wantOrphans := make(map[string]bool)
+	for _, name := range definition.OrphanCollections {
+		wantOrphans[name] = true
+		if !orphans[name] && !usedAfter[name] {
+			changes = append(changes, GraphChange{Action: GraphChangeAddVertexCollection, Collection: name})
+		}
+	}
+	if prune {
+		names := make([]string, 0, len(orphans))
+		for name := range orphans {
+			if !wantOrphans[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			changes = append(changes, GraphChange{Action: GraphChangeRemoveVertexCollection, Collection: name})
+		}
+	}
+	return changes
+}
+
+func vertexCollectionSet(defs []EdgeDefinition) map[string]bool {
+	set := make(map[string]bool)
+	for _, d := range defs {
+		for _, name := range d.From {
+			set[name] = true
+		}
+		for _, name := range d.To {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+func sameStringSet(a, b []string) bool {
+	setA := make(map[string]bool)
+	for _, s := range a {
+		setA[s] = true
+	}
+	setB := make(map[string]bool)
+	for _, s := range b {
+		setB[s] = true
+	}
+	if len(setA) != len(setB) {
+		return false
+	}
+	for s := range setA {
+		if !setB[s] {
+			return false
+		}
+	}
+	return true
+}
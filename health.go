@@ -0,0 +1,120 @@
+package arangogo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Ping checks the server is reachable and the credentials are accepted.
+func (c *Connection) Ping(ctx context.Context) (rc int, err error) {
+	rc, _, err = c.WithContext(ctx).send(http.MethodGet, "/_api/version", nil, nil, nil)
+	if err != nil {
+		return rc, fmt.Errorf("failed to ping: %v", err)
+	}
+	return rc, nil
+}
+
+// CheckAvailability checks the server is ready to handle requests. It fails
+// with status 503 while the server is starting up or shutting down, or it is
+// a follower in an active failover setup.
+func (c *Connection) CheckAvailability(ctx context.Context) (rc int, err error) {
+	rc, _, err = c.WithContext(ctx).send(http.MethodGet, "/_admin/server/availability", nil, nil, nil)
+	if err != nil {
+		return rc, fmt.Errorf("failed to check availability: %v", err)
+	}
+	return rc, nil
+}
+
+type WaitForReadyConfig struct {
+	// Endpoints are the URLs of the servers to wait for. They default to the
+	// URL of the connection.
+	Endpoints []string
+	// InitialInterval is the wait after the first failed check. It defaults
+	// to 100ms.
+	InitialInterval time.Duration
+	// MaxInterval is the upper limit of the wait. It defaults to 5s.
+	MaxInterval time.Duration
+	// Multiplier is the factor the wait grows by after each failed check.
+	// It defaults to 2.
+	Multiplier float64
+}
+
+func (c *WaitForReadyConfig) initialInterval() time.Duration {
+	if c == nil || c.InitialInterval <= 0 {
+		return 100 * time.Millisecond
+	}
+	return c.InitialInterval
+}
+
+func (c *WaitForReadyConfig) maxInterval() time.Duration {
+	if c == nil || c.MaxInterval <= 0 {
+		return 5 * time.Second
+	}
+	return c.MaxInterval
+}
+
+func (c *WaitForReadyConfig) multiplier() float64 {
+	if c == nil || c.Multiplier < 1 {
+		return 2
+	}
+	return c.Multiplier
+}
+
+// WaitForReady waits until all the endpoints are reachable with the
+// credentials of the connection and available, retrying with exponential
+// backoff. An endpoint is not checked again once it is ready. It returns
+// ctx.Err() when ctx is done before all the endpoints are ready; the failed
+// checks are logged like other requests.
+func (c *Connection) WaitForReady(ctx context.Context, config *WaitForReadyConfig) error {
+	endpoints := []string{c.url}
+	if config != nil && len(config.Endpoints) > 0 {
+		endpoints = config.Endpoints
+	}
+	pending := make([]*Connection, len(endpoints))
+	for i, endpoint := range endpoints {
+		if _, err := url.Parse(endpoint); err != nil {
+			return fmt.Errorf("failed to wait for server: invalid endpoint %q: %v", endpoint, err)
+		}
+		ec := c.plain()
+		ec.url = endpoint
+		ec.handler = ec.buildHandler()
+		pending[i] = ec
+	}
+
+	interval := config.initialInterval()
+	for {
+		var notReady []*Connection
+		for _, ec := range pending {
+			if err := ec.checkReady(ctx); err != nil {
+				notReady = append(notReady, ec)
+			}
+		}
+		if len(notReady) == 0 {
+			return nil
+		}
+		pending = notReady
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		interval = time.Duration(float64(interval) * config.multiplier())
+		if maxInterval := config.maxInterval(); interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+func (c *Connection) checkReady(ctx context.Context) error {
+	if _, err := c.Ping(ctx); err != nil {
+		return err
+	}
+	_, err := c.CheckAvailability(ctx)
+	return err
+}
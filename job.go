@@ -0,0 +1,197 @@
+package arangogo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// AsyncModeFireAndForget executes the request asynchronously and
+	// discards the result.
+	AsyncModeFireAndForget = "true"
+	// AsyncModeStore executes the request asynchronously and stores the
+	// result on the server to be fetched with GetJobResult.
+	AsyncModeStore = "store"
+)
+
+const (
+	JobStatusDone    = "done"
+	JobStatusPending = "pending"
+)
+
+const (
+	DeleteJobsAll     = "all"
+	DeleteJobsExpired = "expired"
+)
+
+// AsyncJob receives the id of the job created for the last request sent by a
+// connection returned by Async. It can be shared by goroutines using the
+// connection.
+type AsyncJob struct {
+	mu sync.Mutex
+	id string
+}
+
+// ID returns the job id which is set when the request is accepted by the
+// server. It is empty for AsyncModeFireAndForget.
+func (j *AsyncJob) ID() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.id
+}
+
+func (j *AsyncJob) setID(id string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.id = id
+}
+
+// Async returns a client which sends requests asynchronously with the
+// x-arango-async header. The methods of the returned client return
+// immediately without results, and the id of the job created for the last
+// request is set to job if it is not nil.
+//
+// Methods which need the results of their requests return an
+// *ErrUnsupportedAsync on the returned client. They are EnsureGraph,
+// Traverse, ShortestPath, KShortestPaths, UpdateWithRetry,
+// UpdateVertexWithRetry and the methods returning a *Cursor.
+func (c *Connection) Async(mode string, job *AsyncJob) Client {
+	ac := *c
+	ac.asyncMode = mode
+	ac.asyncJob = job
+	return &ac
+}
+
+// ErrUnsupportedAsync is returned when a method which needs the results of its
+// requests is called on a client returned by Async. No request is sent in
+// this case.
+type ErrUnsupportedAsync struct {
+	Feature string
+}
+
+func (e *ErrUnsupportedAsync) Error() string {
+	return fmt.Sprintf("%s is not supported on asynchronous connections", e.Feature)
+}
+
+// requireSync returns an *ErrUnsupportedAsync if the connection sends
+// requests asynchronously.
+func (c *Connection) requireSync(feature string) error {
+	if c.asyncMode != "" {
+		return &ErrUnsupportedAsync{Feature: feature}
+	}
+	return nil
+}
+
+// GetJobResult fetches the result of the job and decodes it into respBody,
+// which has the same type as the result of the original request.
+// done is false when the job has not finished yet. The result can be fetched
+// only once since the server removes it.
+func (c *Connection) GetJobResult(dbName, jobID string, respBody interface{}) (done bool, rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/job/%s",
+		pathParams: []interface{}{jobID},
+	})
+
+	rc, resp, err := c.send(http.MethodPut, path, nil, nil, respBody)
+	if resp != nil && resp.Header.Get("x-arango-async-id") != "" {
+		done = true
+	}
+	if err != nil {
+		return done, rc, fmt.Errorf("failed to get job result: %v", err)
+	}
+	return done, rc, nil
+}
+
+func (c *Connection) GetJobStatus(dbName, jobID string) (status string, rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/job/%s",
+		pathParams: []interface{}{jobID},
+	})
+
+	rc, _, err = c.send(http.MethodGet, path, nil, nil, nil)
+	if err != nil {
+		return "", rc, fmt.Errorf("failed to get job status: %v", err)
+	}
+	if rc == http.StatusNoContent {
+		return JobStatusPending, rc, nil
+	}
+	return JobStatusDone, rc, nil
+}
+
+// ListJobs returns the ids of the jobs with the status which is JobStatusDone
+// or JobStatusPending. count limits the number of ids if it is positive.
+func (c *Connection) ListJobs(dbName, status string, count int) (ids []string, rc int, err error) {
+	var params url.Values
+	if count > 0 {
+		params = make(url.Values)
+		params.Set("count", strconv.Itoa(count))
+	}
+	path := buildPath(pathConfig{
+		dbName:      dbName,
+		pathFormat:  "/_api/job/%s",
+		pathParams:  []interface{}{status},
+		queryParams: params,
+	})
+
+	rc, _, err = c.send(http.MethodGet, path, nil, nil, &ids)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	return ids, rc, nil
+}
+
+func (c *Connection) CancelJob(dbName, jobID string) (rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/job/%s/cancel",
+		pathParams: []interface{}{jobID},
+	})
+
+	rc, _, err = c.send(http.MethodPut, path, nil, nil, nil)
+	if err != nil {
+		return rc, fmt.Errorf("failed to cancel job: %v", err)
+	}
+	return rc, nil
+}
+
+type DeleteJobsConfig struct {
+	// Stamp is used with DeleteJobsExpired to delete the jobs created
+	// before it.
+	Stamp *time.Time
+}
+
+func (c *DeleteJobsConfig) queryParams() url.Values {
+	if c == nil {
+		return nil
+	}
+
+	var params url.Values
+	if c.Stamp != nil {
+		params = make(url.Values)
+		params.Set("stamp", strconv.FormatInt(c.Stamp.Unix(), 10))
+	}
+	return params
+}
+
+// DeleteJobs deletes the results of jobs. target is DeleteJobsAll,
+// DeleteJobsExpired or a job id.
+func (c *Connection) DeleteJobs(dbName, target string, config *DeleteJobsConfig) (rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:      dbName,
+		pathFormat:  "/_api/job/%s",
+		pathParams:  []interface{}{target},
+		queryParams: config.queryParams(),
+	})
+
+	rc, _, err = c.send(http.MethodDelete, path, nil, nil, nil)
+	if err != nil {
+		return rc, fmt.Errorf("failed to delete jobs: %v", err)
+	}
+	return rc, nil
+}
@@ -1,19 +1,255 @@
 package arangogo
 
-import "log"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/hnakamur/arangogo/vpack"
+)
+
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger is a structured and leveled logger.
 type Logger interface {
-	Log(msg string)
+	// Enabled returns whether logs at the level are written. It is used to
+	// skip building expensive fields like request and response bodies.
+	Enabled(level LogLevel) bool
+	Log(level LogLevel, msg string, fields ...LogField)
 }
 
 type stdLoggerBasedLogger struct {
 	logger *log.Logger
+	level  LogLevel
 }
 
-func (l stdLoggerBasedLogger) Log(msg string) {
-	l.logger.Println(msg)
+func (l stdLoggerBasedLogger) Enabled(level LogLevel) bool {
+	return level >= l.level
 }
 
+func (l stdLoggerBasedLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	if !l.Enabled(level) {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, ", %s=%v", f.Key, f.Value)
+	}
+	l.logger.Println(b.String())
+}
+
+// NewLoggerWithStdLogger returns a logger which writes logs at all levels to
+// the standard logger.
 func NewLoggerWithStdLogger(logger *log.Logger) Logger {
-	return &stdLoggerBasedLogger{logger: logger}
+	return &stdLoggerBasedLogger{logger: logger, level: LogLevelDebug}
+}
+
+// NewLeveledLoggerWithStdLogger returns a logger which writes logs at level
+// or higher to the standard logger.
+func NewLeveledLoggerWithStdLogger(logger *log.Logger, level LogLevel) Logger {
+	return &stdLoggerBasedLogger{logger: logger, level: level}
+}
+
+type slogBasedLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogBasedLogger) slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+func (l slogBasedLogger) Enabled(level LogLevel) bool {
+	return l.logger.Enabled(context.Background(), l.slogLevel(level))
+}
+
+func (l slogBasedLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	l.logger.LogAttrs(context.Background(), l.slogLevel(level), msg, attrs...)
+}
+
+func NewLoggerWithSlog(logger *slog.Logger) Logger {
+	return &slogBasedLogger{logger: logger}
+}
+
+const redacted = "REDACTED"
+
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization"}
+
+var redactedFields = []string{"passwd", "password"}
+
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, k := range redactedHeaders {
+		if _, ok := h[k]; ok {
+			h.Set(k, redacted)
+		}
+	}
+	return h
+}
+
+// redactBody replaces the values of password fields in a JSON body.
+// VelocyPack bodies are converted to JSON first.
+func redactBody(header http.Header, b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType == ContentTypeVelocyPack {
+		j, err := vpack.ToJSON(b)
+		if err != nil {
+			return fmt.Sprintf("(%d bytes of invalid VelocyPack body)", len(b))
+		}
+		b = j
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		// Non-JSON bodies like batch requests can contain credentials.
+		return fmt.Sprintf("(%d bytes of non-JSON body)", len(b))
+	}
+	if !redactValue(v) {
+		return string(b)
+	}
+	rb, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	return string(rb)
+}
+
+func redactValue(v interface{}) (changed bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			if isRedactedField(k) {
+				v[k] = redacted
+				changed = true
+			} else if redactValue(fv) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, ev := range v {
+			if redactValue(ev) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func isRedactedField(key string) bool {
+	for _, f := range redactedFields {
+		if strings.EqualFold(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Connection) logURL(req *Request) string {
+	u, err := url.Parse(c.url + req.Path)
+	if err != nil {
+		return req.Path
+	}
+	return u.Redacted()
+}
+
+func (c *Connection) logExchange(req *Request, resp *Response, duration time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	level := LogLevelInfo
+	if resp.StatusCode >= http.StatusInternalServerError {
+		level = LogLevelError
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		level = LogLevelWarn
+	}
+	if c.logger.Enabled(level) {
+		fields := []LogField{
+			{Key: "method", Value: req.Method},
+			{Key: "url", Value: c.logURL(req)},
+			{Key: "status", Value: resp.StatusCode},
+			{Key: "duration", Value: duration},
+			{Key: "bytesSent", Value: len(req.Payload)},
+			{Key: "bytesReceived", Value: len(resp.Body)},
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			if errorNum := resp.ErrorNum(); errorNum != 0 {
+				fields = append(fields, LogField{Key: "errorNum", Value: errorNum})
+			}
+		}
+		c.logger.Log(level, "Connection send", fields...)
+	}
+
+	if c.logger.Enabled(LogLevelDebug) {
+		c.logger.Log(LogLevelDebug, "Connection send detail",
+			LogField{Key: "method", Value: req.Method},
+			LogField{Key: "url", Value: c.logURL(req)},
+			LogField{Key: "requestHeader", Value: redactHeader(req.Header)},
+			LogField{Key: "payload", Value: redactBody(req.Header, req.Payload)},
+			LogField{Key: "responseHeader", Value: resp.Header},
+			LogField{Key: "responseBody", Value: redactBody(resp.Header, resp.Body)},
+		)
+	}
+}
+
+func (c *Connection) logError(req *Request, err error, duration time.Duration) {
+	if c.logger == nil || !c.logger.Enabled(LogLevelError) {
+		return
+	}
+	c.logger.Log(LogLevelError, "Connection send failed",
+		LogField{Key: "method", Value: req.Method},
+		LogField{Key: "url", Value: c.logURL(req)},
+		LogField{Key: "duration", Value: duration},
+		LogField{Key: "error", Value: err},
+	)
 }
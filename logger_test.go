@@ -0,0 +1,156 @@
+package arangogo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hnakamur/arangogo/vpack"
+)
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{
+		"Authorization":       {"Basic cm9vdDpzZWNyZXQ="},
+		"Proxy-Authorization": {"Basic cHJveHk6c2VjcmV0"},
+		"Content-Type":        {"application/json"},
+	}
+	got := redactHeader(header)
+	for _, k := range []string{"Authorization", "Proxy-Authorization"} {
+		if v := got.Get(k); v != redacted {
+			t.Errorf("%s = %q, want %q", k, v, redacted)
+		}
+	}
+	if v := got.Get("Content-Type"); v != "application/json" {
+		t.Errorf("Content-Type = %q", v)
+	}
+	if v := header.Get("Authorization"); v != "Basic cm9vdDpzZWNyZXQ=" {
+		t.Errorf("original Authorization was changed to %q", v)
+	}
+	if _, ok := redactHeader(http.Header{})["Authorization"]; ok {
+		t.Error("Authorization was added")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		want   string
+	}{
+		{"empty", jsonHeader, ``, ``},
+		{"no secrets", jsonHeader, `{"name":"test", "count":1}`, `{"name":"test", "count":1}`},
+		{"top level passwd", jsonHeader, `{"name":"test","passwd":"secret"}`, `{"name":"test","passwd":"REDACTED"}`},
+		{"top level password", jsonHeader, `{"Password":"secret"}`, `{"Password":"REDACTED"}`},
+		{
+			"nested users",
+			jsonHeader,
+			`{"name":"test","users":[{"username":"alice","passwd":"secret1"},{"username":"bob","password":"secret2"}]}`,
+			`{"name":"test","users":[{"passwd":"REDACTED","username":"alice"},{"password":"REDACTED","username":"bob"}]}`,
+		},
+		{"non-JSON", http.Header{"Content-Type": {"multipart/form-data"}}, "--x\r\npasswd=secret\r\n", "(20 bytes of non-JSON body)"},
+		{"invalid VelocyPack", http.Header{"Content-Type": {ContentTypeVelocyPack}}, "\x0b", "(1 bytes of invalid VelocyPack body)"},
+	}
+	for _, tt := range tests {
+		body := []byte(tt.body)
+		if got := redactBody(tt.header, body); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+		if string(body) != tt.body {
+			t.Errorf("%s: original body was changed to %s", tt.name, body)
+		}
+	}
+}
+
+func TestRedactVelocyPackBody(t *testing.T) {
+	body, err := vpack.Marshal(map[string]interface{}{"name": "test", "passwd": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := string(body)
+	got := redactBody(http.Header{"Content-Type": {ContentTypeVelocyPack}}, body)
+	if want := `{"name":"test","passwd":"REDACTED"}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if string(body) != orig {
+		t.Error("original body was changed")
+	}
+}
+
+// captureLogger keeps the fields of the logs by message.
+type captureLogger struct {
+	mu     sync.Mutex
+	fields map[string]map[string]interface{}
+}
+
+func (l *captureLogger) Enabled(level LogLevel) bool {
+	return true
+}
+
+func (l *captureLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fields == nil {
+		l.fields = make(map[string]map[string]interface{})
+	}
+	m := make(map[string]interface{})
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	l.fields[msg] = m
+}
+
+func TestLoggingRedactsCredentials(t *testing.T) {
+	var gotAuth, gotBody string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotAuth, gotBody = r.Header.Get("Authorization"), string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"error":false,"code":201,"result":true}`))
+	}))
+	defer s.Close()
+
+	logger := new(captureLogger)
+	c, err := NewConnection(&Config{URL: s.URL, Username: "root", Password: "rootsecret", Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.CreateDatabase(CreateDatabaseConfig{
+		Name:   "test",
+		Passwd: "dbsecret",
+		Users:  []CreateDatabaseConfigUser{{Username: "alice", Passwd: "alicesecret"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail := logger.fields["Connection send detail"]
+	if detail == nil {
+		t.Fatalf("no detail log in %v", logger.fields)
+	}
+	logged := fmt.Sprint(detail)
+	for _, secret := range []string{"rootsecret", "dbsecret", "alicesecret", "cm9vdDpyb290c2VjcmV0"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("%s is logged: %s", secret, logged)
+		}
+	}
+	if h := detail["requestHeader"].(http.Header); h.Get("Authorization") != redacted {
+		t.Errorf("logged Authorization = %q", h.Get("Authorization"))
+	}
+
+	// The request sent to the server is not changed.
+	if gotAuth != "Basic cm9vdDpyb290c2VjcmV0" {
+		t.Errorf("sent Authorization = %q", gotAuth)
+	}
+	for _, secret := range []string{`"passwd":"dbsecret"`, `"passwd":"alicesecret"`} {
+		if !strings.Contains(gotBody, secret) {
+			t.Errorf("sent body %s does not contain %s", gotBody, secret)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package arangogo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Request is an outgoing request to ArangoDB. Path is relative to the
+// connection URL and includes the database prefix and the query string.
+type Request struct {
+	// Context is the context set with Connection.WithContext.
+	Context context.Context
+	Method  string
+	Path    string
+	Header  http.Header
+	Payload []byte
+	// Attempt is the number of times the request was sent before.
+	// Middlewares which resend a request should increment it.
+	// UpdateWithRetry and UpdateVertexWithRetry set it to the number of
+	// earlier attempts of the update.
+	Attempt int
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// ErrorNum returns the errorNum in the body of an error response, or 0 if
+// there is none.
+func (r *Response) ErrorNum() int {
+	var errBody struct {
+		ErrorNum int `json:"errorNum"`
+	}
+	if BodyCodec(r.Header).Unmarshal(r.Body, &errBody) != nil {
+		return 0
+	}
+	return errBody.ErrorNum
+}
+
+// Handler sends a request and returns the response. The error is for
+// failures to get a response, not for error status codes.
+type Handler func(req *Request) (*Response, error)
+
+// Middleware wraps a handler. It can modify the request before calling next,
+// return a response without calling next, or observe the response.
+type Middleware func(next Handler) Handler
+
+type attemptKey struct{}
+
+// withAttempt returns a connection whose requests have Attempt set to
+// attempt.
+func (c *Connection) withAttempt(attempt int) *Connection {
+	return c.WithContext(context.WithValue(c.context(), attemptKey{}, attempt))
+}
+
+func attemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+func chainMiddlewares(h Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+func (r *Response) httpResponse() *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode: r.StatusCode,
+		Header:     r.Header,
+	}
+}
+
+func (c *Connection) httpRequest(req *Request) (*http.Request, error) {
+	var reader io.Reader
+	if req.Payload != nil {
+		reader = bytes.NewBuffer(req.Payload)
+	}
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	hreq, err := http.NewRequestWithContext(ctx, req.Method, c.url+req.Path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if req.Header != nil {
+		hreq.Header = req.Header
+	}
+	return hreq, nil
+}
+
+func (c *Connection) roundTrip(req *Request) (*Response, error) {
+	hreq, err := c.httpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(hreq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       b,
+	}, nil
+}
+
+func (c *Connection) loggingMiddleware(next Handler) Handler {
+	return func(req *Request) (*Response, error) {
+		start := time.Now()
+		resp, err := next(req)
+		if err != nil {
+			c.logError(req, err, time.Since(start))
+			return nil, err
+		}
+		c.logExchange(req, resp, time.Since(start))
+		return resp, nil
+	}
+}
@@ -0,0 +1,141 @@
+package arangogo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+const defaultUpdateWithRetryMaxAttempts = 10
+
+type UpdateWithRetryConfig struct {
+	// MaxAttempts is the maximum number of read-modify-write cycles.
+	// The default is 10.
+	MaxAttempts int
+	WaitForSync *bool
+}
+
+func (c *UpdateWithRetryConfig) maxAttempts() int {
+	if c == nil || c.MaxAttempts <= 0 {
+		return defaultUpdateWithRetryMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+func (c *UpdateWithRetryConfig) waitForSync() *bool {
+	if c == nil {
+		return nil
+	}
+	return c.WaitForSync
+}
+
+// ConflictError is returned when a document was modified by others in every
+// attempt of a read-modify-write cycle.
+type ConflictError struct {
+	Handle   string
+	Attempts int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("document was modified concurrently. handle=%s, attempts=%d", e.Handle, e.Attempts)
+}
+
+func resetDocument(docPtr interface{}) error {
+	v := reflect.ValueOf(docPtr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("docPtr must be a non-nil pointer")
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	return nil
+}
+
+// UpdateWithRetry reads the document into docPtr, calls mutate and replaces
+// the document with docPtr only if the document was not modified meanwhile.
+// It repeats this until it succeeds or the maximum attempts are exhausted,
+// in which case it returns a *ConflictError.
+func (c *Connection) UpdateWithRetry(dbName, docHandle string, docPtr interface{}, mutate func(docPtr interface{}) error, config *UpdateWithRetryConfig) (r ReplaceDocumentResult, rc int, err error) {
+	if err := c.requireSync("UpdateWithRetry"); err != nil {
+		return r, 0, err
+	}
+	maxAttempts := config.maxAttempts()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		ac := c.withAttempt(attempt - 1)
+		var raw json.RawMessage
+		rc, err = ac.ReadDocument(dbName, docHandle, nil, &raw)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update document with retry: %v", err)
+		}
+		var doc Document
+		err = json.Unmarshal(raw, &doc)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update document with retry: %v", err)
+		}
+		err = resetDocument(docPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update document with retry: %v", err)
+		}
+		err = json.Unmarshal(raw, docPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update document with retry: %v", err)
+		}
+
+		err = mutate(docPtr)
+		if err != nil {
+			return r, rc, err
+		}
+
+		r, rc, err = ac.ReplaceDocument(dbName, docHandle, docPtr, &ReplaceDocumentConfig{
+			WaitForSync: config.waitForSync(),
+			IfMatch:     doc.Rev,
+		}, nil, nil)
+		if rc == http.StatusPreconditionFailed {
+			continue
+		}
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update document with retry: %v", err)
+		}
+		return r, rc, nil
+	}
+	return r, rc, &ConflictError{Handle: docHandle, Attempts: maxAttempts}
+}
+
+// UpdateVertexWithRetry is the same as UpdateWithRetry except that it reads
+// and replaces the vertex through the graph.
+func (c *Connection) UpdateVertexWithRetry(dbName, graphName, collName, vertexKey string, vertexPtr interface{}, mutate func(vertexPtr interface{}) error, config *UpdateWithRetryConfig) (r ReplaceVertexResult, rc int, err error) {
+	if err := c.requireSync("UpdateVertexWithRetry"); err != nil {
+		return r, 0, err
+	}
+	maxAttempts := config.maxAttempts()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		ac := c.withAttempt(attempt - 1)
+		err = resetDocument(vertexPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update vertex with retry: %v", err)
+		}
+		var getRes GetVertexResult
+		getRes, rc, err = ac.GetVertex(dbName, graphName, collName, vertexKey, nil, vertexPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update vertex with retry: %v", err)
+		}
+
+		err = mutate(vertexPtr)
+		if err != nil {
+			return r, rc, err
+		}
+
+		r, rc, err = ac.ReplaceVertex(dbName, graphName, collName, vertexKey, vertexPtr, &ReplaceVertexConfig{
+			WaitForSync: config.waitForSync(),
+			IfMatch:     getRes.Rev,
+		}, nil, nil)
+		if rc == http.StatusPreconditionFailed {
+			continue
+		}
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to update vertex with retry: %v", err)
+		}
+		return r, rc, nil
+	}
+	return r, rc, &ConflictError{Handle: collName + "/" + vertexKey, Attempts: maxAttempts}
+}
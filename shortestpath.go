@@ -0,0 +1,143 @@
+package arangogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Path is a path between two vertices. Set Vertices and Edges to pointers to
+// slices of your types before passing it to decode them into your types.
+type Path struct {
+	Vertices interface{} `json:"vertices"`
+	Edges    interface{} `json:"edges"`
+	Weight   float64     `json:"weight"`
+}
+
+type ShortestPathConfig struct {
+	Direction       string
+	WeightAttribute string
+	DefaultWeight   *float64
+}
+
+func (c *ShortestPathConfig) options(bindVars map[string]interface{}) (direction, options string, err error) {
+	if c == nil {
+		return "OUTBOUND", "", nil
+	}
+	direction, err = aqlDirection(c.Direction)
+	if err != nil {
+		return "", "", err
+	}
+	var opts []string
+	if c.WeightAttribute != "" {
+		opts = append(opts, "weightAttribute: @pathWeightAttribute")
+		bindVars["pathWeightAttribute"] = c.WeightAttribute
+	}
+	if c.DefaultWeight != nil {
+		opts = append(opts, "defaultWeight: @pathDefaultWeight")
+		bindVars["pathDefaultWeight"] = *c.DefaultWeight
+	}
+	if len(opts) > 0 {
+		options = " OPTIONS {" + strings.Join(opts, ", ") + "}"
+	}
+	return direction, options, nil
+}
+
+func (c *ShortestPathConfig) weightExpr(bindVars map[string]interface{}) string {
+	if c == nil || c.WeightAttribute == "" {
+		return "LENGTH(edges)"
+	}
+	if _, ok := bindVars["pathDefaultWeight"]; !ok {
+		bindVars["pathDefaultWeight"] = 1
+	}
+	return "SUM(edges[* RETURN HAS(CURRENT, @pathWeightAttribute) ? CURRENT[@pathWeightAttribute] : @pathDefaultWeight])"
+}
+
+// ShortestPath finds the shortest path between from and to and decodes it into
+// path. found is false when there is no path.
+func (c *Connection) ShortestPath(dbName, graphName, from, to string, config *ShortestPathConfig, path *Path) (found bool, rc int, err error) {
+	if err := c.requireSync("ShortestPath"); err != nil {
+		return false, 0, err
+	}
+	bindVars := map[string]interface{}{
+		"pathFrom":      from,
+		"pathTo":        to,
+		"pathGraphName": graphName,
+	}
+	direction, options, err := config.options(bindVars)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to get shortest path: %v", err)
+	}
+	query := "LET steps = (FOR v, e IN " + direction + " SHORTEST_PATH @pathFrom TO @pathTo GRAPH @pathGraphName" + options +
+		" RETURN {vertex: v, edge: e})" +
+		" LET edges = steps[* FILTER CURRENT.edge != null RETURN CURRENT.edge]" +
+		" RETURN {vertices: steps[*].vertex, edges: edges, weight: " + config.weightExpr(bindVars) + "}"
+
+	results, rc, err := c.query(dbName, createCursorConfig{Query: query, BindVars: bindVars})
+	if err != nil {
+		return false, rc, fmt.Errorf("failed to get shortest path: %v", err)
+	}
+	if len(results) == 0 {
+		return false, rc, nil
+	}
+
+	var body struct {
+		Vertices []rawValue `json:"vertices"`
+	}
+	err = results[0].unmarshal(&body)
+	if err != nil {
+		return false, rc, fmt.Errorf("failed to decode shortest path: %v", err)
+	}
+	if len(body.Vertices) == 0 {
+		return false, rc, nil
+	}
+	if path != nil {
+		err = results[0].unmarshal(path)
+		if err != nil {
+			return false, rc, fmt.Errorf("failed to decode shortest path: %v", err)
+		}
+	}
+	return true, rc, nil
+}
+
+// KShortestPaths finds up to k shortest paths between from and to in the
+// order of increasing weight. newPath is called for each path to prepare the
+// destination of its vertices and edges; it can be nil.
+func (c *Connection) KShortestPaths(dbName, graphName, from, to string, k int, config *ShortestPathConfig, newPath func() Path) (paths []Path, rc int, err error) {
+	if k <= 0 {
+		return nil, 0, fmt.Errorf("failed to get k shortest paths: invalid k: %d", k)
+	}
+	if err := c.requireSync("KShortestPaths"); err != nil {
+		return nil, 0, err
+	}
+	if err := c.requireVersion("K_SHORTEST_PATHS", 30500); err != nil {
+		return nil, 0, err
+	}
+	bindVars := map[string]interface{}{
+		"pathFrom":      from,
+		"pathTo":        to,
+		"pathGraphName": graphName,
+		"pathK":         k,
+	}
+	direction, options, err := config.options(bindVars)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get k shortest paths: %v", err)
+	}
+	query := "FOR p IN " + direction + " K_SHORTEST_PATHS @pathFrom TO @pathTo GRAPH @pathGraphName" + options +
+		" LIMIT @pathK RETURN {vertices: p.vertices, edges: p.edges, weight: p.weight}"
+
+	results, rc, err := c.query(dbName, createCursorConfig{Query: query, BindVars: bindVars})
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to get k shortest paths: %v", err)
+	}
+	paths = make([]Path, len(results))
+	for i, result := range results {
+		if newPath != nil {
+			paths[i] = newPath()
+		}
+		err = result.unmarshal(&paths[i])
+		if err != nil {
+			return nil, rc, fmt.Errorf("failed to decode k shortest paths: %v", err)
+		}
+	}
+	return paths, rc, nil
+}
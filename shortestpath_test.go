@@ -0,0 +1,201 @@
+package arangogo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cursorServer answers cursor requests with fixed results and keeps the
+// queries it received.
+type cursorServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	queries []createCursorConfig
+}
+
+func newCursorServer(t *testing.T, result string) *cursorServer {
+	t.Helper()
+	s := new(cursorServer)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var q createCursorConfig
+		if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+			t.Errorf("invalid cursor request: %v", err)
+		}
+		s.mu.Lock()
+		s.queries = append(s.queries, q)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"error":false,"code":201,"hasMore":false,"result":` + result + `}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *cursorServer) connection(t *testing.T, version int) *Connection {
+	t.Helper()
+	c, err := NewConnection(&Config{URL: s.URL, ArangoVersion: version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func (s *cursorServer) lastQuery(t *testing.T) createCursorConfig {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queries) == 0 {
+		t.Fatal("no query was sent")
+	}
+	return s.queries[len(s.queries)-1]
+}
+
+func (s *cursorServer) queryCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queries)
+}
+
+func TestShortestPath(t *testing.T) {
+	s := newCursorServer(t, `[{"vertices":[{"_key":"a"},{"_key":"b"}],"edges":[{"_key":"e1","w":2}],"weight":2}]`)
+	c := s.connection(t, 30700)
+
+	var vertices []struct {
+		Key string `json:"_key"`
+	}
+	path := Path{Vertices: &vertices}
+	found, rc, err := c.ShortestPath("test", "g", "users/a", "users/b",
+		&ShortestPathConfig{Direction: TraversalDirectionAny, WeightAttribute: "w"}, &path)
+	if err != nil || rc != http.StatusCreated || !found {
+		t.Fatalf("found = %v, rc = %d, err = %v", found, rc, err)
+	}
+	if len(vertices) != 2 || vertices[1].Key != "b" || path.Weight != 2 {
+		t.Errorf("path = %+v, vertices = %+v", path, vertices)
+	}
+
+	q := s.lastQuery(t)
+	wantQuery := "LET steps = (FOR v, e IN ANY SHORTEST_PATH @pathFrom TO @pathTo GRAPH @pathGraphName OPTIONS {weightAttribute: @pathWeightAttribute}" +
+		" RETURN {vertex: v, edge: e})" +
+		" LET edges = steps[* FILTER CURRENT.edge != null RETURN CURRENT.edge]" +
+		" RETURN {vertices: steps[*].vertex, edges: edges, weight: SUM(edges[* RETURN HAS(CURRENT, @pathWeightAttribute) ? CURRENT[@pathWeightAttribute] : @pathDefaultWeight])}"
+	if q.Query != wantQuery {
+		t.Errorf("query = %s, want %s", q.Query, wantQuery)
+	}
+	wantBindVars := map[string]interface{}{
+		"pathFrom": "users/a", "pathTo": "users/b", "pathGraphName": "g",
+		"pathWeightAttribute": "w", "pathDefaultWeight": 1.0,
+	}
+	if !reflect.DeepEqual(q.BindVars, wantBindVars) {
+		t.Errorf("bind vars = %v, want %v", q.BindVars, wantBindVars)
+	}
+}
+
+func TestShortestPathQueryWithoutConfig(t *testing.T) {
+	s := newCursorServer(t, `[]`)
+	c := s.connection(t, 30700)
+	if _, _, err := c.ShortestPath("test", "g", "users/a", "users/b", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	q := s.lastQuery(t)
+	if !strings.Contains(q.Query, " OUTBOUND SHORTEST_PATH ") || !strings.HasSuffix(q.Query, "weight: LENGTH(edges)}") {
+		t.Errorf("query = %s", q.Query)
+	}
+	if _, ok := q.BindVars["pathDefaultWeight"]; ok || len(q.BindVars) != 3 {
+		t.Errorf("bind vars = %v", q.BindVars)
+	}
+
+	weight := 3.0
+	if _, _, err := c.ShortestPath("test", "g", "users/a", "users/b", &ShortestPathConfig{WeightAttribute: "w", DefaultWeight: &weight}, nil); err != nil {
+		t.Fatal(err)
+	}
+	q = s.lastQuery(t)
+	if !strings.Contains(q.Query, "OPTIONS {weightAttribute: @pathWeightAttribute, defaultWeight: @pathDefaultWeight}") || q.BindVars["pathDefaultWeight"] != 3.0 {
+		t.Errorf("query = %s, bind vars = %v", q.Query, q.BindVars)
+	}
+}
+
+func TestShortestPathNotFound(t *testing.T) {
+	for _, result := range []string{`[]`, `[{"vertices":[],"edges":[],"weight":0}]`} {
+		s := newCursorServer(t, result)
+		c := s.connection(t, 30700)
+		path := Path{Weight: -1}
+		found, _, err := c.ShortestPath("test", "g", "users/a", "users/z", nil, &path)
+		if err != nil || found {
+			t.Errorf("%s: found = %v, err = %v", result, found, err)
+		}
+		if path.Weight != -1 {
+			t.Errorf("%s: path was changed: %+v", result, path)
+		}
+	}
+}
+
+func TestShortestPathInvalidDirection(t *testing.T) {
+	s := newCursorServer(t, `[]`)
+	c := s.connection(t, 30700)
+	if _, _, err := c.ShortestPath("test", "g", "users/a", "users/b", &ShortestPathConfig{Direction: "up"}, nil); err == nil {
+		t.Error("got no error for an invalid direction")
+	}
+	if n := s.queryCount(); n != 0 {
+		t.Errorf("queries = %d, want 0", n)
+	}
+}
+
+func TestKShortestPaths(t *testing.T) {
+	s := newCursorServer(t, `[{"vertices":[{"_key":"a"},{"_key":"b"}],"edges":[{}],"weight":1},{"vertices":[{"_key":"a"},{"_key":"c"},{"_key":"b"}],"edges":[{},{}],"weight":2}]`)
+	c := s.connection(t, 30500)
+
+	type vertex struct {
+		Key string `json:"_key"`
+	}
+	var keys [][]vertex
+	paths, _, err := c.KShortestPaths("test", "g", "users/a", "users/b", 2, nil, func() Path {
+		keys = append(keys, nil)
+		return Path{Vertices: &keys[len(keys)-1]}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0].Weight != 1 || paths[1].Weight != 2 {
+		t.Fatalf("paths = %+v", paths)
+	}
+	if got := *paths[1].Vertices.(*[]vertex); len(got) != 3 || got[1].Key != "c" {
+		t.Errorf("vertices = %+v", got)
+	}
+
+	q := s.lastQuery(t)
+	wantQuery := "FOR p IN OUTBOUND K_SHORTEST_PATHS @pathFrom TO @pathTo GRAPH @pathGraphName" +
+		" LIMIT @pathK RETURN {vertices: p.vertices, edges: p.edges, weight: p.weight}"
+	if q.Query != wantQuery {
+		t.Errorf("query = %s, want %s", q.Query, wantQuery)
+	}
+	wantBindVars := map[string]interface{}{
+		"pathFrom": "users/a", "pathTo": "users/b", "pathGraphName": "g", "pathK": 2.0,
+	}
+	if !reflect.DeepEqual(q.BindVars, wantBindVars) {
+		t.Errorf("bind vars = %v, want %v", q.BindVars, wantBindVars)
+	}
+}
+
+func TestKShortestPathsErrors(t *testing.T) {
+	s := newCursorServer(t, `[]`)
+	for _, k := range []int{0, -1} {
+		if _, _, err := s.connection(t, 30500).KShortestPaths("test", "g", "users/a", "users/b", k, nil, nil); err == nil || !strings.Contains(err.Error(), "invalid k") {
+			t.Errorf("k = %d: err = %v", k, err)
+		}
+	}
+
+	_, rc, err := s.connection(t, 30400).KShortestPaths("test", "g", "users/a", "users/b", 1, nil, nil)
+	e, ok := err.(*ErrUnsupportedByServer)
+	if !ok || rc != 0 || e.Feature != "K_SHORTEST_PATHS" || e.RequiredVersion != 30500 || e.ServerVersion != 30400 {
+		t.Errorf("rc = %d, err = %#v", rc, err)
+	}
+	if n := s.queryCount(); n != 0 {
+		t.Errorf("queries = %d, want 0", n)
+	}
+}
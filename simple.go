@@ -0,0 +1,319 @@
+package arangogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (c *Connection) simpleQueryCursor(dbName, operation string, config interface{}) (cur *Cursor, rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/simple/%s",
+		pathParams: []interface{}{operation},
+	})
+
+	var body cursorBody
+	rc, _, err = c.send(http.MethodPut, path, nil, config, &body)
+	if err != nil {
+		return nil, rc, err
+	}
+	return newCursor(c, dbName, body), rc, nil
+}
+
+func (c *Connection) simpleQuery(dbName, operation string, config, respBody interface{}) (rc int, err error) {
+	path := buildPath(pathConfig{
+		dbName:     dbName,
+		pathFormat: "/_api/simple/%s",
+		pathParams: []interface{}{operation},
+	})
+
+	rc, _, err = c.send(http.MethodPut, path, nil, config, respBody)
+	return rc, err
+}
+
+type ByExampleConfig struct {
+	Collection string      `json:"collection"`
+	Example    interface{} `json:"example"`
+	Skip       int         `json:"skip,omitempty"`
+	Limit      int         `json:"limit,omitempty"`
+	BatchSize  int         `json:"batchSize,omitempty"`
+}
+
+func (c *Connection) ByExample(dbName string, config ByExampleConfig) (cur *Cursor, rc int, err error) {
+	if err := c.requireSync("ByExample"); err != nil {
+		return nil, 0, err
+	}
+	cur, rc, err = c.simpleQueryCursor(dbName, "by-example", config)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to query by example: %v", err)
+	}
+	return cur, rc, nil
+}
+
+type FirstExampleConfig struct {
+	Collection string      `json:"collection"`
+	Example    interface{} `json:"example"`
+}
+
+func (c *Connection) FirstExample(dbName string, config FirstExampleConfig, docPtr interface{}) (rc int, err error) {
+	var body struct {
+		Document interface{} `json:"document"`
+	}
+	if docPtr != nil {
+		body.Document = docPtr
+	}
+	rc, err = c.simpleQuery(dbName, "first-example", config, &body)
+	if err != nil {
+		return rc, fmt.Errorf("failed to query first example: %v", err)
+	}
+	return rc, nil
+}
+
+type AnyConfig struct {
+	Collection string `json:"collection"`
+}
+
+// Any decodes a random document in the collection into docPtr. found is false
+// when the collection is empty.
+func (c *Connection) Any(dbName string, config AnyConfig, docPtr interface{}) (found bool, rc int, err error) {
+	var body struct {
+		Document json.RawMessage `json:"document"`
+	}
+	rc, err = c.simpleQuery(dbName, "any", config, &body)
+	if err != nil {
+		return false, rc, fmt.Errorf("failed to query any document: %v", err)
+	}
+	if len(body.Document) == 0 || string(body.Document) == "null" {
+		return false, rc, nil
+	}
+	if docPtr != nil {
+		err = json.Unmarshal(body.Document, docPtr)
+		if err != nil {
+			return false, rc, fmt.Errorf("failed to decode any document: %v", err)
+		}
+	}
+	return true, rc, nil
+}
+
+type RangeConfig struct {
+	Collection string      `json:"collection"`
+	Attribute  string      `json:"attribute"`
+	Left       interface{} `json:"left"`
+	Right      interface{} `json:"right"`
+	Closed     *bool       `json:"closed,omitempty"`
+	Skip       int         `json:"skip,omitempty"`
+	Limit      int         `json:"limit,omitempty"`
+	BatchSize  int         `json:"batchSize,omitempty"`
+}
+
+func (c *Connection) Range(dbName string, config RangeConfig) (cur *Cursor, rc int, err error) {
+	if err := c.requireSync("Range"); err != nil {
+		return nil, 0, err
+	}
+	cur, rc, err = c.simpleQueryCursor(dbName, "range", config)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to query range: %v", err)
+	}
+	return cur, rc, nil
+}
+
+type NearConfig struct {
+	Collection string  `json:"collection"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	// Distance is the attribute name to store the distance in the result.
+	Distance  string `json:"distance,omitempty"`
+	Skip      int    `json:"skip,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	Geo       string `json:"geo,omitempty"`
+	BatchSize int    `json:"batchSize,omitempty"`
+}
+
+func (c *Connection) Near(dbName string, config NearConfig) (cur *Cursor, rc int, err error) {
+	if err := c.requireSync("Near"); err != nil {
+		return nil, 0, err
+	}
+	cur, rc, err = c.simpleQueryCursor(dbName, "near", config)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to query near: %v", err)
+	}
+	return cur, rc, nil
+}
+
+type WithinConfig struct {
+	Collection string  `json:"collection"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Radius     float64 `json:"radius"`
+	// Distance is the attribute name to store the distance in the result.
+	Distance  string `json:"distance,omitempty"`
+	Skip      int    `json:"skip,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	Geo       string `json:"geo,omitempty"`
+	BatchSize int    `json:"batchSize,omitempty"`
+}
+
+func (c *Connection) Within(dbName string, config WithinConfig) (cur *Cursor, rc int, err error) {
+	if err := c.requireSync("Within"); err != nil {
+		return nil, 0, err
+	}
+	cur, rc, err = c.simpleQueryCursor(dbName, "within", config)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to query within: %v", err)
+	}
+	return cur, rc, nil
+}
+
+type FulltextConfig struct {
+	Collection string `json:"collection"`
+	Attribute  string `json:"attribute"`
+	Query      string `json:"query"`
+	Skip       int    `json:"skip,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Index      string `json:"index,omitempty"`
+	BatchSize  int    `json:"batchSize,omitempty"`
+}
+
+func (c *Connection) Fulltext(dbName string, config FulltextConfig) (cur *Cursor, rc int, err error) {
+	if err := c.requireSync("Fulltext"); err != nil {
+		return nil, 0, err
+	}
+	cur, rc, err = c.simpleQueryCursor(dbName, "fulltext", config)
+	if err != nil {
+		return nil, rc, fmt.Errorf("failed to query fulltext: %v", err)
+	}
+	return cur, rc, nil
+}
+
+type LookupByKeysConfig struct {
+	Collection string   `json:"collection"`
+	Keys       []string `json:"keys"`
+}
+
+func (c *Connection) LookupByKeys(dbName string, config LookupByKeysConfig, docsPtr interface{}) (rc int, err error) {
+	var body struct {
+		Documents interface{} `json:"documents"`
+	}
+	if docsPtr != nil {
+		body.Documents = docsPtr
+	}
+	rc, err = c.simpleQuery(dbName, "lookup-by-keys", config, &body)
+	if err != nil {
+		return rc, fmt.Errorf("failed to lookup by keys: %v", err)
+	}
+	return rc, nil
+}
+
+type RemoveByKeysConfigOptions struct {
+	WaitForSync *bool `json:"waitForSync,omitempty"`
+	ReturnOld   *bool `json:"returnOld,omitempty"`
+	Silent      *bool `json:"silent,omitempty"`
+}
+
+type RemoveByKeysConfig struct {
+	Collection string                     `json:"collection"`
+	Keys       []string                   `json:"keys"`
+	Options    *RemoveByKeysConfigOptions `json:"options,omitempty"`
+}
+
+type RemoveByKeysResult struct {
+	Removed int `json:"removed"`
+	Ignored int `json:"ignored"`
+}
+
+func (c *Connection) RemoveByKeys(dbName string, config RemoveByKeysConfig, oldDocsPtr interface{}) (r RemoveByKeysResult, rc int, err error) {
+	var body struct {
+		Removed int         `json:"removed"`
+		Ignored int         `json:"ignored"`
+		Old     interface{} `json:"old"`
+	}
+	if oldDocsPtr != nil {
+		body.Old = oldDocsPtr
+	}
+	rc, err = c.simpleQuery(dbName, "remove-by-keys", config, &body)
+	for _, key := range config.Keys {
+		c.invalidateDocument(dbName, config.Collection+"/"+key)
+	}
+	if err != nil {
+		return r, rc, fmt.Errorf("failed to remove by keys: %v", err)
+	}
+	r = RemoveByKeysResult{
+		Removed: body.Removed,
+		Ignored: body.Ignored,
+	}
+	return r, rc, nil
+}
+
+type RemoveByExampleConfigOptions struct {
+	WaitForSync *bool `json:"waitForSync,omitempty"`
+	Limit       int   `json:"limit,omitempty"`
+}
+
+type RemoveByExampleConfig struct {
+	Collection string                        `json:"collection"`
+	Example    interface{}                   `json:"example"`
+	Options    *RemoveByExampleConfigOptions `json:"options,omitempty"`
+}
+
+func (c *Connection) RemoveByExample(dbName string, config RemoveByExampleConfig) (deleted int, rc int, err error) {
+	var body struct {
+		Deleted int `json:"deleted"`
+	}
+	rc, err = c.simpleQuery(dbName, "remove-by-example", config, &body)
+	c.invalidateCollection(dbName, config.Collection)
+	if err != nil {
+		return 0, rc, fmt.Errorf("failed to remove by example: %v", err)
+	}
+	return body.Deleted, rc, nil
+}
+
+type UpdateByExampleConfigOptions struct {
+	KeepNull     *bool `json:"keepNull,omitempty"`
+	WaitForSync  *bool `json:"waitForSync,omitempty"`
+	Limit        int   `json:"limit,omitempty"`
+	MergeObjects *bool `json:"mergeObjects,omitempty"`
+}
+
+type UpdateByExampleConfig struct {
+	Collection string                        `json:"collection"`
+	Example    interface{}                   `json:"example"`
+	NewValue   interface{}                   `json:"newValue"`
+	Options    *UpdateByExampleConfigOptions `json:"options,omitempty"`
+}
+
+func (c *Connection) UpdateByExample(dbName string, config UpdateByExampleConfig) (updated int, rc int, err error) {
+	var body struct {
+		Updated int `json:"updated"`
+	}
+	rc, err = c.simpleQuery(dbName, "update-by-example", config, &body)
+	c.invalidateCollection(dbName, config.Collection)
+	if err != nil {
+		return 0, rc, fmt.Errorf("failed to update by example: %v", err)
+	}
+	return body.Updated, rc, nil
+}
+
+type ReplaceByExampleConfigOptions struct {
+	WaitForSync *bool `json:"waitForSync,omitempty"`
+	Limit       int   `json:"limit,omitempty"`
+}
+
+type ReplaceByExampleConfig struct {
+	Collection string                         `json:"collection"`
+	Example    interface{}                    `json:"example"`
+	NewValue   interface{}                    `json:"newValue"`
+	Options    *ReplaceByExampleConfigOptions `json:"options,omitempty"`
+}
+
+func (c *Connection) ReplaceByExample(dbName string, config ReplaceByExampleConfig) (replaced int, rc int, err error) {
+	var body struct {
+		Replaced int `json:"replaced"`
+	}
+	rc, err = c.simpleQuery(dbName, "replace-by-example", config, &body)
+	c.invalidateCollection(dbName, config.Collection)
+	if err != nil {
+		return 0, rc, fmt.Errorf("failed to replace by example: %v", err)
+	}
+	return body.Replaced, rc, nil
+}
@@ -0,0 +1,193 @@
+package arangogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	TraversalDirectionOutbound = "outbound"
+	TraversalDirectionInbound  = "inbound"
+	TraversalDirectionAny      = "any"
+)
+
+const (
+	TraversalOrderDFS = "dfs"
+	TraversalOrderBFS = "bfs"
+)
+
+const (
+	TraversalUniquenessNone   = "none"
+	TraversalUniquenessPath   = "path"
+	TraversalUniquenessGlobal = "global"
+)
+
+type TraversalUniqueness struct {
+	Vertices string
+	Edges    string
+}
+
+type TraversalConfig struct {
+	Direction string
+	MinDepth  int
+	// MaxDepth defaults to MinDepth, or 1 if MinDepth is also 0. Set it to
+	// IntPtr(0) to visit only the start vertex.
+	MaxDepth   *int
+	Uniqueness TraversalUniqueness
+	Order      string
+	// Filter is an AQL expression which can refer the variables v, e and p
+	// and bind parameters in BindVars.
+	Filter   string
+	BindVars map[string]interface{}
+}
+
+var traversalBindVarNames = []string{
+	"traversalMinDepth",
+	"traversalMaxDepth",
+	"traversalStartVertex",
+	"traversalGraphName",
+	"traversalBfs",
+	"traversalUniqueVertices",
+	"traversalUniqueEdges",
+}
+
+func aqlDirection(direction string) (string, error) {
+	switch direction {
+	case "", TraversalDirectionOutbound:
+		return "OUTBOUND", nil
+	case TraversalDirectionInbound:
+		return "INBOUND", nil
+	case TraversalDirectionAny:
+		return "ANY", nil
+	default:
+		return "", fmt.Errorf("invalid direction: %s", direction)
+	}
+}
+
+func validUniqueness(uniqueness string) bool {
+	switch uniqueness {
+	case TraversalUniquenessNone, TraversalUniquenessPath, TraversalUniquenessGlobal:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *TraversalConfig) query(graphName, startVertex string, withPath bool) (cfg createCursorConfig, err error) {
+	direction, err := aqlDirection(c.Direction)
+	if err != nil {
+		return cfg, err
+	}
+	minDepth := c.MinDepth
+	var maxDepth int
+	if c.MaxDepth != nil {
+		maxDepth = *c.MaxDepth
+	} else {
+		maxDepth = minDepth
+		if maxDepth == 0 {
+			maxDepth = 1
+		}
+	}
+	if minDepth < 0 || maxDepth < minDepth {
+		return cfg, fmt.Errorf("invalid depth range: %d..%d", minDepth, maxDepth)
+	}
+
+	bindVars := map[string]interface{}{
+		"traversalMinDepth":    minDepth,
+		"traversalMaxDepth":    maxDepth,
+		"traversalStartVertex": startVertex,
+		"traversalGraphName":   graphName,
+	}
+	var options []string
+	switch c.Order {
+	case "":
+	case TraversalOrderDFS, TraversalOrderBFS:
+		options = append(options, "bfs: @traversalBfs")
+		bindVars["traversalBfs"] = c.Order == TraversalOrderBFS
+	default:
+		return cfg, fmt.Errorf("invalid order: %s", c.Order)
+	}
+	if c.Uniqueness.Vertices != "" {
+		if !validUniqueness(c.Uniqueness.Vertices) {
+			return cfg, fmt.Errorf("invalid vertices uniqueness: %s", c.Uniqueness.Vertices)
+		}
+		options = append(options, "uniqueVertices: @traversalUniqueVertices")
+		bindVars["traversalUniqueVertices"] = c.Uniqueness.Vertices
+	}
+	if c.Uniqueness.Edges != "" {
+		if !validUniqueness(c.Uniqueness.Edges) {
+			return cfg, fmt.Errorf("invalid edges uniqueness: %s", c.Uniqueness.Edges)
+		}
+		options = append(options, "uniqueEdges: @traversalUniqueEdges")
+		bindVars["traversalUniqueEdges"] = c.Uniqueness.Edges
+	}
+	for name, value := range c.BindVars {
+		for _, reserved := range traversalBindVarNames {
+			if name == reserved {
+				return cfg, fmt.Errorf("reserved bind parameter name: %s", name)
+			}
+		}
+		bindVars[name] = value
+	}
+
+	query := "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth " + direction +
+		" @traversalStartVertex GRAPH @traversalGraphName"
+	if len(options) > 0 {
+		query += " OPTIONS {" + strings.Join(options, ", ") + "}"
+	}
+	if c.Filter != "" {
+		query += " FILTER (" + c.Filter + ")"
+	}
+	if withPath {
+		query += " RETURN {vertex: v, path: p}"
+	} else {
+		query += " RETURN {vertex: v}"
+	}
+	return createCursorConfig{Query: query, BindVars: bindVars}, nil
+}
+
+// Traverse walks the graph from startVertex and decodes the visited vertices
+// into verticesPtr and the paths to them into pathsPtr. Both must be pointers
+// to slices or nil.
+func (c *Connection) Traverse(dbName, graphName, startVertex string, config TraversalConfig, verticesPtr, pathsPtr interface{}) (rc int, err error) {
+	if err := c.requireSync("Traverse"); err != nil {
+		return 0, err
+	}
+	cursorConfig, err := config.query(graphName, startVertex, pathsPtr != nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to traverse graph: %v", err)
+	}
+
+	results, rc, err := c.query(dbName, cursorConfig)
+	if err != nil {
+		return rc, fmt.Errorf("failed to traverse graph: %v", err)
+	}
+
+	var items []struct {
+		Vertex rawValue `json:"vertex"`
+		Path   rawValue `json:"path"`
+	}
+	err = unmarshalRawValues(results, &items)
+	if err != nil {
+		return rc, fmt.Errorf("failed to decode traversal result: %v", err)
+	}
+	vertices := make([]rawValue, len(items))
+	paths := make([]rawValue, len(items))
+	for i, item := range items {
+		vertices[i] = item.Vertex
+		paths[i] = item.Path
+	}
+	if verticesPtr != nil {
+		err = unmarshalRawValues(vertices, verticesPtr)
+		if err != nil {
+			return rc, fmt.Errorf("failed to decode traversal vertices: %v", err)
+		}
+	}
+	if pathsPtr != nil {
+		err = unmarshalRawValues(paths, pathsPtr)
+		if err != nil {
+			return rc, fmt.Errorf("failed to decode traversal paths: %v", err)
+		}
+	}
+	return rc, nil
+}
@@ -0,0 +1,116 @@
+package arangogo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTraversalQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   TraversalConfig
+		withPath bool
+		query    string
+		bindVars map[string]interface{}
+	}{
+		{
+			name:   "defaults",
+			config: TraversalConfig{},
+			query:  "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth OUTBOUND @traversalStartVertex GRAPH @traversalGraphName RETURN {vertex: v}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 0, "traversalMaxDepth": 1,
+			},
+		},
+		{
+			name:     "inbound with paths",
+			config:   TraversalConfig{Direction: TraversalDirectionInbound, MinDepth: 2},
+			withPath: true,
+			query:    "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth INBOUND @traversalStartVertex GRAPH @traversalGraphName RETURN {vertex: v, path: p}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 2, "traversalMaxDepth": 2,
+			},
+		},
+		{
+			name:   "any with max depth",
+			config: TraversalConfig{Direction: TraversalDirectionAny, MinDepth: 1, MaxDepth: IntPtr(3)},
+			query:  "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth ANY @traversalStartVertex GRAPH @traversalGraphName RETURN {vertex: v}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 1, "traversalMaxDepth": 3,
+			},
+		},
+		{
+			name:   "depth 0",
+			config: TraversalConfig{MaxDepth: IntPtr(0)},
+			query:  "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth OUTBOUND @traversalStartVertex GRAPH @traversalGraphName RETURN {vertex: v}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 0, "traversalMaxDepth": 0,
+			},
+		},
+		{
+			name: "options",
+			config: TraversalConfig{
+				Order:      TraversalOrderBFS,
+				Uniqueness: TraversalUniqueness{Vertices: TraversalUniquenessGlobal, Edges: TraversalUniquenessPath},
+			},
+			query: "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth OUTBOUND @traversalStartVertex GRAPH @traversalGraphName" +
+				" OPTIONS {bfs: @traversalBfs, uniqueVertices: @traversalUniqueVertices, uniqueEdges: @traversalUniqueEdges} RETURN {vertex: v}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 0, "traversalMaxDepth": 1, "traversalBfs": true,
+				"traversalUniqueVertices": "global", "traversalUniqueEdges": "path",
+			},
+		},
+		{
+			name:   "dfs",
+			config: TraversalConfig{Order: TraversalOrderDFS},
+			query:  "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth OUTBOUND @traversalStartVertex GRAPH @traversalGraphName OPTIONS {bfs: @traversalBfs} RETURN {vertex: v}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 0, "traversalMaxDepth": 1, "traversalBfs": false,
+			},
+		},
+		{
+			name:   "filter",
+			config: TraversalConfig{Filter: "v.age >= @minAge", BindVars: map[string]interface{}{"minAge": 20}},
+			query:  "FOR v, e, p IN @traversalMinDepth..@traversalMaxDepth OUTBOUND @traversalStartVertex GRAPH @traversalGraphName FILTER (v.age >= @minAge) RETURN {vertex: v}",
+			bindVars: map[string]interface{}{
+				"traversalMinDepth": 0, "traversalMaxDepth": 1, "minAge": 20,
+			},
+		},
+	}
+	for _, tt := range tests {
+		cfg, err := tt.config.query("g", "users/alice", tt.withPath)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if cfg.Query != tt.query {
+			t.Errorf("%s: query = %s, want %s", tt.name, cfg.Query, tt.query)
+		}
+		tt.bindVars["traversalStartVertex"] = "users/alice"
+		tt.bindVars["traversalGraphName"] = "g"
+		if !reflect.DeepEqual(cfg.BindVars, tt.bindVars) {
+			t.Errorf("%s: bind vars = %v, want %v", tt.name, cfg.BindVars, tt.bindVars)
+		}
+	}
+}
+
+func TestTraversalQueryErrors(t *testing.T) {
+	tests := []struct {
+		config TraversalConfig
+		want   string
+	}{
+		{TraversalConfig{Direction: "up"}, "invalid direction: up"},
+		{TraversalConfig{MinDepth: -1}, "invalid depth range: -1..-1"},
+		{TraversalConfig{MinDepth: 2, MaxDepth: IntPtr(1)}, "invalid depth range: 2..1"},
+		{TraversalConfig{Order: "random"}, "invalid order: random"},
+		{TraversalConfig{Uniqueness: TraversalUniqueness{Vertices: "all"}}, "invalid vertices uniqueness: all"},
+		{TraversalConfig{Uniqueness: TraversalUniqueness{Edges: "all"}}, "invalid edges uniqueness: all"},
+		{TraversalConfig{BindVars: map[string]interface{}{"traversalGraphName": "other"}}, "reserved bind parameter name: traversalGraphName"},
+	}
+	for _, tt := range tests {
+		_, err := tt.config.query("g", "users/alice", false)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%+v: err = %v, want %s", tt.config, err, tt.want)
+		}
+	}
+}
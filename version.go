@@ -0,0 +1,204 @@
+package arangogo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type VersionInfo struct {
+	Server  string            `json:"server"`
+	Version string            `json:"version"`
+	License string            `json:"license"`
+	Details map[string]string `json:"details"`
+}
+
+// Number returns the version in the format of Config.ArangoVersion, which
+// is 30500 for 3.5.0. It returns 0 if the version cannot be parsed.
+func (v VersionInfo) Number() int {
+	return parseVersion(v.Version)
+}
+
+func parseVersion(s string) int {
+	if i := strings.IndexAny(s, "-+ "); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return 0
+	}
+	n := 0
+	for i := 0; i < 3; i++ {
+		var v int
+		if i < len(parts) {
+			var err error
+			v, err = strconv.Atoi(parts[i])
+			if err != nil {
+				return 0
+			}
+		}
+		n = n*100 + v
+	}
+	return n
+}
+
+func (c *Connection) Version() (v VersionInfo, rc int, err error) {
+	path := buildPath(pathConfig{
+		pathFormat:  "/_api/version",
+		queryParams: url.Values{"details": {"true"}},
+	})
+
+	rc, _, err = c.send(http.MethodGet, path, nil, nil, &v)
+	if err != nil {
+		return v, rc, fmt.Errorf("failed to get version: %v", err)
+	}
+	return v, rc, nil
+}
+
+const (
+	ServerRoleSingle      = "SINGLE"
+	ServerRoleCoordinator = "COORDINATOR"
+	ServerRolePrimary     = "PRIMARY"
+	ServerRoleSecondary   = "SECONDARY"
+	ServerRoleAgent       = "AGENT"
+	ServerRoleUndefined   = "UNDEFINED"
+)
+
+func (c *Connection) ServerRole() (role string, rc int, err error) {
+	var body struct {
+		Role string `json:"role"`
+	}
+	rc, _, err = c.send(http.MethodGet, "/_admin/server/role", nil, nil, &body)
+	if err != nil {
+		return "", rc, fmt.Errorf("failed to get server role: %v", err)
+	}
+	return body.Role, rc, nil
+}
+
+const (
+	EngineRocksDB = "rocksdb"
+	EngineMMFiles = "mmfiles"
+)
+
+type EngineInfo struct {
+	Name     string                 `json:"name"`
+	Supports map[string]interface{} `json:"supports"`
+}
+
+func (c *Connection) Engine() (e EngineInfo, rc int, err error) {
+	rc, _, err = c.send(http.MethodGet, "/_api/engine", nil, nil, &e)
+	if err != nil {
+		return e, rc, fmt.Errorf("failed to get engine: %v", err)
+	}
+	return e, rc, nil
+}
+
+// ErrUnsupportedByServer is returned when a method or an option requires a
+// newer server than the connected one. The request is not sent in this case.
+type ErrUnsupportedByServer struct {
+	Feature         string
+	RequiredVersion int
+	ServerVersion   int
+}
+
+func (e *ErrUnsupportedByServer) Error() string {
+	return fmt.Sprintf("%s is not supported by server. required=%d, server=%d", e.Feature, e.RequiredVersion, e.ServerVersion)
+}
+
+// versionRetryInterval is how long a failed detection of the server version
+// is returned to later callers before the version is detected again.
+const versionRetryInterval = 10 * time.Second
+
+// serverVersion is shared by the connections derived from the same
+// connection so that the version is detected once.
+type serverVersion struct {
+	mu      sync.Mutex
+	version int
+	// err is the error of the last detection. It is kept until failedAt plus
+	// versionRetryInterval.
+	err      error
+	failedAt time.Time
+	// detecting is closed when the running detection finishes. It is nil
+	// when no detection is running.
+	detecting chan struct{}
+}
+
+// arangoVersion returns Config.ArangoVersion, or the version detected with
+// Version if it was not set. Only one detection runs at a time, and the
+// lock is not held while it waits for the server.
+func (c *Connection) arangoVersion() (int, error) {
+	sv := c.version
+	sv.mu.Lock()
+	for sv.version == 0 && sv.detecting != nil {
+		done := sv.detecting
+		sv.mu.Unlock()
+		select {
+		case <-done:
+		case <-c.context().Done():
+			return 0, fmt.Errorf("failed to detect server version: %v", c.context().Err())
+		}
+		sv.mu.Lock()
+	}
+	if sv.version != 0 {
+		defer sv.mu.Unlock()
+		return sv.version, nil
+	}
+	if sv.err != nil && time.Since(sv.failedAt) < versionRetryInterval {
+		defer sv.mu.Unlock()
+		return 0, sv.err
+	}
+	done := make(chan struct{})
+	sv.detecting = done
+	sv.mu.Unlock()
+
+	v, err := c.detectVersion()
+
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.version = v
+	sv.err = nil
+	// The error of a canceled request is not the server's, so the next
+	// caller detects the version again.
+	if err != nil && c.context().Err() == nil {
+		sv.err = err
+		sv.failedAt = time.Now()
+	}
+	sv.detecting = nil
+	close(done)
+	return v, err
+}
+
+func (c *Connection) detectVersion() (int, error) {
+	// Detect with a plain connection so that the request is not put in a
+	// batch nor sent asynchronously.
+	v, _, err := c.plain().Version()
+	if err != nil {
+		return 0, fmt.Errorf("failed to detect server version: %v", err)
+	}
+	n := v.Number()
+	if n == 0 {
+		return 0, fmt.Errorf("failed to detect server version: invalid version %q", v.Version)
+	}
+	return n, nil
+}
+
+// requireVersion returns an *ErrUnsupportedByServer if the server is older
+// than version.
+func (c *Connection) requireVersion(feature string, version int) error {
+	v, err := c.arangoVersion()
+	if err != nil {
+		return err
+	}
+	if v < version {
+		return &ErrUnsupportedByServer{
+			Feature:         feature,
+			RequiredVersion: version,
+			ServerVersion:   v,
+		}
+	}
+	return nil
+}
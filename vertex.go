@@ -1,6 +1,7 @@
 package arangogo
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,6 +16,7 @@ type CreateVertexResult struct {
 
 type CreateVertexConfig struct {
 	WaitForSync *bool
+	ReturnNew   *bool
 }
 
 func (c *CreateVertexConfig) urlValues() url.Values {
@@ -23,14 +25,19 @@ func (c *CreateVertexConfig) urlValues() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnNew != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
 	return params
 }
 
-func (c *Connection) CreateVertex(dbName, graphName, collName string, data interface{}, config *CreateVertexConfig) (r CreateVertexResult, rc int, err error) {
+func (c *Connection) CreateVertex(dbName, graphName, collName string, data interface{}, config *CreateVertexConfig, newVertexPtr interface{}) (r CreateVertexResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/vertex/%s",
@@ -40,6 +47,10 @@ func (c *Connection) CreateVertex(dbName, graphName, collName string, data inter
 
 	var body struct {
 		Vertex CreateVertexResult `json:"vertex"`
+		New    interface{}        `json:"new"`
+	}
+	if newVertexPtr != nil {
+		body.New = newVertexPtr
 	}
 	rc, _, err = c.send(http.MethodPost, path, nil, data, &body)
 	if err != nil {
@@ -49,7 +60,8 @@ func (c *Connection) CreateVertex(dbName, graphName, collName string, data inter
 }
 
 type GetVertexConfig struct {
-	IfMatch string
+	IfNoneMatch string
+	IfMatch     string
 }
 
 func (c *GetVertexConfig) header() http.Header {
@@ -57,33 +69,71 @@ func (c *GetVertexConfig) header() http.Header {
 		return nil
 	}
 	var header http.Header
-	if c.IfMatch != "" {
+	if c.IfNoneMatch != "" || c.IfMatch != "" {
 		header = make(http.Header)
+	}
+	if c.IfNoneMatch != "" {
+		header.Set("if-none-match", c.IfNoneMatch)
+	}
+	if c.IfMatch != "" {
 		header.Set("if-match", c.IfMatch)
-		return header
 	}
+	return header
+}
 
-	return nil
+type GetVertexResult struct {
+	Rev string
+	// NotModified is true when the vertex revision matched IfNoneMatch.
+	// The vertex is not decoded in this case.
+	NotModified bool
+	// PreconditionFailed is true when the vertex revision did not match IfMatch.
+	PreconditionFailed bool
 }
 
-func (c *Connection) GetVertex(dbName, graphName, collName, vertexKey string, config *GetVertexConfig, vertexPtr interface{}) (rc int, err error) {
+func (c *Connection) GetVertex(dbName, graphName, collName, vertexKey string, config *GetVertexConfig, vertexPtr interface{}) (r GetVertexResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:     dbName,
 		pathFormat: "/_api/gharial/%s/vertex/%s/%s",
 		pathParams: []interface{}{graphName, collName, vertexKey},
 	})
 
+	if c.useCache(config.header()) {
+		r.Rev, rc, err = c.readThroughCache(documentCacheKey(dbName, collName+"/"+vertexKey), path,
+			func(b json.RawMessage) (json.RawMessage, error) {
+				var body struct {
+					Vertex json.RawMessage `json:"vertex"`
+				}
+				err := json.Unmarshal(b, &body)
+				return body.Vertex, err
+			}, vertexPtr)
+		if err != nil {
+			return r, rc, fmt.Errorf("failed to get vertex: %v", err)
+		}
+		return r, rc, nil
+	}
+
 	var body struct {
 		Vertex interface{} `json:"vertex"`
 	}
 	if vertexPtr != nil {
 		body.Vertex = vertexPtr
 	}
-	rc, _, err = c.send(http.MethodGet, path, config.header(), nil, &body)
+	rc, resp, err := c.send(http.MethodGet, path, config.header(), nil, &body)
+	if resp != nil {
+		r.Rev = revFromETag(resp.Header.Get("ETag"))
+	}
+	switch rc {
+	case http.StatusNotModified:
+		r.NotModified = true
+		return r, rc, nil
+	case http.StatusPreconditionFailed:
+		r.PreconditionFailed = true
+		return r, rc, nil
+	}
 	if err != nil {
-		return rc, fmt.Errorf("failed to get vertex: %v", err)
+		return r, rc, fmt.Errorf("failed to get vertex: %v", err)
 	}
-	return rc, nil
+	return r, rc, nil
 }
 
 type ModifyVertexResult struct {
@@ -96,6 +146,8 @@ type ModifyVertexResult struct {
 type ModifyVertexConfig struct {
 	WaitForSync *bool
 	KeepNull    *bool
+	ReturnOld   *bool
+	ReturnNew   *bool
 	IfMatch     string
 }
 
@@ -119,7 +171,7 @@ func (c *ModifyVertexConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil || c.KeepNull != nil {
+	if c.WaitForSync != nil || c.KeepNull != nil || c.ReturnOld != nil || c.ReturnNew != nil {
 		params = make(url.Values)
 	}
 	if c.WaitForSync != nil {
@@ -128,10 +180,16 @@ func (c *ModifyVertexConfig) queryParams() url.Values {
 	if c.KeepNull != nil {
 		params.Set("keepNull", strconv.FormatBool(*c.KeepNull))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
 	return params
 }
 
-func (c *Connection) ModifyVertex(dbName, graphName, collName, vertexKey string, data interface{}, config *ModifyVertexConfig) (r ModifyVertexResult, rc int, err error) {
+func (c *Connection) ModifyVertex(dbName, graphName, collName, vertexKey string, data interface{}, config *ModifyVertexConfig, oldVertexPtr, newVertexPtr interface{}) (r ModifyVertexResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/vertex/%s/%s",
@@ -141,8 +199,17 @@ func (c *Connection) ModifyVertex(dbName, graphName, collName, vertexKey string,
 
 	var body struct {
 		Vertex ModifyVertexResult `json:"vertex"`
+		Old    interface{}        `json:"old"`
+		New    interface{}        `json:"new"`
+	}
+	if oldVertexPtr != nil {
+		body.Old = oldVertexPtr
+	}
+	if newVertexPtr != nil {
+		body.New = newVertexPtr
 	}
 	rc, _, err = c.send(http.MethodPatch, path, config.header(), data, &body)
+	c.invalidateDocument(dbName, collName+"/"+vertexKey)
 	if err != nil {
 		return body.Vertex, rc, fmt.Errorf("failed to modify vertex: %v", err)
 	}
@@ -158,6 +225,8 @@ type ReplaceVertexResult struct {
 
 type ReplaceVertexConfig struct {
 	WaitForSync *bool
+	ReturnOld   *bool
+	ReturnNew   *bool
 	IfMatch     string
 }
 
@@ -181,14 +250,22 @@ func (c *ReplaceVertexConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnOld != nil || c.ReturnNew != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
+	if c.ReturnNew != nil {
+		params.Set("returnNew", strconv.FormatBool(*c.ReturnNew))
+	}
 	return params
 }
 
-func (c *Connection) ReplaceVertex(dbName, graphName, collName, vertexKey string, data interface{}, config *ReplaceVertexConfig) (r ReplaceVertexResult, rc int, err error) {
+func (c *Connection) ReplaceVertex(dbName, graphName, collName, vertexKey string, data interface{}, config *ReplaceVertexConfig, oldVertexPtr, newVertexPtr interface{}) (r ReplaceVertexResult, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/vertex/%s/%s",
@@ -198,8 +275,17 @@ func (c *Connection) ReplaceVertex(dbName, graphName, collName, vertexKey string
 
 	var body struct {
 		Vertex ReplaceVertexResult `json:"vertex"`
+		Old    interface{}         `json:"old"`
+		New    interface{}         `json:"new"`
+	}
+	if oldVertexPtr != nil {
+		body.Old = oldVertexPtr
+	}
+	if newVertexPtr != nil {
+		body.New = newVertexPtr
 	}
 	rc, _, err = c.send(http.MethodPut, path, config.header(), data, &body)
+	c.invalidateDocument(dbName, collName+"/"+vertexKey)
 	if err != nil {
 		return body.Vertex, rc, fmt.Errorf("failed to replace vertex: %v", err)
 	}
@@ -208,6 +294,7 @@ func (c *Connection) ReplaceVertex(dbName, graphName, collName, vertexKey string
 
 type RemoveVertexConfig struct {
 	WaitForSync *bool
+	ReturnOld   *bool
 	IfMatch     string
 }
 
@@ -231,14 +318,19 @@ func (c *RemoveVertexConfig) queryParams() url.Values {
 	}
 
 	var params url.Values
-	if c.WaitForSync != nil {
+	if c.WaitForSync != nil || c.ReturnOld != nil {
 		params = make(url.Values)
+	}
+	if c.WaitForSync != nil {
 		params.Set("waitForSync", strconv.FormatBool(*c.WaitForSync))
 	}
+	if c.ReturnOld != nil {
+		params.Set("returnOld", strconv.FormatBool(*c.ReturnOld))
+	}
 	return params
 }
 
-func (c *Connection) RemoveVertex(dbName, graphName, collName, vertexKey string, config *RemoveVertexConfig) (removed bool, rc int, err error) {
+func (c *Connection) RemoveVertex(dbName, graphName, collName, vertexKey string, config *RemoveVertexConfig, oldVertexPtr interface{}) (removed bool, rc int, err error) {
 	path := buildPath(pathConfig{
 		dbName:      dbName,
 		pathFormat:  "/_api/gharial/%s/vertex/%s/%s",
@@ -247,9 +339,19 @@ func (c *Connection) RemoveVertex(dbName, graphName, collName, vertexKey string,
 	})
 
 	var body struct {
-		Removed bool `json:"removed"`
+		Removed bool        `json:"removed"`
+		Old     interface{} `json:"old"`
+	}
+	if oldVertexPtr != nil {
+		body.Old = oldVertexPtr
 	}
 	rc, _, err = c.send(http.MethodDelete, path, config.header(), nil, &body)
+	c.invalidateDocument(dbName, collName+"/"+vertexKey)
+	if c.cache != nil {
+		// The server also removes the edges connected to the vertex.
+		g, graphRead := c.readGraphForCache(dbName, graphName)
+		c.invalidateGraphCollections(dbName, g.edgeCollectionsOf(collName), graphRead)
+	}
 	if err != nil {
 		return body.Removed, rc, fmt.Errorf("failed to remove vertex: %v", err)
 	}
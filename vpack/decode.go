@@ -0,0 +1,499 @@
+package vpack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Unmarshaler is implemented by types which decode VelocyPack themselves.
+// UnmarshalVPack must copy data to keep it.
+type Unmarshaler interface {
+	UnmarshalVPack(data []byte) error
+}
+
+// UnmarshalTypeError describes a VelocyPack value which cannot be stored in
+// a Go value of the type.
+type UnmarshalTypeError struct {
+	Value string
+	Type  reflect.Type
+	// Field is the struct and the field name like Doc.name.
+	Field string
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("vpack: cannot unmarshal %s into Go struct field %s of type %s", e.Value, e.Field, e.Type)
+	}
+	return fmt.Sprintf("vpack: cannot unmarshal %s into Go value of type %s", e.Value, e.Type)
+}
+
+func typeError(h byte, t reflect.Type) error {
+	return &UnmarshalTypeError{Value: typeName(h), Type: t}
+}
+
+// Unmarshal decodes the VelocyPack value data into the value pointed to by
+// v. Numbers are decoded into float64 in interface values like encoding/json
+// does.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("vpack: Unmarshal(non-pointer %T)", v)
+	}
+	size, err := byteSize(data)
+	if err != nil {
+		return err
+	}
+	if size != len(data) {
+		return errors.New("vpack: invalid data after top-level value")
+	}
+	return decodeValue(data, rv, false)
+}
+
+var errNonEmptyInterface = errors.New("vpack: cannot unmarshal into non-empty interface")
+
+// indirect walks down v allocating pointers as needed until it gets to a
+// non-pointer like encoding/json does. It stops at an Unmarshaler or a
+// json.Unmarshaler and returns it. If decodingNull is set, it stops at the
+// last pointer so that it can be set to nil.
+func indirect(v reflect.Value, decodingNull bool) (interface{}, reflect.Value) {
+	if v.Kind() != reflect.Ptr && v.Type().Name() != "" && v.CanAddr() {
+		v = v.Addr()
+	}
+	for {
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Ptr && !e.IsNil() && (!decodingNull || e.Elem().Kind() == reflect.Ptr) {
+				v = e
+				continue
+			}
+		}
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		if decodingNull && v.CanSet() {
+			break
+		}
+		if v.Elem().Kind() == reflect.Interface && v.Elem().Elem() == v {
+			v = v.Elem()
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 && v.CanInterface() {
+			switch u := v.Interface().(type) {
+			case Unmarshaler:
+				return u, reflect.Value{}
+			case json.Unmarshaler:
+				return u, reflect.Value{}
+			}
+		}
+		v = v.Elem()
+	}
+	return nil, v
+}
+
+func decodeValue(b []byte, v reflect.Value, quoted bool) error {
+	h := b[0]
+	u, v := indirect(v, isNull(h))
+	switch u := u.(type) {
+	case Unmarshaler:
+		return u.UnmarshalVPack(b)
+	case json.Unmarshaler:
+		j, err := ToJSON(b)
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalJSON(j)
+	}
+
+	switch {
+	case isNull(h):
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+
+	case h == typeFalse || h == typeTrue:
+		b := h == typeTrue
+		switch v.Kind() {
+		case reflect.Bool:
+			v.SetBool(b)
+		case reflect.Interface:
+			if v.NumMethod() != 0 {
+				return errNonEmptyInterface
+			}
+			v.Set(reflect.ValueOf(b))
+		default:
+			return typeError(h, v.Type())
+		}
+		return nil
+
+	case isNumber(h):
+		return decodeNumber(b, v)
+
+	case h >= typeShortString && h <= typeLongString:
+		s := b[1:]
+		if h == typeLongString {
+			s = b[9:]
+		}
+		if quoted {
+			return decodeQuoted(h, string(s), v)
+		}
+		switch v.Kind() {
+		case reflect.String:
+			if v.Type() == numberType {
+				if _, err := strconv.ParseFloat(string(s), 64); err != nil {
+					return fmt.Errorf("vpack: invalid number literal %q", s)
+				}
+			}
+			v.SetString(string(s))
+		case reflect.Slice:
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				return typeError(h, v.Type())
+			}
+			d := make([]byte, base64.StdEncoding.DecodedLen(len(s)))
+			n, err := base64.StdEncoding.Decode(d, s)
+			if err != nil {
+				return fmt.Errorf("vpack: invalid base64 string: %v", err)
+			}
+			v.SetBytes(d[:n])
+		case reflect.Interface:
+			if v.NumMethod() != 0 {
+				return errNonEmptyInterface
+			}
+			v.Set(reflect.ValueOf(string(s)))
+		default:
+			return typeError(h, v.Type())
+		}
+		return nil
+
+	case h >= typeBinary1 && h <= 0xc7:
+		d, _ := binaryValue(b)
+		switch {
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetBytes(append([]byte(nil), d...))
+		case v.Kind() == reflect.String:
+			v.SetString(string(d))
+		case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+			v.Set(reflect.ValueOf(append([]byte(nil), d...)))
+		default:
+			return typeError(h, v.Type())
+		}
+		return nil
+
+	case isArray(h):
+		return decodeArray(b, v)
+
+	case isObject(h):
+		return decodeObject(b, v)
+	}
+	return fmt.Errorf("vpack: unsupported type 0x%02x", h)
+}
+
+func decodeNumber(b []byte, v reflect.Value) error {
+	h := b[0]
+	i, isInt := intValue(b)
+	u, isUint := uintValue(b)
+	f, isDouble := doubleValue(b)
+	switch {
+	case isInt:
+		f = float64(i)
+	case isUint:
+		f = float64(u)
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case isUint && !isInt:
+			if u > math.MaxInt64 {
+				return typeError(h, v.Type())
+			}
+			i = int64(u)
+		case isDouble:
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return typeError(h, v.Type())
+			}
+			i = int64(f)
+		}
+		if v.OverflowInt(i) {
+			return typeError(h, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch {
+		case isInt:
+			if i < 0 {
+				return typeError(h, v.Type())
+			}
+			u = uint64(i)
+		case isDouble:
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return typeError(h, v.Type())
+			}
+			u = uint64(f)
+		}
+		if v.OverflowUint(u) {
+			return typeError(h, v.Type())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if v.OverflowFloat(f) {
+			return typeError(h, v.Type())
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		if v.Type() != numberType {
+			return typeError(h, v.Type())
+		}
+		switch {
+		case isInt:
+			v.SetString(strconv.FormatInt(i, 10))
+		case isUint:
+			v.SetString(strconv.FormatUint(u, 10))
+		default:
+			v.SetString(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return errNonEmptyInterface
+		}
+		v.Set(reflect.ValueOf(f))
+	default:
+		return typeError(h, v.Type())
+	}
+	return nil
+}
+
+// decodeQuoted decodes a bool or a number in the string s for a field with
+// the string option.
+func decodeQuoted(h byte, s string, v reflect.Value) error {
+	var err error
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return typeError(h, v.Type())
+	}
+	if err != nil {
+		return &UnmarshalTypeError{Value: "string " + strconv.Quote(s), Type: v.Type()}
+	}
+	return nil
+}
+
+func decodeArray(b []byte, v reflect.Value) error {
+	it, err := newIterator(b, false)
+	if err != nil {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return errNonEmptyInterface
+		}
+		a := make([]interface{}, it.n)
+		for i := range a {
+			_, item, _, err := it.next()
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(item, reflect.ValueOf(&a[i]).Elem(), false); err != nil {
+				return err
+			}
+		}
+		v.Set(reflect.ValueOf(a))
+		return nil
+	case reflect.Slice:
+		if v.Cap() >= it.n {
+			v.SetLen(it.n)
+		} else {
+			v.Set(reflect.MakeSlice(v.Type(), it.n, it.n))
+		}
+		for i := 0; i < it.n; i++ {
+			_, item, _, err := it.next()
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(item, v.Index(i), false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			_, item, ok, err := it.next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+				continue
+			}
+			if err := decodeValue(item, v.Index(i), false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return typeError(b[0], v.Type())
+}
+
+func decodeObject(b []byte, v reflect.Value) error {
+	it, err := newIterator(b, false)
+	if err != nil {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return errNonEmptyInterface
+		}
+		m := make(map[string]interface{}, it.n)
+		for {
+			key, item, ok, err := it.next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			k, err := keyBytes(key)
+			if err != nil {
+				return err
+			}
+			var e interface{}
+			if err := decodeValue(item, reflect.ValueOf(&e).Elem(), false); err != nil {
+				return err
+			}
+			m[string(k)] = e
+		}
+		v.Set(reflect.ValueOf(m))
+		return nil
+
+	case reflect.Map:
+		t := v.Type()
+		kt := t.Key()
+		switch kt.Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return typeError(b[0], t)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, it.n))
+		}
+		for {
+			key, item, ok, err := it.next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			k, err := keyBytes(key)
+			if err != nil {
+				return err
+			}
+			e := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(item, e, false); err != nil {
+				return err
+			}
+			kv := reflect.New(kt).Elem()
+			switch kt.Kind() {
+			case reflect.String:
+				kv.SetString(string(k))
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n, err := strconv.ParseInt(string(k), 10, kt.Bits())
+				if err != nil {
+					return &UnmarshalTypeError{Value: "number " + string(k), Type: kt}
+				}
+				kv.SetInt(n)
+			default:
+				n, err := strconv.ParseUint(string(k), 10, kt.Bits())
+				if err != nil {
+					return &UnmarshalTypeError{Value: "number " + string(k), Type: kt}
+				}
+				kv.SetUint(n)
+			}
+			v.SetMapIndex(kv, e)
+		}
+		return nil
+
+	case reflect.Struct:
+		fields := cachedFields(v.Type())
+		for {
+			key, item, ok, err := it.next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			k, err := keyBytes(key)
+			if err != nil {
+				return err
+			}
+			f := fields.lookup(k)
+			if f == nil {
+				continue
+			}
+			fv, ok := settableField(v, f.index)
+			if !ok {
+				continue
+			}
+			if err := decodeValue(item, fv, f.quoted); err != nil {
+				if te, ok := err.(*UnmarshalTypeError); ok && te.Field == "" {
+					te.Field = v.Type().Name() + "." + f.name
+				}
+				return err
+			}
+		}
+		return nil
+	}
+	return typeError(b[0], v.Type())
+}
+
+// settableField returns the field of v with the index allocating nil
+// pointers to embedded structs. ok is false if the pointer cannot be set
+// because the embedded struct is not exported.
+func settableField(v reflect.Value, index []int) (fv reflect.Value, ok bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,387 @@
+package vpack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Marshaler is implemented by types which encode VelocyPack themselves.
+type Marshaler interface {
+	MarshalVPack() ([]byte, error)
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	numberType        = reflect.TypeOf(json.Number(""))
+)
+
+// Marshal returns the VelocyPack encoding of v. Objects are written with
+// sorted index tables as ArangoDB does.
+func Marshal(v interface{}) ([]byte, error) {
+	return appendValue(nil, reflect.ValueOf(v), false)
+}
+
+func appendValue(dst []byte, v reflect.Value, quoted bool) ([]byte, error) {
+	if !v.IsValid() {
+		return append(dst, typeNull), nil
+	}
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(dst, typeNull), nil
+		}
+		return appendValue(dst, v.Elem(), quoted)
+	}
+
+	t := v.Type()
+	if t.Kind() != reflect.Ptr && v.CanAddr() {
+		if pt := reflect.PtrTo(t); pt.Implements(marshalerType) || pt.Implements(jsonMarshalerType) {
+			v = v.Addr()
+			t = pt
+		}
+	}
+	if t.Implements(marshalerType) || t.Implements(jsonMarshalerType) {
+		if t.Kind() == reflect.Ptr && v.IsNil() {
+			return append(dst, typeNull), nil
+		}
+		if m, ok := v.Interface().(Marshaler); ok {
+			b, err := m.MarshalVPack()
+			if err != nil {
+				return nil, fmt.Errorf("vpack: failed to marshal %s: %v", t, err)
+			}
+			if size, err := byteSize(b); err != nil || size != len(b) {
+				return nil, fmt.Errorf("vpack: invalid VelocyPack from %s", t)
+			}
+			return append(dst, b...), nil
+		}
+		b, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("vpack: failed to marshal %s: %v", t, err)
+		}
+		return appendJSONValue(dst, b)
+	}
+	if t == numberType {
+		return appendNumber(dst, json.Number(v.String()))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if quoted {
+			return appendString(dst, strconv.FormatBool(v.Bool())), nil
+		}
+		if v.Bool() {
+			return append(dst, typeTrue), nil
+		}
+		return append(dst, typeFalse), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if quoted {
+			return appendString(dst, strconv.FormatInt(v.Int(), 10)), nil
+		}
+		return appendInt(dst, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if quoted {
+			return appendString(dst, strconv.FormatUint(v.Uint(), 10)), nil
+		}
+		return appendUint(dst, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("vpack: unsupported value: %v", f)
+		}
+		if quoted {
+			return appendString(dst, strconv.FormatFloat(f, 'g', -1, t.Bits())), nil
+		}
+		return appendDouble(dst, f), nil
+	case reflect.String:
+		return appendString(dst, v.String()), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return append(dst, typeNull), nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !reflect.PtrTo(t.Elem()).Implements(jsonMarshalerType) {
+			// Byte slices are base64 strings like in JSON so that
+			// documents are the same with both codecs.
+			return appendString(dst, base64.StdEncoding.EncodeToString(v.Bytes())), nil
+		}
+		return appendArray(dst, v)
+	case reflect.Array:
+		return appendArray(dst, v)
+	case reflect.Map:
+		if v.IsNil() {
+			return append(dst, typeNull), nil
+		}
+		return appendMap(dst, v)
+	case reflect.Struct:
+		return appendStruct(dst, v)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return append(dst, typeNull), nil
+		}
+		return appendValue(dst, v.Elem(), quoted)
+	}
+	return nil, fmt.Errorf("vpack: unsupported type: %s", t)
+}
+
+func appendInt(dst []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= 9:
+		return append(dst, typeSmallInt0+byte(i))
+	case i >= -6 && i < 0:
+		return append(dst, byte(typeShortString+i))
+	}
+	n := 1
+	for n < 8 && (i < -(1<<(8*uint(n)-1)) || i >= 1<<(8*uint(n)-1)) {
+		n++
+	}
+	dst = append(dst, typeInt1+byte(n-1))
+	return appendLE(dst, uint64(i), n)
+}
+
+func appendUint(dst []byte, u uint64) []byte {
+	if u <= math.MaxInt64 {
+		return appendInt(dst, int64(u))
+	}
+	dst = append(dst, typeUint1+7)
+	return appendLE(dst, u, 8)
+}
+
+func appendDouble(dst []byte, f float64) []byte {
+	dst = append(dst, typeDouble)
+	return appendLE(dst, math.Float64bits(f), 8)
+}
+
+func appendNumber(dst []byte, n json.Number) ([]byte, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return appendInt(dst, i), nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return appendUint(dst, u), nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return nil, fmt.Errorf("vpack: invalid number %q", n)
+	}
+	return appendDouble(dst, f), nil
+}
+
+func appendString(dst []byte, s string) []byte {
+	if len(s) <= maxShortStringLen {
+		dst = append(dst, typeShortString+byte(len(s)))
+	} else {
+		dst = append(dst, typeLongString)
+		dst = appendLE(dst, uint64(len(s)), 8)
+	}
+	return append(dst, s...)
+}
+
+func appendLE(dst []byte, v uint64, n int) []byte {
+	for i := 0; i < n; i++ {
+		dst = append(dst, byte(v))
+		v >>= 8
+	}
+	return dst
+}
+
+// compoundHeaderSize is the room reserved for the header of an array or an
+// object while its items are written. It is the header size with 8 byte
+// widths, and the items are moved down when the header is smaller.
+const compoundHeaderSize = 9
+
+func appendArray(dst []byte, v reflect.Value) ([]byte, error) {
+	n := v.Len()
+	if n == 0 {
+		return append(dst, typeEmptyArray), nil
+	}
+	start := len(dst)
+	dst = append(dst, make([]byte, compoundHeaderSize)...)
+	offsets := make([]int, n)
+	for i := 0; i < n; i++ {
+		offsets[i] = len(dst) - start
+		var err error
+		dst, err = appendValue(dst, v.Index(i), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return closeCompound(dst, start, offsets, typeArray1), nil
+}
+
+// member is an attribute of an object being written.
+type member struct {
+	key    string
+	offset int
+}
+
+func appendMap(dst []byte, v reflect.Value) ([]byte, error) {
+	if v.Len() == 0 {
+		return append(dst, typeEmptyObject), nil
+	}
+	members := make([]member, 0, v.Len())
+	values := make([]reflect.Value, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		var key string
+		switch k.Kind() {
+		case reflect.String:
+			key = k.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			key = strconv.FormatInt(k.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return nil, fmt.Errorf("vpack: unsupported map key type: %s", k.Type())
+		}
+		members = append(members, member{key: key})
+		values = append(values, iter.Value())
+	}
+	// Write the attributes in key order so that the output is stable.
+	order := make([]int, len(members))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return members[order[i]].key < members[order[j]].key
+	})
+
+	start := len(dst)
+	dst = append(dst, make([]byte, compoundHeaderSize)...)
+	sorted := make([]member, 0, len(members))
+	for _, i := range order {
+		m := members[i]
+		m.offset = len(dst) - start
+		dst = appendString(dst, m.key)
+		var err error
+		dst, err = appendValue(dst, values[i], false)
+		if err != nil {
+			return nil, err
+		}
+		sorted = append(sorted, m)
+	}
+	return closeObject(dst, start, sorted), nil
+}
+
+func appendStruct(dst []byte, v reflect.Value) ([]byte, error) {
+	fields := cachedFields(v.Type()).list
+	start := len(dst)
+	dst = append(dst, make([]byte, compoundHeaderSize)...)
+	members := make([]member, 0, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		members = append(members, member{key: f.name, offset: len(dst) - start})
+		dst = appendString(dst, f.name)
+		var err error
+		dst, err = appendValue(dst, fv, f.quoted)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(members) == 0 {
+		return append(dst[:start], typeEmptyObject), nil
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].key < members[j].key
+	})
+	return closeObject(dst, start, members), nil
+}
+
+// fieldByIndex returns the field of v with the index. ok is false if the
+// field is in an embedded struct through a nil pointer.
+func fieldByIndex(v reflect.Value, index []int) (fv reflect.Value, ok bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func closeObject(dst []byte, start int, members []member) []byte {
+	offsets := make([]int, len(members))
+	for i, m := range members {
+		offsets[i] = m.offset
+	}
+	return closeCompound(dst, start, offsets, typeObject1)
+}
+
+// closeCompound writes the header and the index table of the array or the
+// object whose items follow the room reserved at start. It uses the
+// narrowest width the byte length fits in.
+func closeCompound(dst []byte, start int, offsets []int, head byte) []byte {
+	n := len(offsets)
+	bodyLen := len(dst) - start - compoundHeaderSize
+	for log := 0; ; log++ {
+		w := 1 << uint(log)
+		hs, tail := 1+2*w, 0
+		if w == 8 {
+			hs, tail = compoundHeaderSize, 8
+		}
+		total := hs + bodyLen + n*w + tail
+		if w < 8 && uint64(total) >= 1<<(8*uint(w)) {
+			continue
+		}
+
+		copy(dst[start+hs:], dst[start+compoundHeaderSize:])
+		dst = dst[:start+hs+bodyLen]
+		dst[start] = head + byte(log)
+		putUint(dst[start+1:], uint64(total), w)
+		if w < 8 {
+			putUint(dst[start+1+w:], uint64(n), w)
+		}
+		for _, off := range offsets {
+			dst = appendLE(dst, uint64(off-compoundHeaderSize+hs), w)
+		}
+		if w == 8 {
+			dst = appendLE(dst, uint64(n), 8)
+		}
+		return dst
+	}
+}
+
+// appendJSONValue appends the VelocyPack encoding of the JSON value b.
+func appendJSONValue(dst []byte, b []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("vpack: invalid JSON: %v", err)
+	}
+	return appendValue(dst, reflect.ValueOf(v), false)
+}
+
+// FromJSON converts the JSON value b to VelocyPack.
+func FromJSON(b []byte) ([]byte, error) {
+	return appendJSONValue(nil, b)
+}
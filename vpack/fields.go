@@ -0,0 +1,218 @@
+package vpack
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a struct field with the json tag options applied.
+type field struct {
+	name      string
+	nameBytes []byte
+	tagged    bool
+	index     []int
+	typ       reflect.Type
+	omitEmpty bool
+	// quoted is set for the string option, which puts bools and numbers in
+	// strings.
+	quoted bool
+}
+
+type structFields struct {
+	list   []field
+	byName map[string]int
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+func cachedFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	list := typeFields(t)
+	f := &structFields{list: list, byName: make(map[string]int, len(list))}
+	for i := range list {
+		list[i].nameBytes = []byte(list[i].name)
+		f.byName[list[i].name] = i
+	}
+	v, _ := fieldCache.LoadOrStore(t, f)
+	return v.(*structFields)
+}
+
+// lookup returns the field for the key preferring an exact match like
+// encoding/json does.
+func (f *structFields) lookup(key []byte) *field {
+	if i, ok := f.byName[string(key)]; ok {
+		return &f.list[i]
+	}
+	for i := range f.list {
+		if bytes.EqualFold(f.list[i].nameBytes, key) {
+			return &f.list[i]
+		}
+	}
+	return nil
+}
+
+// typeFields returns the fields of t following the rules of encoding/json:
+// the fields of embedded structs are promoted, and among fields with the
+// same name the shallowest one wins, then the tagged one. The name is
+// dropped if there is still a tie.
+func typeFields(t reflect.Type) []field {
+	var current []field
+	next := []field{{typ: t}}
+	var count, nextCount map[reflect.Type]int
+	visited := map[reflect.Type]bool{}
+	var fields []field
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if sf.PkgPath != "" {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					quoted := false
+					if hasOption(opts, "string") {
+						switch ft.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64:
+							quoted = true
+						}
+					}
+					fi := field{
+						name:      name,
+						tagged:    name != "",
+						index:     index,
+						typ:       ft,
+						omitEmpty: hasOption(opts, "omitempty"),
+						quoted:    quoted,
+					}
+					if fi.name == "" {
+						fi.name = sf.Name
+					}
+					fields = append(fields, fi)
+					if count[f.typ] > 1 {
+						// The struct is embedded more than once at this
+						// depth, so the field conflicts with itself.
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, field{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x := fields
+		if x[i].name != x[j].name {
+			return x[i].name < x[j].name
+		}
+		if len(x[i].index) != len(x[j].index) {
+			return len(x[i].index) < len(x[j].index)
+		}
+		if x[i].tagged != x[j].tagged {
+			return x[i].tagged
+		}
+		return lessIndex(x[i].index, x[j].index)
+	})
+
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fi.name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		dup := fields[i+1]
+		if len(fi.index) == len(dup.index) && fi.tagged == dup.tagged {
+			continue
+		}
+		out = append(out, fi)
+	}
+
+	fields = out
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	return fields
+}
+
+func lessIndex(a, b []int) bool {
+	for k, xik := range a {
+		if k >= len(b) {
+			return false
+		}
+		if xik != b[k] {
+			return xik < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func parseTag(tag string) (name, opts string) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var o string
+		if i := strings.Index(opts, ","); i >= 0 {
+			o, opts = opts[:i], opts[i+1:]
+		} else {
+			o, opts = opts, ""
+		}
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package vpack
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ToJSON converts the VelocyPack value b to JSON. Binary values are written
+// as base64 strings and dates as milliseconds since the epoch.
+func ToJSON(b []byte) ([]byte, error) {
+	size, err := byteSize(b)
+	if err != nil {
+		return nil, err
+	}
+	return appendJSON(make([]byte, 0, size+size/2), b[:size])
+}
+
+func appendJSON(dst, b []byte) ([]byte, error) {
+	h := b[0]
+	switch {
+	case isNull(h):
+		return append(dst, "null"...), nil
+	case h == typeFalse:
+		return append(dst, "false"...), nil
+	case h == typeTrue:
+		return append(dst, "true"...), nil
+	case h == typeDouble:
+		f, _ := doubleValue(b)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("vpack: unsupported value: %v", f)
+		}
+		return strconv.AppendFloat(dst, f, 'g', -1, 64), nil
+	case isNumber(h):
+		if i, ok := intValue(b); ok {
+			return strconv.AppendInt(dst, i, 10), nil
+		}
+		u, _ := uintValue(b)
+		return strconv.AppendUint(dst, u, 10), nil
+	case h >= typeShortString && h <= typeLongString:
+		s, _ := stringValue(b)
+		return appendJSONString(dst, s), nil
+	case h >= typeBinary1 && h <= 0xc7:
+		d, _ := binaryValue(b)
+		return appendJSONString(dst, base64.StdEncoding.EncodeToString(d)), nil
+	case isArray(h) || isObject(h):
+		it, err := newIterator(b, true)
+		if err != nil {
+			return nil, err
+		}
+		open, close := byte('['), byte(']')
+		if it.object {
+			open, close = '{', '}'
+		}
+		dst = append(dst, open)
+		for i := 0; ; i++ {
+			key, value, ok, err := it.next()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if it.object {
+				k, err := keyBytes(key)
+				if err != nil {
+					return nil, err
+				}
+				dst = appendJSONString(dst, string(k))
+				dst = append(dst, ':')
+			}
+			if dst, err = appendJSON(dst, value); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, close), nil
+	}
+	return nil, fmt.Errorf("vpack: unsupported type 0x%02x", h)
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				dst = append(dst, '\\', c)
+			case c == '\n':
+				dst = append(dst, '\\', 'n')
+			case c == '\r':
+				dst = append(dst, '\\', 'r')
+			case c == '\t':
+				dst = append(dst, '\\', 't')
+			case c < 0x20:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			default:
+				dst = append(dst, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, `�`...)
+		} else {
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(dst, '"')
+}
@@ -0,0 +1,417 @@
+// Package vpack implements VelocyPack, the binary format ArangoDB stores
+// documents in and accepts as application/x-velocypack over HTTP.
+//
+// Marshal and Unmarshal convert Go values like encoding/json does, using the
+// json struct tags, so the same types can be sent in both formats. Values
+// implementing json.Marshaler or json.Unmarshaler, like json.RawMessage and
+// time.Time, are converted through JSON.
+//
+// BCD numbers, tagged values and custom types are not supported.
+package vpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ContentType is the media type of VelocyPack bodies.
+const ContentType = "application/x-velocypack"
+
+// Type bytes. Types whose size is in the type byte, like integers and short
+// strings, are the first of their ranges.
+const (
+	typeNone        = 0x00
+	typeEmptyArray  = 0x01
+	typeArray1      = 0x06
+	typeEmptyObject = 0x0a
+	typeObject1     = 0x0b
+	typeNull        = 0x18
+	typeFalse       = 0x19
+	typeTrue        = 0x1a
+	typeDouble      = 0x1b
+	typeUTCDate     = 0x1c
+	typeMinKey      = 0x1e
+	typeMaxKey      = 0x1f
+	typeInt1        = 0x20
+	typeUint1       = 0x28
+	typeSmallInt0   = 0x30
+	typeShortString = 0x40
+	typeLongString  = 0xbf
+	typeBinary1     = 0xc0
+)
+
+// maxShortStringLen is the longest string stored with the length in the
+// type byte.
+const maxShortStringLen = 126
+
+var errTruncated = errors.New("vpack: unexpected end of data")
+
+// translatedKeys are the attribute names ArangoDB stores as small integers.
+var translatedKeys = [...][]byte{
+	1: []byte("_key"),
+	2: []byte("_rev"),
+	3: []byte("_id"),
+	4: []byte("_from"),
+	5: []byte("_to"),
+}
+
+func readUint(b []byte, n int) uint64 {
+	var v uint64
+	for i := n - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+func putUint(b []byte, v uint64, n int) {
+	for i := 0; i < n; i++ {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+// readVLQ reads a variable length quantity stored from the start of b and
+// returns it with its length.
+func readVLQ(b []byte) (v uint64, n int, err error) {
+	for shift := uint(0); n < len(b) && shift < 64; shift += 7 {
+		c := b[n]
+		n++
+		v |= uint64(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return v, n, nil
+		}
+	}
+	return 0, 0, errTruncated
+}
+
+// readReverseVLQ reads a variable length quantity stored backwards from the
+// end of b.
+func readReverseVLQ(b []byte) (v uint64, n int, err error) {
+	for shift := uint(0); n < len(b) && shift < 64; shift += 7 {
+		c := b[len(b)-1-n]
+		n++
+		v |= uint64(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return v, n, nil
+		}
+	}
+	return 0, 0, errTruncated
+}
+
+// byteSize returns the size of the value at the start of b.
+func byteSize(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, errTruncated
+	}
+	h := b[0]
+	var size uint64
+	switch {
+	case h == typeEmptyArray || h == typeEmptyObject ||
+		h >= typeNull && h <= typeTrue || h == typeMinKey || h == typeMaxKey ||
+		h >= typeSmallInt0 && h < typeShortString:
+		size = 1
+	case h >= 0x02 && h <= 0x09 || h >= 0x0b && h <= 0x12:
+		// Arrays and objects store the byte length after the type byte
+		// in 1, 2, 4 or 8 bytes.
+		w := compoundWidth(h)
+		if len(b) < 1+w {
+			return 0, errTruncated
+		}
+		size = readUint(b[1:], w)
+	case h == 0x13 || h == 0x14:
+		// Compact arrays and objects store the byte length as a VLQ.
+		v, _, err := readVLQ(b[1:])
+		if err != nil {
+			return 0, err
+		}
+		size = v
+	case h == typeDouble || h == typeUTCDate:
+		size = 9
+	case h >= typeInt1 && h < typeUint1:
+		size = 1 + uint64(h-typeInt1+1)
+	case h >= typeUint1 && h < typeSmallInt0:
+		size = 1 + uint64(h-typeUint1+1)
+	case h >= typeShortString && h < typeLongString:
+		size = 1 + uint64(h-typeShortString)
+	case h == typeLongString:
+		if len(b) < 9 {
+			return 0, errTruncated
+		}
+		size = 9 + readUint(b[1:], 8)
+	case h >= typeBinary1 && h <= 0xc7:
+		w := int(h-typeBinary1) + 1
+		if len(b) < 1+w {
+			return 0, errTruncated
+		}
+		size = 1 + uint64(w) + readUint(b[1:], w)
+	default:
+		return 0, fmt.Errorf("vpack: unsupported type 0x%02x", h)
+	}
+	if size == 0 || size > uint64(len(b)) {
+		return 0, errTruncated
+	}
+	return int(size), nil
+}
+
+// compoundWidth returns the width of the byte length, the number of items
+// and the offsets of arrays and objects with the type byte h.
+func compoundWidth(h byte) int {
+	switch {
+	case h >= 0x02 && h <= 0x05:
+		return 1 << (h - 0x02)
+	case h >= 0x06 && h <= 0x09:
+		return 1 << (h - 0x06)
+	case h >= 0x0b && h <= 0x0e:
+		return 1 << (h - 0x0b)
+	default:
+		return 1 << (h - 0x0f)
+	}
+}
+
+func isArray(h byte) bool {
+	return h >= typeEmptyArray && h <= 0x09 || h == 0x13
+}
+
+func isObject(h byte) bool {
+	return h >= typeEmptyObject && h <= 0x12 || h == 0x14
+}
+
+// iterator iterates the values of an array or the keys and values of an
+// object.
+type iterator struct {
+	b      []byte
+	object bool
+	n      int
+	i      int
+	// pos is the position of the next item when the items are read in
+	// sequence, and size is their size if they have the same size.
+	pos  int
+	size int
+	// w is the width of the index table at table. offsets are the sorted
+	// offsets when the items are read in the order they are stored.
+	w       int
+	table   int
+	offsets []int
+}
+
+// newIterator returns an iterator of the array or the object b. If stored is
+// set, the attributes of objects are read in the order they are stored
+// instead of the order of the index table.
+func newIterator(b []byte, stored bool) (it iterator, err error) {
+	h := b[0]
+	it = iterator{b: b, object: isObject(h)}
+	switch {
+	case h == typeEmptyArray || h == typeEmptyObject:
+		return it, nil
+	case h >= 0x02 && h <= 0x05:
+		// The items have the same size and there is no index table. Zero
+		// bytes may pad the header.
+		it.pos = 1 + compoundWidth(h)
+		for it.pos < len(b) && b[it.pos] == typeNone {
+			it.pos++
+		}
+		if it.pos == len(b) {
+			return it, nil
+		}
+		size, err := byteSize(b[it.pos:])
+		if err != nil {
+			return it, err
+		}
+		it.size = size
+		it.n = (len(b) - it.pos) / size
+		return it, nil
+	case h == 0x13 || h == 0x14:
+		// Compact arrays and objects have the number of items as a VLQ
+		// stored backwards at the end.
+		_, vn, err := readVLQ(b[1:])
+		if err != nil {
+			return it, err
+		}
+		n, _, err := readReverseVLQ(b)
+		if err != nil {
+			return it, err
+		}
+		if n > uint64(len(b)) {
+			return it, errTruncated
+		}
+		it.pos = 1 + vn
+		it.n = int(n)
+		return it, nil
+	}
+
+	// The index table is at the end followed by the number of items when
+	// the width is 8 bytes.
+	w := compoundWidth(h)
+	end := len(b)
+	var n uint64
+	if w == 8 {
+		if end < 17 {
+			return it, errTruncated
+		}
+		end -= 8
+		n = readUint(b[end:], 8)
+	} else {
+		if end < 1+2*w {
+			return it, errTruncated
+		}
+		n = readUint(b[1+w:], w)
+	}
+	if n > uint64(end)/uint64(w) {
+		return it, errTruncated
+	}
+	it.n = int(n)
+	it.w = w
+	it.table = end - it.n*w
+	if stored && h >= typeObject1 && h <= 0x0e {
+		it.offsets = make([]int, it.n)
+		for i := range it.offsets {
+			it.offsets[i] = int(readUint(b[it.table+i*w:], w))
+		}
+		sort.Ints(it.offsets)
+	}
+	return it, nil
+}
+
+// next returns the next value and its key if the iterator is for an object.
+// ok is false at the end.
+func (it *iterator) next() (key, value []byte, ok bool, err error) {
+	if it.i >= it.n {
+		return nil, nil, false, nil
+	}
+	var pos int
+	switch {
+	case it.offsets != nil:
+		pos = it.offsets[it.i]
+	case it.w != 0:
+		pos = int(readUint(it.b[it.table+it.i*it.w:], it.w))
+	case it.size != 0:
+		pos = it.pos + it.i*it.size
+		it.i++
+		return nil, it.b[pos : pos+it.size], true, nil
+	default:
+		pos = it.pos
+	}
+	it.i++
+	if pos <= 0 || pos >= len(it.b) {
+		return nil, nil, false, errTruncated
+	}
+	if it.object {
+		size, err := byteSize(it.b[pos:])
+		if err != nil {
+			return nil, nil, false, err
+		}
+		key = it.b[pos : pos+size]
+		pos += size
+	}
+	size, err := byteSize(it.b[pos:])
+	if err != nil {
+		return nil, nil, false, err
+	}
+	value = it.b[pos : pos+size]
+	it.pos = pos + size
+	return key, value, true, nil
+}
+
+// keyBytes returns the attribute name of the object key b.
+func keyBytes(b []byte) ([]byte, error) {
+	h := b[0]
+	switch {
+	case h >= typeShortString && h < typeLongString:
+		return b[1:], nil
+	case h == typeLongString:
+		return b[9:], nil
+	}
+	if n, ok := uintValue(b); ok && n < uint64(len(translatedKeys)) && translatedKeys[n] != nil {
+		return translatedKeys[n], nil
+	}
+	return nil, fmt.Errorf("vpack: invalid object key type 0x%02x", h)
+}
+
+func stringValue(b []byte) (string, bool) {
+	h := b[0]
+	switch {
+	case h >= typeShortString && h < typeLongString:
+		return string(b[1:]), true
+	case h == typeLongString:
+		return string(b[9:]), true
+	}
+	return "", false
+}
+
+func binaryValue(b []byte) ([]byte, bool) {
+	h := b[0]
+	if h >= typeBinary1 && h <= 0xc7 {
+		return b[1+int(h-typeBinary1)+1:], true
+	}
+	return nil, false
+}
+
+func uintValue(b []byte) (uint64, bool) {
+	h := b[0]
+	switch {
+	case h >= typeSmallInt0 && h <= 0x39:
+		return uint64(h - typeSmallInt0), true
+	case h >= typeUint1 && h < typeSmallInt0:
+		return readUint(b[1:], int(h-typeUint1)+1), true
+	}
+	return 0, false
+}
+
+func intValue(b []byte) (int64, bool) {
+	h := b[0]
+	switch {
+	case h >= typeSmallInt0 && h <= 0x39:
+		return int64(h - typeSmallInt0), true
+	case h >= 0x3a && h < typeShortString:
+		return int64(h) - typeShortString, true
+	case h >= typeInt1 && h < typeUint1 || h == typeUTCDate:
+		n := int(h-typeInt1) + 1
+		if h == typeUTCDate {
+			n = 8
+		}
+		v := readUint(b[1:], n)
+		if n < 8 && v&(1<<(8*uint(n)-1)) != 0 {
+			v |= math.MaxUint64 << (8 * uint(n))
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func doubleValue(b []byte) (float64, bool) {
+	if b[0] == typeDouble {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[1:])), true
+	}
+	return 0, false
+}
+
+func isNumber(h byte) bool {
+	return h == typeDouble || h == typeUTCDate || h >= typeInt1 && h < typeShortString
+}
+
+func isNull(h byte) bool {
+	return h == typeNull || h == typeMinKey || h == typeMaxKey
+}
+
+func typeName(h byte) string {
+	switch {
+	case isNull(h):
+		return "null"
+	case h == typeFalse || h == typeTrue:
+		return "bool"
+	case isNumber(h):
+		return "number"
+	case h >= typeShortString && h <= typeLongString:
+		return "string"
+	case h >= typeBinary1 && h <= 0xc7:
+		return "binary"
+	case isArray(h):
+		return "array"
+	case isObject(h):
+		return "object"
+	}
+	return fmt.Sprintf("type 0x%02x", h)
+}
@@ -0,0 +1,543 @@
+package vpack_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/arangogo/vpack"
+)
+
+// fromHex returns the bytes of the space separated hex string s.
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Replace(s, " ", "", -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+type sorted struct {
+	B int `json:"b"`
+	A int `json:"a"`
+}
+
+func TestMarshalGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"null", nil, "18"},
+		{"false", false, "19"},
+		{"true", true, "1a"},
+		{"smallint 0", 0, "30"},
+		{"smallint 9", 9, "39"},
+		{"smallint -1", -1, "3f"},
+		{"smallint -6", -6, "3a"},
+		{"int1 10", 10, "20 0a"},
+		{"int1 -7", -7, "20 f9"},
+		{"int1 127", 127, "20 7f"},
+		{"int1 -128", -128, "20 80"},
+		{"int2 128", 128, "21 80 00"},
+		{"int2 -129", -129, "21 7f ff"},
+		{"int3", 1 << 15, "22 00 80 00"},
+		{"int4", 1 << 23, "23 00 00 80 00"},
+		{"int5", int64(1) << 31, "24 00 00 00 80 00"},
+		{"int6", int64(1) << 39, "25 00 00 00 00 80 00"},
+		{"int7", int64(1) << 47, "26 00 00 00 00 00 80 00"},
+		{"int8", int64(1) << 55, "27 00 00 00 00 00 00 80 00"},
+		{"max int64", int64(math.MaxInt64), "27 ff ff ff ff ff ff ff 7f"},
+		{"min int64", int64(math.MinInt64), "27 00 00 00 00 00 00 00 80"},
+		{"uint in int range", uint(200), "21 c8 00"},
+		{"uint8", uint64(math.MaxUint64), "2f ff ff ff ff ff ff ff ff"},
+		{"double", 1.5, "1b 00 00 00 00 00 00 f8 3f"},
+		{"negative double", -0.25, "1b 00 00 00 00 00 00 d0 bf"},
+		{"empty string", "", "40"},
+		{"short string", "abc", "43 61 62 63"},
+		{"bytes as base64", []byte{1, 2, 3}, "44 41 51 49 44"},
+		{"nil slice", []int(nil), "18"},
+		{"empty array", []int{}, "01"},
+		{"array", []int{1, 2, 3}, "06 09 03 31 32 33 03 04 05"},
+		{"nested", []interface{}{[]int{}, map[string]int{}}, "06 07 02 01 0a 03 04"},
+		{"nil map", map[string]int(nil), "18"},
+		{"empty object", map[string]int{}, "0a"},
+		{"object", map[string]int{"b": 2, "a": 1}, "0b 0b 02 41 61 31 41 62 32 03 06"},
+		{"struct in field order with a sorted index", sorted{B: 1, A: 2}, "0b 0b 02 41 62 31 41 61 32 06 03"},
+		{"quoted number", struct {
+			N int `json:"n,string"`
+		}{42}, "0b 09 01 41 6e 42 34 32 03"},
+		{"raw message", json.RawMessage(`{"a":[1]}`), "0b 0b 01 41 61 06 05 01 31 03 03"},
+	}
+	for _, tt := range tests {
+		got, err := vpack.Marshal(tt.value)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if want := fromHex(t, tt.want); !bytes.Equal(got, want) {
+			t.Errorf("%s: got % x, want % x", tt.name, got, want)
+		}
+	}
+}
+
+// TestMarshalWidths checks that arrays, objects and strings use the
+// narrowest header their byte length fits in.
+func TestMarshalWidths(t *testing.T) {
+	ones := make([]int, 300)
+	for i := range ones {
+		ones[i] = 1
+	}
+	long := strings.Repeat("x", 70000)
+	tests := []struct {
+		name   string
+		value  interface{}
+		size   int
+		header string
+		index  string
+	}{
+		{"long string", strings.Repeat("x", 127), 136, "bf 7f 00 00 00 00 00 00 00", ""},
+		{"short string", strings.Repeat("x", 126), 127, "be", ""},
+		{"array with 2 byte widths", ones, 905, "07 89 03 2c 01 31", "2f 01 30 01"},
+		{"array with 4 byte widths", []string{long}, 70022, "08 86 11 01 00 01 00 00 00 bf", "09 00 00 00"},
+		{"object with 2 byte widths", map[string][]int{"a": ones}, 914, "0c 92 03 01 00 41 61 07", "05 00"},
+		{"object with 4 byte widths", map[string]string{"a": long}, 70024, "0d 88 11 01 00 01 00 00 00 41 61 bf", "09 00 00 00"},
+	}
+	for _, tt := range tests {
+		got, err := vpack.Marshal(tt.value)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != tt.size {
+			t.Errorf("%s: size = %d, want %d", tt.name, len(got), tt.size)
+			continue
+		}
+		if header := fromHex(t, tt.header); !bytes.HasPrefix(got, header) {
+			t.Errorf("%s: header = % x, want % x", tt.name, got[:len(header)], header)
+		}
+		if index := fromHex(t, tt.index); tt.index != "" && !bytes.HasSuffix(got, index) {
+			t.Errorf("%s: index = % x, want % x", tt.name, got[len(got)-len(index):], index)
+		}
+	}
+}
+
+func TestUnmarshalGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		json string
+	}{
+		{"null", "18", "null"},
+		{"min key", "1e", "null"},
+		{"max key", "1f", "null"},
+		{"false", "19", "false"},
+		{"true", "1a", "true"},
+		{"smallint 0", "30", "0"},
+		{"smallint 9", "39", "9"},
+		{"smallint -6", "3a", "-6"},
+		{"smallint -1", "3f", "-1"},
+		{"int1", "20 ff", "-1"},
+		{"int2", "21 00 80", "-32768"},
+		{"int3", "22 00 00 80", "-8388608"},
+		{"int4", "23 00 00 00 80", "-2147483648"},
+		{"int5", "24 00 00 00 00 80", "-549755813888"},
+		{"int6", "25 00 00 00 00 00 80", "-140737488355328"},
+		{"int7", "26 00 00 00 00 00 00 80", "-36028797018963968"},
+		{"int8", "27 00 00 00 00 00 00 00 80", "-9223372036854775808"},
+		{"positive int8", "27 ff ff ff ff ff ff ff 7f", "9223372036854775807"},
+		{"uint1", "28 ff", "255"},
+		{"uint2", "29 ff ff", "65535"},
+		{"uint3", "2a ff ff ff", "16777215"},
+		{"uint4", "2b ff ff ff ff", "4294967295"},
+		{"uint5", "2c ff ff ff ff ff", "1099511627775"},
+		{"uint6", "2d ff ff ff ff ff ff", "281474976710655"},
+		{"uint7", "2e ff ff ff ff ff ff ff", "72057594037927935"},
+		{"uint8", "2f ff ff ff ff ff ff ff ff", "18446744073709551615"},
+		{"double", "1b 00 00 00 00 00 00 f8 3f", "1.5"},
+		{"large double", "1b 9c 75 00 88 3c e4 37 7e", "1e+300"},
+		{"utc date", "1c 88 cc 35 64 6f 01 00 00", "1577934245000"},
+		{"utc date before the epoch", "1c 18 fc ff ff ff ff ff ff", "-1000"},
+		{"short string", "43 61 62 63", `"abc"`},
+		{"long string", "bf 03 00 00 00 00 00 00 00 61 62 63", `"abc"`},
+		{"empty array", "01", "[]"},
+		{"empty object", "0a", "{}"},
+
+		{"array 0x02", "02 05 31 32 33", "[1,2,3]"},
+		{"array 0x02 with multi byte items", "02 08 20 0a 20 0b 20 0c", "[10,11,12]"},
+		{"array 0x03", "03 06 00 31 32 33", "[1,2,3]"},
+		{"array 0x04", "04 08 00 00 00 31 32 33", "[1,2,3]"},
+		{"array 0x05", "05 0c 00 00 00 00 00 00 00 31 32 33", "[1,2,3]"},
+		{"array 0x03 with a padded header", "03 0c 00 00 00 00 00 00 00 31 32 33", "[1,2,3]"},
+
+		{"array 0x06", "06 09 03 31 32 33 03 04 05", "[1,2,3]"},
+		{"array 0x06 with a padded header", "06 0f 03 00 00 00 00 00 00 31 32 33 09 0a 0b", "[1,2,3]"},
+		{"array 0x07", "07 0e 00 03 00 31 32 33 05 00 06 00 07 00", "[1,2,3]"},
+		{"array 0x08", "08 18 00 00 00 03 00 00 00 31 32 33 09 00 00 00 0a 00 00 00 0b 00 00 00", "[1,2,3]"},
+		{"array 0x09", "09 23 00 00 00 00 00 00 00 31 32 09 00 00 00 00 00 00 00 0a 00 00 00 00 00 00 00 02 00 00 00 00 00 00 00", "[1,2]"},
+
+		{"object 0x0b", "0b 0b 02 41 61 31 41 62 32 03 06", `{"a":1,"b":2}`},
+		{"object 0x0b in stored order", "0b 0b 02 41 62 31 41 61 32 06 03", `{"b":1,"a":2}`},
+		{"object 0x0b with a padded header", "0b 0d 01 00 00 00 00 00 00 41 61 31 09", `{"a":1}`},
+		{"object 0x0c", "0c 0a 00 01 00 41 61 31 05 00", `{"a":1}`},
+		{"object 0x0d", "0d 10 00 00 00 01 00 00 00 41 61 31 09 00 00 00", `{"a":1}`},
+		{"object 0x0e", "0e 1c 00 00 00 00 00 00 00 41 61 31 09 00 00 00 00 00 00 00 01 00 00 00 00 00 00 00", `{"a":1}`},
+		{"unsorted object 0x0f", "0f 0b 02 41 62 31 41 61 32 03 06", `{"b":1,"a":2}`},
+
+		{"compact array", "13 06 31 28 10 02", "[1,16]"},
+		{"compact object", "14 0a 41 61 28 0c 41 62 1a 02", `{"a":12,"b":true}`},
+		{"translated keys", "0b 12 05 31 30 32 31 33 32 34 33 35 34 03 05 07 09 0b", `{"_key":0,"_rev":1,"_id":2,"_from":3,"_to":4}`},
+		{"translated key as uint", "0b 07 01 28 01 30 03", `{"_key":0}`},
+	}
+	for _, tt := range tests {
+		data := fromHex(t, tt.data)
+		j, err := vpack.ToJSON(data)
+		if err != nil {
+			t.Errorf("%s: ToJSON: %v", tt.name, err)
+		} else if string(j) != tt.json {
+			t.Errorf("%s: ToJSON = %s, want %s", tt.name, j, tt.json)
+		}
+
+		var got, want interface{}
+		if err := json.Unmarshal([]byte(tt.json), &want); err != nil {
+			t.Fatal(err)
+		}
+		if err := vpack.Unmarshal(data, &got); err != nil {
+			t.Errorf("%s: Unmarshal: %v", tt.name, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Unmarshal = %#v, want %#v", tt.name, got, want)
+		}
+	}
+}
+
+func TestUnmarshalCompactWithMultiByteLengths(t *testing.T) {
+	// 130 items need 2 byte VLQs for both the byte length and the number
+	// of items, which is stored backwards.
+	data := append(fromHex(t, "13 87 01"), bytes.Repeat([]byte{0x31}, 130)...)
+	data = append(data, 0x01, 0x82)
+	var got []int
+	if err := vpack.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 130 || got[0] != 1 || got[129] != 1 {
+		t.Errorf("got %d items: %v", len(got), got)
+	}
+}
+
+func TestUnmarshalIntegers(t *testing.T) {
+	ints := []struct {
+		data string
+		want int64
+	}{
+		{"3a", -6},
+		{"39", 9},
+		{"20 80", -128},
+		{"21 ff 7f", 32767},
+		{"22 ff ff 7f", 1<<23 - 1},
+		{"23 00 00 00 80", math.MinInt32},
+		{"24 ff ff ff ff 7f", 1<<39 - 1},
+		{"25 00 00 00 00 00 80", -1 << 47},
+		{"26 ff ff ff ff ff ff 7f", 1<<55 - 1},
+		{"27 00 00 00 00 00 00 00 80", math.MinInt64},
+		{"2b ff ff ff ff", math.MaxUint32},
+		{"2f ff ff ff ff ff ff ff 7f", math.MaxInt64},
+		{"1c 88 cc 35 64 6f 01 00 00", 1577934245000},
+	}
+	for _, tt := range ints {
+		var got int64
+		if err := vpack.Unmarshal(fromHex(t, tt.data), &got); err != nil || got != tt.want {
+			t.Errorf("%s: got %d, %v, want %d", tt.data, got, err, tt.want)
+		}
+	}
+
+	uints := []struct {
+		data string
+		want uint64
+	}{
+		{"28 80", 128},
+		{"29 00 80", 1 << 15},
+		{"2a 00 00 80", 1 << 23},
+		{"2b 00 00 00 80", 1 << 31},
+		{"2c 00 00 00 00 80", 1 << 39},
+		{"2d 00 00 00 00 00 80", 1 << 47},
+		{"2e 00 00 00 00 00 00 80", 1 << 55},
+		{"2f 00 00 00 00 00 00 00 80", 1 << 63},
+		{"1b 00 00 00 00 00 00 f0 3f", 1},
+	}
+	for _, tt := range uints {
+		var got uint64
+		if err := vpack.Unmarshal(fromHex(t, tt.data), &got); err != nil || got != tt.want {
+			t.Errorf("%s: got %d, %v, want %d", tt.data, got, err, tt.want)
+		}
+	}
+
+	for _, i := range []int64{0, 9, 10, -1, -6, -7, 127, 128, -128, -129, 1 << 40, -1 << 62, math.MaxInt64, math.MinInt64} {
+		b, err := vpack.Marshal(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got int64
+		if err := vpack.Unmarshal(b, &got); err != nil || got != i {
+			t.Errorf("%d: got %d, %v from % x", i, got, err, b)
+		}
+	}
+}
+
+func TestUnmarshalOverflow(t *testing.T) {
+	tests := []struct {
+		data string
+		v    interface{}
+	}{
+		{"21 00 01", new(int8)},
+		{"3f", new(uint)},
+		{"2f 00 00 00 00 00 00 00 80", new(int64)},
+		{"1b 00 00 00 00 00 00 f8 3f", new(int)},
+		{"43 61 62 63", new(int)},
+	}
+	for _, tt := range tests {
+		err := vpack.Unmarshal(fromHex(t, tt.data), tt.v)
+		if _, ok := err.(*vpack.UnmarshalTypeError); !ok {
+			t.Errorf("%s into %T: err = %v, want an UnmarshalTypeError", tt.data, tt.v, err)
+		}
+	}
+}
+
+type document struct {
+	Key  string `json:"_key"`
+	Rev  string `json:"_rev"`
+	ID   string `json:"_id"`
+	From string `json:"_from"`
+	To   string `json:"_to"`
+}
+
+func TestUnmarshalTranslatedKeysIntoStruct(t *testing.T) {
+	// 0x31 to 0x35 are _key, _rev, _id, _from and _to.
+	data := fromHex(t, "0b 17 05 31 41 6b 32 41 72 33 41 69 34 41 66 35 41 74 03 06 09 0c 0f")
+	var got document
+	if err := vpack.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (document{Key: "k", Rev: "r", ID: "i", From: "f", To: "t"}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	var m map[string]interface{}
+	if err := vpack.Unmarshal(fromHex(t, "0b 06 01 36 30 03"), &m); err == nil {
+		t.Errorf("got no error for the untranslated key 0x36: %v", m)
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	for _, data := range []string{"c0 03 01 02 03", "c1 03 00 01 02 03"} {
+		b := fromHex(t, data)
+		var got []byte
+		if err := vpack.Unmarshal(b, &got); err != nil || !bytes.Equal(got, []byte{1, 2, 3}) {
+			t.Errorf("%s: got %v, %v", data, got, err)
+		}
+		if j, err := vpack.ToJSON(b); err != nil || string(j) != `"AQID"` {
+			t.Errorf("%s: ToJSON = %s, %v", data, j, err)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"0b 00",
+		"02 03 0b",
+		"06 05 02 31 03",
+		"13 05 31",
+		"bf 01",
+		"20",
+		"1b 00 00",
+		"c0 05 01",
+		"31 32",
+		"e0",
+	}
+	for _, data := range tests {
+		var v interface{}
+		if err := vpack.Unmarshal(fromHex(t, data), &v); err == nil {
+			t.Errorf("%q: got no error, value %v", data, v)
+		}
+	}
+
+	var i int
+	if err := vpack.Unmarshal([]byte{0x31}, i); err == nil {
+		t.Error("got no error for a non-pointer")
+	}
+
+	b, err := vpack.Marshal(map[string]interface{}{"age": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Age int `json:"age"`
+	}
+	err = vpack.Unmarshal(b, &doc)
+	if te, ok := err.(*vpack.UnmarshalTypeError); !ok || !strings.HasSuffix(te.Field, ".age") {
+		t.Errorf("err = %v, want an UnmarshalTypeError for the age field", err)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	for _, v := range []interface{}{math.NaN(), math.Inf(1), make(chan int), map[bool]int{true: 1}} {
+		if b, err := vpack.Marshal(v); err == nil {
+			t.Errorf("%T: got no error, % x", v, b)
+		}
+	}
+}
+
+type base struct {
+	Key string `json:"_key,omitempty"`
+	Rev string `json:"_rev,omitempty"`
+}
+
+type roundTrip struct {
+	base
+	Name   string            `json:"name"`
+	Age    int               `json:"age,omitempty"`
+	N      int64             `json:"n,string"`
+	F      float64           `json:"f"`
+	B      []byte            `json:"b"`
+	Tags   []string          `json:"tags"`
+	M      map[string]int    `json:"m"`
+	IntKey map[int]string    `json:"intKey"`
+	P      *int              `json:"p"`
+	Any    interface{}       `json:"any"`
+	Raw    json.RawMessage   `json:"raw"`
+	T      time.Time         `json:"t"`
+	U      uint64            `json:"u"`
+	Neg    int8              `json:"neg"`
+	Skip   string            `json:"-"`
+	Arr    [2]int            `json:"arr"`
+	Nested map[string][]base `json:"nested"`
+	Long   string            `json:"long"`
+}
+
+func newRoundTrip() roundTrip {
+	p := 5
+	many := make([]base, 100)
+	for i := range many {
+		many[i] = base{Key: strings.Repeat("k", i)}
+	}
+	return roundTrip{
+		base:   base{Key: "k", Rev: "r"},
+		Name:   "n\"\x01é",
+		Age:    3,
+		N:      42,
+		F:      1.5,
+		B:      []byte{1, 2, 3},
+		Tags:   []string{"a", "b"},
+		M:      map[string]int{"z": -1000, "a": 70000},
+		IntKey: map[int]string{-1: "x", 2: "y"},
+		P:      &p,
+		Any:    map[string]interface{}{"x": []interface{}{1.0, "s", nil, true}},
+		Raw:    json.RawMessage(`{"q":[1,2]}`),
+		T:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		U:      math.MaxUint64,
+		Neg:    -5,
+		Arr:    [2]int{7, 8},
+		Nested: map[string][]base{"many": many},
+		Long:   strings.Repeat("x", 300),
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := newRoundTrip()
+	b, err := vpack.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out roundTrip
+	if err := vpack.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got\n%#v\nwant\n%#v", out, in)
+	}
+
+	in.Skip = "skipped"
+	if b2, err := vpack.Marshal(in); err != nil || !bytes.Equal(b, b2) {
+		t.Errorf("Marshal of a value differs from a pointer, err = %v", err)
+	}
+
+	nb, err := vpack.Marshal(map[string]interface{}{"p": nil, "tags": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Skip = "kept"
+	if err := vpack.Unmarshal(nb, &out); err != nil || out.P != nil || out.Tags != nil || out.Skip != "kept" {
+		t.Errorf("null fields: err = %v, got %+v", err, out)
+	}
+}
+
+// TestJSONRoundTrip checks that ToJSON and FromJSON agree with
+// encoding/json.
+func TestJSONRoundTrip(t *testing.T) {
+	in := newRoundTrip()
+	b, err := vpack.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := vpack.ToJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromVPack roundTrip
+	if err := json.Unmarshal(j, &fromVPack); err != nil {
+		t.Fatalf("%v: %s", err, j)
+	}
+	if !reflect.DeepEqual(fromVPack, in) {
+		t.Errorf("ToJSON: got\n%#v\nwant\n%#v", fromVPack, in)
+	}
+
+	jb, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vb, err := vpack.FromJSON(jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(vb, b) {
+		var fromJSON roundTrip
+		if err := vpack.Unmarshal(vb, &fromJSON); err != nil || !reflect.DeepEqual(fromJSON, in) {
+			t.Errorf("FromJSON: err = %v, got\n%#v\nwant\n%#v", err, fromJSON, in)
+		}
+	}
+
+	var generic, want interface{}
+	if err := vpack.Unmarshal(b, &generic); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(jb, &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(generic, want) {
+		t.Errorf("generic: got\n%v\nwant\n%v", generic, want)
+	}
+
+	if _, err := vpack.FromJSON([]byte(`{"a":`)); err == nil {
+		t.Error("got no error for invalid JSON")
+	}
+}
+
+func TestToJSONEscapes(t *testing.T) {
+	b, err := vpack.Marshal("\"\\\n\r\t\x01é\xff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := vpack.ToJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"\"\\\n\r\t\u0001é` + "�" + `"`; string(j) != want {
+		t.Errorf("got %s, want %s", j, want)
+	}
+}